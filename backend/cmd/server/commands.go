@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/backup"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// runCommand runs a maintenance subcommand instead of the server:
+//
+//	server backup -user <id> [-o file.tar.gz]
+//	server restore -user <id> [-i file.tar.gz] [-remap]
+func runCommand(ctx context.Context, svc service.TodoService, name string, args []string) error {
+	switch name {
+	case "backup":
+		return runBackup(ctx, svc, args)
+	case "restore":
+		return runRestore(ctx, svc, args)
+	default:
+		return fmt.Errorf("unknown command %q, expected backup or restore", name)
+	}
+}
+
+func runBackup(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	user := fs.String("user", "", "ID of the user to back up")
+	out := fs.String("o", "-", "archive to write, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(*user)
+	if err != nil {
+		return errors.New("-user needs a valid user ID")
+	}
+
+	data, err := svc.BackupUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if *out == "-" {
+		return backup.Write(os.Stdout, data, time.Now())
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := backup.Write(f, data, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runRestore(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	user := fs.String("user", "", "ID of the user to restore into")
+	in := fs.String("i", "-", "archive to read, - for stdin")
+	remap := fs.Bool("remap", false, "give every record a new ID instead of keeping the archived ones")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(*user)
+	if err != nil {
+		return errors.New("-user needs a valid user ID")
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, manifest, err := backup.Read(r)
+	if err != nil {
+		return err
+	}
+	result, err := svc.RestoreUser(ctx, userID, data, *remap)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "restored backup of %s from %s\n", manifest.UserID, manifest.CreatedAt.Format(time.RFC3339))
+	return json.NewEncoder(os.Stdout).Encode(result)
+}
@@ -2,54 +2,252 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
 	"github.com/awnzl/to-do-app/db"
 	"github.com/awnzl/to-do-app/internal/api"
+	"github.com/awnzl/to-do-app/internal/health"
+	"github.com/awnzl/to-do-app/internal/jobs"
+	"github.com/awnzl/to-do-app/internal/logging"
+	"github.com/awnzl/to-do-app/internal/metrics"
+	"github.com/awnzl/to-do-app/internal/notify"
+	"github.com/awnzl/to-do-app/internal/repository"
 	"github.com/awnzl/to-do-app/internal/repository/postgres"
 	"github.com/awnzl/to-do-app/internal/service"
+	"github.com/awnzl/to-do-app/internal/storage"
+	"github.com/awnzl/to-do-app/internal/tracing"
 )
 
+// migrationsPath is the directory the migrations are read from.
+const migrationsPath = "./migrations"
+
 func main() {
+	logger, err := logging.New(os.Stdout, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "set up logging:", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	// the first SIGTERM or interrupt shuts the server down gracefully, a
+	// second one kills it
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
 	cfg, err := getDBConfig()
 	if err != nil {
-		log.Fatalln("get db config", err)
+		fatal("get db config", err)
+	}
+
+	serverCfg, err := getServerConfig()
+	if err != nil {
+		fatal("get server config", err)
 	}
 
+	shutdownTracing, err := tracing.Setup(ctx)
+	if err != nil {
+		fatal("set up tracing", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	connectedDB, err := db.NewConnection(cfg)
 	if err != nil {
-		log.Fatalln("connect to the db", err)
+		fatal("connect to the db", err)
+	}
+
+	if err := db.MigrateWithLock(ctx, connectedDB, cfg.MigrateURL(), migrationsPath); err != nil {
+		fatal("run migrations", err)
+	}
+
+	repo := postgres.NewTodoRepo(connectedDB)
+
+	blobs, err := setupBlobStore()
+	if err != nil {
+		fatal("set up blob store", err)
 	}
 
-	if err := db.MigrateWithLock(context.Background(), connectedDB, cfg.MigrateURL(), "./migrations"); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	m := metrics.New(connectedDB.DB, repo)
+	todoService := setupService(connectedDB, repo, blobs, m)
+
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, todoService, os.Args[1], os.Args[2:]); err != nil {
+			fatal(os.Args[1], err)
+		}
+		return
 	}
 
-	router := setupAPI(connectedDB)
+	notifier, err := setupNotifier(repo)
+	if err != nil {
+		fatal("set up notifier", err)
+	}
+	checker, err := setupChecker(connectedDB)
+	if err != nil {
+		fatal("set up health checks", err)
+	}
 
-	log.Printf("Starting server on :8080")
-	log.Fatal(http.ListenAndServe(":8080", router))
+	// the stages stop in this order: the jobs, then the workers that
+	// enqueue notifications, and the notifier last so that it sends all
+	// they enqueued
+	stages := []*stage{
+		startStage(checker, "jobs",
+			worker{"imports", jobs.NewImportWorker(todoService, 2*time.Second).Run},
+			worker{"blob_cleaner", jobs.NewBlobCleaner(todoService, 30*time.Second).Run},
+			worker{"archiver", jobs.NewArchiver(todoService, time.Hour).Run},
+		),
+		startStage(checker, "notification producers",
+			worker{"digests", func(ctx context.Context) { notifier.RunDigests(ctx, time.Minute) }},
+			worker{"events", func(ctx context.Context) { notifier.RunEvents(ctx, 5*time.Second) }},
+		),
+		startStage(checker, "notifier", worker{"notifier", notifier.Run}).withDrain(notifier.Drain),
+	}
+
+	router := api.NewRouter(service.Instrument(todoService), tracing.Middleware, m.Middleware)
+	apiServer := newServer(serverCfg.Addr, router, serverCfg)
+	adminServer := newServer(adminAddr(), adminHandler(m, checker), serverCfg)
+
+	listenErr := make(chan error, 2)
+	go listen("server", apiServer, listenErr)
+	go listen("admin server", adminServer, listenErr)
+
+	select {
+	case <-ctx.Done():
+	case err = <-listenErr:
+	}
+	stop()
+	slog.Info("shutting down", "timeout", serverCfg.ShutdownTimeout)
+
+	if shutdown(serverCfg.ShutdownTimeout, checker, apiServer, adminServer, stages...) {
+		if err := connectedDB.Close(); err != nil {
+			slog.Error("close the db", "error", err)
+		}
+	} else {
+		// the workers still running are cut off when the process exits
+		slog.Warn("leaving the db open for background workers that did not stop in time")
+	}
+	if err != nil {
+		// fatal skips the deferred flush
+		shutdownTracing(context.Background())
+		fatal("serve", err)
+	}
+	slog.Info("server stopped")
 }
 
-func setupAPI(db *sqlx.DB) chi.Router {
-	// Initialize repository
-	repo := postgres.NewTodoRepo(db)
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
 
+func setupService(
+	db *sqlx.DB, repo repository.Repository, blobs storage.BlobStore, txObserver repository.TxObserver,
+) service.TodoService {
 	// Initialize transaction manager
-	txManager := postgres.NewTxManager(db)
+	txManager := postgres.NewTxManager(db, txObserver)
 
 	// Initialize service
-	todoService := service.NewTodoService(repo, txManager)
+	return service.NewTodoService(repo, txManager, blobs)
+}
+
+// setupChecker checks the readiness of the server against the latest
+// migration it ships with.
+func setupChecker(conn *sqlx.DB) (*health.Checker, error) {
+	latest, err := db.LatestMigration(migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+	version := func(ctx context.Context) (uint, bool, error) {
+		return db.MigrationVersion(ctx, conn)
+	}
+	return health.NewChecker(conn, version, latest), nil
+}
 
-	// Create router
-	return api.NewRouter(todoService)
+// adminHandler serves the metrics and health checks on a listener of their
+// own, so that they need not be exposed with the API.
+func adminHandler(m *metrics.Metrics, checker *health.Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.HandleFunc("GET /healthz", checker.Live)
+	mux.HandleFunc("GET /readyz", checker.Ready)
+	return mux
+}
+
+// adminAddr is ADMIN_ADDR, or :9090 when it is not set.
+func adminAddr() string {
+	if addr := os.Getenv("ADMIN_ADDR"); addr != "" {
+		return addr
+	}
+	return ":9090"
+}
+
+// setupBlobStore keeps attachments in BLOB_DIR unless BLOB_STORE=s3 selects
+// an S3 compatible service.
+func setupBlobStore() (storage.BlobStore, error) {
+	switch store := os.Getenv("BLOB_STORE"); store {
+	case "", "fs":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "./data/attachments"
+		}
+		return storage.NewFSStore(dir)
+	case "s3":
+		return storage.NewS3Store(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", store)
+	}
+}
+
+func setupNotifier(repo repository.Repository) (*notify.Notifier, error) {
+	templates, err := notify.LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var sender notify.Sender = notify.LogSender{}
+	cfg, ok, err := getSMTPConfig()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		sender = notify.NewSMTPSender(cfg)
+	} else {
+		slog.Warn("SMTP_HOST is not set, notifications will only be logged")
+	}
+
+	return notify.NewNotifier(repo, templates, sender, 256), nil
+}
+
+func getSMTPConfig() (notify.SMTPConfig, bool, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return notify.SMTPConfig{}, false, nil
+	}
+	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil {
+		return notify.SMTPConfig{}, false, err
+	}
+	return notify.SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USER"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}, true, nil
 }
 
 func getDBConfig() (db.Config, error) {
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/health"
+)
+
+type serverConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds the time taken to drain requests and stop the
+	// background workers.
+	ShutdownTimeout time.Duration
+}
+
+// getServerConfig reads the listen address from SERVER_HOST and SERVER_PORT
+// and the timeouts, as durations such as "30s", from SERVER_*_TIMEOUT and
+// SHUTDOWN_TIMEOUT.
+func getServerConfig() (serverConfig, error) {
+	port := os.Getenv("SERVER_PORT")
+	if port == "" {
+		port = "8080"
+	}
+	cfg := serverConfig{Addr: net.JoinHostPort(os.Getenv("SERVER_HOST"), port)}
+
+	timeouts := []struct {
+		env string
+		def time.Duration
+		dst *time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", 30 * time.Second, &cfg.ReadTimeout},
+		{"SERVER_READ_HEADER_TIMEOUT", 5 * time.Second, &cfg.ReadHeaderTimeout},
+		{"SERVER_WRITE_TIMEOUT", 60 * time.Second, &cfg.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", 120 * time.Second, &cfg.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", 30 * time.Second, &cfg.ShutdownTimeout},
+	}
+	for _, t := range timeouts {
+		*t.dst = t.def
+		s := os.Getenv(t.env)
+		if s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return serverConfig{}, fmt.Errorf("invalid %s %q", t.env, s)
+		}
+		*t.dst = d
+	}
+	return cfg, nil
+}
+
+// newServer serves handler on addr with the timeouts of cfg.
+func newServer(addr string, handler http.Handler, cfg serverConfig) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	}
+}
+
+// listen serves srv until it is shut down. Any other failure is sent to
+// errc.
+func listen(name string, srv *http.Server, errc chan<- error) {
+	slog.Info("starting "+name, "addr", srv.Addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		errc <- fmt.Errorf("%s: %w", name, err)
+	}
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// stage is a group of background workers that are stopped together.
+type stage struct {
+	name   string
+	cancel context.CancelFunc
+	done   []<-chan struct{}
+	drain  func(ctx context.Context) error
+}
+
+// startStage runs workers, reporting their state to checker.
+func startStage(checker *health.Checker, name string, workers ...worker) *stage {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &stage{name: name, cancel: cancel}
+	for _, w := range workers {
+		s.done = append(s.done, checker.Go(ctx, w.name, w.run))
+	}
+	return s
+}
+
+// withDrain makes s finish the work its workers leave behind with drain
+// once they have returned.
+func (s *stage) withDrain(drain func(ctx context.Context) error) *stage {
+	s.drain = drain
+	return s
+}
+
+// stop cancels the workers of s, waits for them to return and drains what
+// they left, all before ctx is done.
+func (s *stage) stop(ctx context.Context) error {
+	s.cancel()
+	for _, done := range s.done {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if s.drain != nil {
+		if err := s.drain(ctx); err != nil {
+			return fmt.Errorf("drain: %w", err)
+		}
+	}
+	return nil
+}
+
+// returned reports whether all workers of s have returned.
+func (s *stage) returned() bool {
+	for _, done := range s.done {
+		select {
+		case <-done:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// shutdown stops the server within timeout. It reports not ready first,
+// then stops accepting API requests and waits for those in flight, stops
+// the background workers stage by stage and the admin listener last, so
+// that probes and metrics are served until the end. It reports whether all
+// workers have returned, and so no longer use the database.
+func shutdown(
+	timeout time.Duration, checker *health.Checker, api, admin *http.Server, stages ...*stage,
+) (stopped bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	checker.BeginShutdown()
+	if err := api.Shutdown(ctx); err != nil {
+		slog.Error("drain api requests", "error", err)
+		api.Close()
+	}
+	stopped = true
+	for _, s := range stages {
+		if err := s.stop(ctx); err != nil {
+			slog.Error("stop background workers", "stage", s.name, "error", err)
+		}
+		stopped = stopped && s.returned()
+	}
+	if err := admin.Shutdown(ctx); err != nil {
+		admin.Close()
+	}
+	return stopped
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/health"
+)
+
+func TestGetServerConfig(t *testing.T) {
+	t.Setenv("SERVER_HOST", "")
+	t.Setenv("SERVER_PORT", "")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "")
+
+	cfg, err := getServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Addr)
+	assert.Equal(t, 60*time.Second, cfg.WriteTimeout)
+
+	t.Setenv("SERVER_HOST", "0.0.0.0")
+	t.Setenv("SERVER_PORT", "8081")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "2m")
+
+	cfg, err = getServerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:8081", cfg.Addr)
+	assert.Equal(t, 2*time.Minute, cfg.WriteTimeout)
+	assert.Equal(t, 5*time.Second, cfg.ReadHeaderTimeout)
+
+	t.Setenv("SERVER_WRITE_TIMEOUT", "60")
+	_, err = getServerConfig()
+	assert.Error(t, err)
+}
+
+func TestStageStop(t *testing.T) {
+	checker := health.NewChecker(nil, nil, 0)
+	stopped := make(chan string, 2)
+	s := startStage(checker, "jobs",
+		worker{"a", func(ctx context.Context) { <-ctx.Done(); stopped <- "a" }},
+		worker{"b", func(ctx context.Context) { <-ctx.Done(); stopped <- "b" }},
+	)
+
+	require.NoError(t, s.stop(context.Background()))
+	assert.Len(t, stopped, 2)
+	assert.True(t, s.returned())
+
+	stuck := startStage(checker, "stuck", worker{"c", func(context.Context) { select {} }})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, stuck.stop(ctx), context.DeadlineExceeded)
+	assert.False(t, stuck.returned())
+}
+
+func TestStageStopDrainsAfterWorkersReturn(t *testing.T) {
+	checker := health.NewChecker(nil, nil, 0)
+	var order []string
+	s := startStage(checker, "notifier",
+		worker{"notifier", func(ctx context.Context) { <-ctx.Done(); order = append(order, "returned") }},
+	).withDrain(func(ctx context.Context) error {
+		order = append(order, "drained")
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, s.stop(ctx))
+	assert.Equal(t, []string{"returned", "drained"}, order)
+
+	cancel()
+	assert.ErrorIs(t, s.stop(ctx), context.Canceled)
+	assert.True(t, s.returned())
+}
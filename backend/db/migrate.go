@@ -2,13 +2,16 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
 )
@@ -40,7 +43,7 @@ func MigrateWithLock(ctx context.Context, db *sqlx.DB, dsn, migrationsPath strin
 	}
 
 	if !locked {
-		log.Println("Another migration is in progress, waiting...")
+		slog.InfoContext(ctx, "another migration is in progress, waiting")
 		// Wait for lock with timeout
 		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 		defer cancel()
@@ -59,3 +62,46 @@ func MigrateWithLock(ctx context.Context, db *sqlx.DB, dsn, migrationsPath strin
 	dbURL := db.DriverName() + "://" + dsn
 	return RunMigrations(dbURL, migrationsPath)
 }
+
+// MigrationVersion returns the version the schema is migrated to, as
+// recorded by golang-migrate, and whether the migration to it failed
+// halfway and left the schema dirty. It is 0 before the first migration.
+func MigrationVersion(ctx context.Context, db *sqlx.DB) (version uint, dirty bool, err error) {
+	var state struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err = db.GetContext(ctx, &state, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	return uint(state.Version), state.Dirty, nil
+}
+
+// LatestMigration returns the version of the last migration in
+// migrationsPath.
+func LatestMigration(migrationsPath string) (uint, error) {
+	src, err := source.Open(fmt.Sprintf("file://%s", migrationsPath))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open migrations: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
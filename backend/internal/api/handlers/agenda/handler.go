@@ -0,0 +1,42 @@
+package agenda
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.Get)
+}
+
+// Get returns the caller's open todos grouped by when they need attention.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	agenda, err := h.svc.Agenda(r.Context())
+	switch {
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(agenda)
+}
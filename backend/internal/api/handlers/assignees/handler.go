@@ -0,0 +1,106 @@
+package assignees
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes registers assigning and unassigning users to a todo.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Put("/{userID}", h.Assign)
+	r.Delete("/{userID}", h.Unassign)
+}
+
+// RegisterUserRoutes registers the todos assigned to a user.
+func (h *Handler) RegisterUserRoutes(r chi.Router) {
+	r.Get("/", h.ListAssigned)
+}
+
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	todoID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AssignTodo(r.Context(), todoID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Unassign(w http.ResponseWriter, r *http.Request) {
+	todoID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UnassignTodo(r.Context(), todoID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAssigned returns the todos assigned to a user across all lists they
+// can access.
+func (h *Handler) ListAssigned(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	todos, err := h.svc.ListAssignedTodos(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(todos)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (todoID, userID uuid.UUID, ok bool) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return todoID, userID, false
+	}
+	userID, err = uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return todoID, userID, false
+	}
+	return todoID, userID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTodoNotFound),
+		errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrNoListAccess):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
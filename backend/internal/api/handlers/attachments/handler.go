@@ -0,0 +1,179 @@
+package attachments
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+	"github.com/awnzl/to-do-app/internal/storage"
+)
+
+// multipartOverhead leaves room for the multipart framing and form fields
+// around the file itself.
+const multipartOverhead = 1 << 20
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Upload)
+	r.Get("/{attachmentID}", h.Download)
+	r.Delete("/{attachmentID}", h.Delete)
+}
+
+// Upload stores the file of the multipart form field "file". An optional
+// "sha256" field holds the hex checksum the content must match.
+func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAttachmentSize+multipartOverhead)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, service.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.svc.AddAttachment(
+		r.Context(),
+		todoID,
+		header.Filename,
+		header.Header.Get("Content-Type"),
+		header.Size,
+		r.FormValue("sha256"),
+		file,
+	)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := h.svc.ListAttachments(r.Context(), todoID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// Download streams the content of an attachment. Its checksum is sent as
+// ETag and Repr-Digest so clients can verify what they received.
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	todoID, attachmentID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.svc.OpenAttachment(r.Context(), todoID, attachmentID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer content.Close()
+
+	etag := `"` + attachment.SHA256 + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.Filename,
+	}))
+	w.Header().Set("ETag", etag)
+	if sum, err := hex.DecodeString(attachment.SHA256); err == nil {
+		w.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum)+":")
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, content)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	todoID, attachmentID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteAttachment(r.Context(), todoID, attachmentID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (todoID, attachmentID uuid.UUID, ok bool) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return todoID, attachmentID, false
+	}
+	attachmentID, err = uuid.Parse(chi.URLParam(r, "attachmentID"))
+	if err != nil {
+		http.Error(w, "invalid attachment ID", http.StatusBadRequest)
+		return todoID, attachmentID, false
+	}
+	return todoID, attachmentID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTodoNotFound),
+		errors.Is(err, repository.ErrAttachmentNotFound),
+		errors.Is(err, storage.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrUnsupportedMediaType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, service.ErrChecksumMismatch):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,122 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/backup"
+	"github.com/awnzl/to-do-app/internal/logging"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+const maxRestoreSize = 100 << 20
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterBackupRoutes(r chi.Router) {
+	r.Get("/", h.Backup)
+}
+
+func (h *Handler) RegisterRestoreRoutes(r chi.Router) {
+	r.Post("/", h.Restore)
+}
+
+// Backup streams the account archive of a user. A failure is answered with
+// 500 unless part of the archive was sent already; then it is only logged
+// and the client gets a truncated archive.
+func (h *Handler) Backup(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.svc.BackupUser(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	now := time.Now()
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("%s-%s.tar.gz", data.User.Username, now.UTC().Format("20060102-150405")),
+	}))
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	if err := backup.Write(ww, data, now); err != nil {
+		if ww.BytesWritten() == 0 {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "backup user",
+			"user_id", userID, "bytes", ww.BytesWritten(), "error", err)
+	}
+}
+
+// Restore recreates the content of an archive in the request body for a
+// user. ids=remap gives every record a new ID; by default the IDs of the
+// archive are kept and the restore fails with 409 if any is taken.
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var remapIDs bool
+	switch ids := r.URL.Query().Get("ids"); ids {
+	case "", "keep":
+	case "remap":
+		remapIDs = true
+	default:
+		http.Error(w, fmt.Sprintf("invalid ids %q, expected keep or remap", ids), http.StatusBadRequest)
+		return
+	}
+
+	data, _, err := backup.Read(http.MaxBytesReader(w, r.Body, maxRestoreSize))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := h.svc.RestoreUser(r.Context(), userID, data, remapIDs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge), errors.Is(err, backup.ErrArchiveTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrIDConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, backup.ErrInvalidArchive):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,200 @@
+package board
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterColumnRoutes registers the board columns of a list.
+func (h *Handler) RegisterColumnRoutes(r chi.Router) {
+	r.Get("/", h.ListColumns)
+	r.Post("/", h.CreateColumn)
+	r.Put("/{columnID}", h.UpdateColumn)
+	r.Delete("/{columnID}", h.DeleteColumn)
+}
+
+// RegisterBoardRoutes registers the board of a list.
+func (h *Handler) RegisterBoardRoutes(r chi.Router) {
+	r.Get("/", h.Board)
+}
+
+// RegisterTodoRoutes registers the column placement of a todo.
+func (h *Handler) RegisterTodoRoutes(r chi.Router) {
+	r.Put("/", h.PlaceTodo)
+}
+
+func (h *Handler) ListColumns(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	columns, err := h.svc.ListBoardColumns(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(columns)
+}
+
+func (h *Handler) CreateColumn(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+	req, ok := decodeColumnRequest(w, r)
+	if !ok {
+		return
+	}
+
+	column, err := h.svc.CreateBoardColumn(r.Context(), listID, req.Name, req.WIPLimit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(column)
+}
+
+// UpdateColumn renames a column and replaces its WIP limit. A position in
+// the body also moves the column among the list's columns.
+func (h *Handler) UpdateColumn(w http.ResponseWriter, r *http.Request) {
+	listID, columnID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+	req, ok := decodeColumnRequest(w, r)
+	if !ok {
+		return
+	}
+
+	column, err := h.svc.UpdateBoardColumn(r.Context(), listID, columnID, req.Name, req.WIPLimit, req.Position)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(column)
+}
+
+func (h *Handler) DeleteColumn(w http.ResponseWriter, r *http.Request) {
+	listID, columnID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteBoardColumn(r.Context(), listID, columnID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Board returns the columns of a list with their todos in order, and the
+// todos that are in no column.
+func (h *Handler) Board(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	board, err := h.svc.GetBoard(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(board)
+}
+
+// PlaceTodo moves a todo into a column at a position, or off the board when
+// column_id is null.
+func (h *Handler) PlaceTodo(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PlaceTodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.MoveTodoToColumn(r.Context(), todoID, req.ColumnID, req.Position); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeColumnRequest(w http.ResponseWriter, r *http.Request) (*models.SaveBoardColumnRequest, bool) {
+	var req models.SaveBoardColumnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Name == "" {
+		http.Error(w, "invalid column name", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.WIPLimit != nil && *req.WIPLimit < 1 {
+		http.Error(w, "wip_limit must be positive", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (listID, columnID uuid.UUID, ok bool) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return listID, columnID, false
+	}
+	columnID, err = uuid.Parse(chi.URLParam(r, "columnID"))
+	if err != nil {
+		http.Error(w, "invalid column ID", http.StatusBadRequest)
+		return listID, columnID, false
+	}
+	return listID, columnID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrColumnNotFound),
+		errors.Is(err, repository.ErrListNotFound),
+		errors.Is(err, repository.ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrColumnNameTaken),
+		errors.Is(err, service.ErrWIPLimitReached):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrColumnNotInList):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,348 @@
+// Package caldav serves todo lists as CalDAV calendar collections holding
+// one VTODO resource per todo. It implements the subset of RFC 4791 and
+// RFC 6578 used by Apple Reminders, Thunderbird and DAVx5: PROPFIND,
+// REPORT (calendar-query, calendar-multiget, sync-collection), GET, PUT and
+// DELETE with ETag preconditions, plus ctags.
+//
+// The layout below Prefix is:
+//
+//	/                      root
+//	/principal/            principal of the calling user
+//	/lists/                calendar home, the lists the user owns
+//	/lists/{listID}/       calendar collection
+//	/lists/{listID}/{name} calendar object
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/calendar"
+	"github.com/awnzl/to-do-app/internal/ical"
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// Prefix is the path the CalDAV tree is mounted at.
+const Prefix = "/dav"
+
+// Methods are the WebDAV methods that have to be registered with chi.
+var Methods = []string{"PROPFIND", "REPORT"}
+
+const maxObjectSize = 1 << 20
+
+type kind int
+
+const (
+	kindRoot kind = iota
+	kindPrincipal
+	kindHome
+	kindCalendar
+	kindObject
+)
+
+// target is the resource a request path points at.
+type target struct {
+	kind kind
+	user *models.User
+	list *models.TodoList
+	name string
+}
+
+var errNotFound = errors.New("resource not found")
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.HandleFunc("/", h.serve)
+	r.HandleFunc("/*", h.serve)
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// clients authenticate with the gateway in front of the server, which
+	// sets the user; there are no credentials to challenge for here
+	userID, ok := identity.UserID(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	t, err := h.resolve(r, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case "PROPFIND":
+		h.propfind(w, r, t)
+	case "REPORT":
+		h.report(w, r, t)
+	case http.MethodGet, http.MethodHead:
+		h.get(w, r, t)
+	case http.MethodPut:
+		h.put(w, r, t)
+	case http.MethodDelete:
+		h.delete(w, r, t)
+	default:
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// resolve maps the request path onto a resource. Lists of other users are
+// reported as missing.
+func (h *Handler) resolve(r *http.Request, userID uuid.UUID) (*target, error) {
+	user, err := h.svc.GetUser(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, Prefix)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		segments = nil
+	}
+
+	t := &target{user: user}
+	switch {
+	case len(segments) == 0:
+		t.kind = kindRoot
+	case segments[0] == "principal" && len(segments) == 1:
+		t.kind = kindPrincipal
+	case segments[0] == "lists" && len(segments) == 1:
+		t.kind = kindHome
+	case segments[0] == "lists" && len(segments) <= 3:
+		listID, err := uuid.Parse(segments[1])
+		if err != nil {
+			return nil, errNotFound
+		}
+		list, err := h.svc.GetList(r.Context(), listID)
+		if err != nil {
+			return nil, err
+		}
+		if list.OwnerID == nil || *list.OwnerID != user.ID {
+			return nil, errNotFound
+		}
+		t.list = list
+		t.kind = kindCalendar
+		if len(segments) == 3 {
+			t.kind = kindObject
+			if t.name, err = url.PathUnescape(segments[2]); err != nil {
+				return nil, errNotFound
+			}
+		}
+	default:
+		return nil, errNotFound
+	}
+
+	return t, nil
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request, t *target) {
+	var cal *ical.Component
+	switch t.kind {
+	case kindObject:
+		obj, err := h.svc.GetCalendarObject(r.Context(), t.list.ID, t.name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		cal = calendar.Object(obj)
+	case kindCalendar:
+		objects, err := h.svc.ListCalendarObjects(r.Context(), t.list.ID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		cal = calendar.NewCalendar(t.list.Name)
+		for _, obj := range objects {
+			cal.Components = append(cal.Components, calendar.Object(obj).Components...)
+		}
+	default:
+		http.Error(w, "not a calendar resource", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, etag, err := encode(cal)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", etag)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(data)
+}
+
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, t *target) {
+	if t.kind != kindObject {
+		http.Error(w, "only calendar objects can be written", http.StatusMethodNotAllowed)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "text/calendar") {
+		writePrecondition(w, http.StatusUnsupportedMediaType, xmlName(nsCalDAV, "supported-calendar-data"))
+		return
+	}
+
+	vtodo, err := calendar.ParseObject(http.MaxBytesReader(w, r.Body, maxObjectSize))
+	if err != nil {
+		if errors.Is(err, calendar.ErrNoTodo) {
+			writePrecondition(w, http.StatusForbidden, xmlName(nsCalDAV, "supported-calendar-component"))
+			return
+		}
+		writePrecondition(w, http.StatusBadRequest, xmlName(nsCalDAV, "valid-calendar-data"))
+		return
+	}
+
+	existing, err := h.svc.GetCalendarObject(r.Context(), t.list.ID, t.name)
+	if err != nil && !errors.Is(err, repository.ErrTodoNotFound) {
+		writeError(w, err)
+		return
+	}
+	if !h.checkPreconditions(w, r, existing) {
+		return
+	}
+
+	if existing != nil {
+		todo := &existing.Todo
+		if err := calendar.ApplyTodo(todo, vtodo); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.svc.UpdateTodo(r.Context(), todo); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	todo := &models.Todo{ID: uuid.New()}
+	if err := calendar.ApplyTodo(todo, vtodo); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	uid := calendar.UID(todo.ID)
+	if p := vtodo.Prop("UID"); p != nil && p.Value != "" {
+		uid = p.Value
+	}
+	if err := h.svc.CreateCalendarObject(r.Context(), t.list.ID, t.name, uid, todo); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, t *target) {
+	if t.kind != kindObject {
+		http.Error(w, "only calendar objects can be deleted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	existing, err := h.svc.GetCalendarObject(r.Context(), t.list.ID, t.name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !h.checkPreconditions(w, r, existing) {
+		return
+	}
+
+	if err := h.svc.DeleteTodo(r.Context(), existing.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkPreconditions evaluates If-Match and If-None-Match against the
+// current state of the object, which is nil when it does not exist.
+func (h *Handler) checkPreconditions(w http.ResponseWriter, r *http.Request, existing *models.CalendarObject) bool {
+	var etag string
+	if existing != nil {
+		var err error
+		if _, etag, err = encode(calendar.Object(existing)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+	}
+
+	if match := r.Header.Get("If-Match"); match != "" {
+		if existing == nil || (match != "*" && !etagListContains(match, etag)) {
+			http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+			return false
+		}
+	}
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" && existing != nil {
+		if noneMatch == "*" || etagListContains(noneMatch, etag) {
+			http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+			return false
+		}
+	}
+
+	return true
+}
+
+func etagListContains(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// encode renders a calendar and derives its ETag from the content.
+func encode(cal *ical.Component) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errNotFound),
+		errors.Is(err, repository.ErrListNotFound),
+		errors.Is(err, repository.ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrOpenBlockers),
+		errors.Is(err, repository.ErrCalendarObjectNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,335 @@
+package caldav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// fakeService keeps a single user's lists and todos in memory, including
+// the change log that backs sync tokens.
+type fakeService struct {
+	service.TodoService
+
+	user    *models.User
+	lists   map[uuid.UUID]*models.TodoList
+	objects map[uuid.UUID]*models.CalendarObject
+	changes []fakeChange
+}
+
+type fakeChange struct {
+	seq    int64
+	listID uuid.UUID
+	change models.CalendarChange
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		user:    &models.User{ID: uuid.New(), Username: "ann", Email: "ann@example.com"},
+		lists:   map[uuid.UUID]*models.TodoList{},
+		objects: map[uuid.UUID]*models.CalendarObject{},
+	}
+}
+
+func (f *fakeService) logChange(obj *models.CalendarObject, deleted bool) {
+	f.changes = append(f.changes, fakeChange{
+		seq:    int64(len(f.changes) + 1),
+		listID: obj.ListID,
+		change: models.CalendarChange{TodoID: obj.ID, Name: obj.Name, Deleted: deleted},
+	})
+}
+
+func (f *fakeService) GetUser(_ context.Context, id uuid.UUID) (*models.User, error) {
+	if id != f.user.ID {
+		return nil, repository.ErrUserNotFound
+	}
+	return f.user, nil
+}
+
+func (f *fakeService) GetList(_ context.Context, id uuid.UUID) (*models.TodoList, error) {
+	if list, ok := f.lists[id]; ok {
+		return list, nil
+	}
+	return nil, repository.ErrListNotFound
+}
+
+func (f *fakeService) ListOwnedLists(_ context.Context, ownerID uuid.UUID) ([]*models.TodoList, error) {
+	var lists []*models.TodoList
+	for _, list := range f.lists {
+		if list.OwnerID != nil && *list.OwnerID == ownerID {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+func (f *fakeService) ListCalendarObjects(_ context.Context, listID uuid.UUID) ([]*models.CalendarObject, error) {
+	var objects []*models.CalendarObject
+	for _, obj := range f.objects {
+		if obj.ListID == listID {
+			copied := *obj
+			objects = append(objects, &copied)
+		}
+	}
+	return objects, nil
+}
+
+func (f *fakeService) GetCalendarObject(_ context.Context, listID uuid.UUID, name string) (*models.CalendarObject, error) {
+	for _, obj := range f.objects {
+		if obj.ListID == listID && obj.Name == name {
+			copied := *obj
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrTodoNotFound
+}
+
+func (f *fakeService) CreateTodo(
+	_ context.Context, listID uuid.UUID, title, description string, schedule models.Schedule,
+) (*models.Todo, error) {
+	id := uuid.New()
+	obj := &models.CalendarObject{
+		Todo: models.Todo{
+			ID: id, ListID: listID, Title: title, Description: description,
+			CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		},
+		Name: id.String() + ".ics",
+		UID:  id.String() + "@to-do-app",
+	}
+	schedule.Apply(&obj.Todo, time.UTC)
+	f.objects[id] = obj
+	f.logChange(obj, false)
+	todo := obj.Todo
+	return &todo, nil
+}
+
+func (f *fakeService) CreateCalendarObject(
+	_ context.Context, listID uuid.UUID, name, uid string, todo *models.Todo,
+) error {
+	todo.ListID = listID
+	todo.CreatedAt, todo.UpdatedAt = time.Now(), time.Now()
+	obj := &models.CalendarObject{Todo: *todo, Name: name, UID: uid}
+	f.objects[todo.ID] = obj
+	f.logChange(obj, false)
+	return nil
+}
+
+func (f *fakeService) UpdateTodo(_ context.Context, todo *models.Todo) error {
+	obj := f.objects[todo.ID]
+	obj.Todo = *todo
+	obj.UpdatedAt = time.Now()
+	f.logChange(obj, false)
+	return nil
+}
+
+func (f *fakeService) CompleteTodo(_ context.Context, id uuid.UUID, _ bool) error {
+	todo := f.objects[id].Todo
+	todo.Status = true
+	return f.UpdateTodo(context.Background(), &todo)
+}
+
+func (f *fakeService) DeleteTodo(_ context.Context, id uuid.UUID) error {
+	f.logChange(f.objects[id], true)
+	delete(f.objects, id)
+	return nil
+}
+
+func (f *fakeService) GetCalendarSyncToken(_ context.Context, listID uuid.UUID) (int64, error) {
+	var seq int64
+	for _, c := range f.changes {
+		if c.listID == listID {
+			seq = c.seq
+		}
+	}
+	return seq, nil
+}
+
+func (f *fakeService) ListCalendarChanges(
+	_ context.Context, listID uuid.UUID, since int64,
+) ([]*models.CalendarChange, error) {
+	latest := map[uuid.UUID]models.CalendarChange{}
+	for _, c := range f.changes {
+		if c.listID == listID && c.seq > since {
+			latest[c.change.TodoID] = c.change
+		}
+	}
+	var changes []*models.CalendarChange
+	for _, c := range latest {
+		changes = append(changes, &c)
+	}
+	return changes, nil
+}
+
+type davClient struct {
+	t      *testing.T
+	router http.Handler
+	userID uuid.UUID
+}
+
+func (c *davClient) do(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	req = req.WithContext(identity.WithUserID(req.Context(), c.userID))
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func setup(t *testing.T) (*fakeService, *models.TodoList, *davClient) {
+	for _, method := range Methods {
+		chi.RegisterMethod(method)
+	}
+
+	svc := newFakeService()
+	list := &models.TodoList{ID: uuid.New(), Name: "Groceries", OwnerID: &svc.user.ID}
+	svc.lists[list.ID] = list
+
+	r := chi.NewRouter()
+	r.Route(Prefix, NewHandler(svc).RegisterRoutes)
+
+	return svc, list, &davClient{t: t, router: r, userID: svc.user.ID}
+}
+
+const vtodo = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:client-uid-1\r\n" +
+	"SUMMARY:Buy milk\r\nDUE:20250314T160000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+func TestPutGetAndPreconditions(t *testing.T) {
+	svc, list, c := setup(t)
+	href := Prefix + "/lists/" + list.ID.String() + "/client-uid-1.ics"
+
+	rec := c.do(http.MethodPut, href, vtodo, "Content-Type", "text/calendar", "If-None-Match", "*")
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.Len(t, svc.objects, 1)
+
+	rec = c.do(http.MethodGet, href, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "UID:client-uid-1\r\n")
+	assert.Contains(t, rec.Body.String(), "SUMMARY:Buy milk\r\n")
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = c.do(http.MethodPut, href, vtodo, "If-None-Match", "*")
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	completed := strings.Replace(vtodo, "END:VTODO", "STATUS:COMPLETED\r\nEND:VTODO", 1)
+	rec = c.do(http.MethodPut, href, completed, "If-Match", `"stale"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	rec = c.do(http.MethodPut, href, completed, "If-Match", etag)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	for _, obj := range svc.objects {
+		assert.True(t, obj.Status)
+	}
+
+	rec = c.do(http.MethodPut, href, strings.ReplaceAll(vtodo, "VTODO", "VEVENT"))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestPropfindAndSyncCollection(t *testing.T) {
+	svc, list, c := setup(t)
+	calendarHref := Prefix + "/lists/" + list.ID.String() + "/"
+
+	first, _ := svc.CreateTodo(context.Background(), list.ID, "First", "", models.Schedule{})
+	second, _ := svc.CreateTodo(context.Background(), list.ID, "Second", "", models.Schedule{})
+
+	rec := c.do("PROPFIND", Prefix+"/lists/", `<?xml version="1.0"?>
+		<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+			<d:prop><d:resourcetype/><d:displayname/><cs:getctag/><d:quota-used-bytes/></d:prop>
+		</d:propfind>`, "Depth", "1")
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<d:href>"+calendarHref+"</d:href>")
+	assert.Contains(t, body, "<d:displayname>Groceries</d:displayname>")
+	assert.Contains(t, body, "<d:resourcetype><d:collection/><c:calendar/></d:resourcetype>")
+	assert.Contains(t, body, "<cs:getctag>2</cs:getctag>")
+	assert.Contains(t, body, "<d:quota-used-bytes/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status>")
+
+	syncReport := func(token string) string {
+		rec := c.do("REPORT", calendarHref, `<?xml version="1.0"?>
+			<d:sync-collection xmlns:d="DAV:">
+				<d:sync-token>`+token+`</d:sync-token>
+				<d:sync-level>1</d:sync-level>
+				<d:prop><d:getetag/></d:prop>
+			</d:sync-collection>`)
+		require.Equal(t, http.StatusMultiStatus, rec.Code, rec.Body.String())
+		return rec.Body.String()
+	}
+	tokenOf := func(body string) string {
+		m := regexp.MustCompile(`<d:sync-token>([^<]+)</d:sync-token>`).FindStringSubmatch(body)
+		require.Len(t, m, 2)
+		return m[1]
+	}
+
+	body = syncReport("")
+	assert.Equal(t, 2, strings.Count(body, "<d:getetag>"))
+	token := tokenOf(body)
+
+	require.NoError(t, svc.DeleteTodo(context.Background(), first.ID))
+	second.Title = "Second, renamed"
+	require.NoError(t, svc.UpdateTodo(context.Background(), second))
+
+	body = syncReport(token)
+	assert.Contains(t, body, "<d:href>"+calendarHref+first.ID.String()+".ics</d:href>"+
+		"<d:status>HTTP/1.1 404 Not Found</d:status>")
+	assert.Contains(t, body, "<d:href>"+calendarHref+second.ID.String()+".ics</d:href><d:propstat>")
+	assert.NotEqual(t, token, tokenOf(body))
+
+	rec = c.do("REPORT", calendarHref, `<d:sync-collection xmlns:d="DAV:"><d:sync-token>bogus</d:sync-token></d:sync-collection>`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<d:valid-sync-token/>")
+}
+
+func TestCalendarQueryAndMultiget(t *testing.T) {
+	svc, list, c := setup(t)
+	calendarHref := Prefix + "/lists/" + list.ID.String() + "/"
+
+	open, _ := svc.CreateTodo(context.Background(), list.ID, "Open", "", models.Schedule{})
+	done, _ := svc.CreateTodo(context.Background(), list.ID, "Done", "", models.Schedule{})
+	require.NoError(t, svc.CompleteTodo(context.Background(), done.ID, false))
+
+	rec := c.do("REPORT", calendarHref, `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/></d:prop>
+			<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VTODO">
+				<c:prop-filter name="COMPLETED"><c:is-not-defined/></c:prop-filter>
+			</c:comp-filter></c:comp-filter></c:filter>
+		</c:calendar-query>`)
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	assert.Contains(t, rec.Body.String(), open.ID.String())
+	assert.NotContains(t, rec.Body.String(), done.ID.String())
+
+	rec = c.do("REPORT", calendarHref, `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/><c:calendar-data/></d:prop>
+			<d:href>`+calendarHref+done.ID.String()+`.ics</d:href>
+			<d:href>`+calendarHref+`missing.ics</d:href>
+		</c:calendar-multiget>`)
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), "SUMMARY:Done")
+	assert.Contains(t, string(body), "missing.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status>")
+}
+
+func TestOtherUsersListsAreHidden(t *testing.T) {
+	svc, _, c := setup(t)
+	otherOwner := uuid.New()
+	other := &models.TodoList{ID: uuid.New(), Name: "Private", OwnerID: &otherOwner}
+	svc.lists[other.ID] = other
+
+	rec := c.do("PROPFIND", Prefix+"/lists/"+other.ID.String()+"/", "", "Depth", "0")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
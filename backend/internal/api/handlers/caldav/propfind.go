@@ -0,0 +1,274 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/calendar"
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+const syncTokenPrefix = "http://to-do-app/ns/sync/"
+
+func xmlName(space, local string) xml.Name {
+	return xml.Name{Space: space, Local: local}
+}
+
+var (
+	propResourceType         = xmlName(nsDAV, "resourcetype")
+	propDisplayName          = xmlName(nsDAV, "displayname")
+	propCurrentUserPrincipal = xmlName(nsDAV, "current-user-principal")
+	propPrincipalURL         = xmlName(nsDAV, "principal-URL")
+	propOwner                = xmlName(nsDAV, "owner")
+	propGetETag              = xmlName(nsDAV, "getetag")
+	propGetContentType       = xmlName(nsDAV, "getcontenttype")
+	propGetContentLength     = xmlName(nsDAV, "getcontentlength")
+	propGetLastModified      = xmlName(nsDAV, "getlastmodified")
+	propSyncToken            = xmlName(nsDAV, "sync-token")
+	propSupportedReportSet   = xmlName(nsDAV, "supported-report-set")
+	propPrivilegeSet         = xmlName(nsDAV, "current-user-privilege-set")
+	propCalendarHomeSet      = xmlName(nsCalDAV, "calendar-home-set")
+	propUserAddressSet       = xmlName(nsCalDAV, "calendar-user-address-set")
+	propComponentSet         = xmlName(nsCalDAV, "supported-calendar-component-set")
+	propCalendarData         = xmlName(nsCalDAV, "calendar-data")
+	propGetCTag              = xmlName(nsCS, "getctag")
+)
+
+// properties is the full property set of a resource in the order allprop
+// reports it.
+type properties []propValue
+
+func (p *properties) add(name xml.Name, value string) {
+	*p = append(*p, propValue{name: name, value: value})
+}
+
+func (p properties) get(name xml.Name) (string, bool) {
+	for _, v := range p {
+		if v.name == name {
+			return v.value, true
+		}
+	}
+	return "", false
+}
+
+func (h *Handler) propfind(w http.ResponseWriter, r *http.Request, t *target) {
+	var req propfindRequest
+	if err := decodeBody(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// an empty body asks for all properties
+	all := req.AllProp != nil || (req.PropName == nil && len(req.Prop) == 0)
+
+	depth := r.Header.Get("Depth")
+	hrefs := []string{h.href(t)}
+	var resources []properties
+
+	self, err := h.properties(r.Context(), t)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resources = append(resources, self)
+
+	if depth != "0" {
+		switch t.kind {
+		case kindRoot:
+			for _, child := range []*target{{kind: kindPrincipal, user: t.user}, {kind: kindHome, user: t.user}} {
+				props, err := h.properties(r.Context(), child)
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				hrefs = append(hrefs, h.href(child))
+				resources = append(resources, props)
+			}
+		case kindHome:
+			lists, err := h.svc.ListOwnedLists(r.Context(), t.user.ID)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			for _, list := range lists {
+				child := &target{kind: kindCalendar, user: t.user, list: list}
+				props, err := h.properties(r.Context(), child)
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				hrefs = append(hrefs, h.href(child))
+				resources = append(resources, props)
+			}
+		case kindCalendar:
+			objects, err := h.svc.ListCalendarObjects(r.Context(), t.list.ID)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			for _, obj := range objects {
+				props, err := objectProperties(obj)
+				if err != nil {
+					writeError(w, err)
+					return
+				}
+				hrefs = append(hrefs, h.objectHref(t.list, obj.Name))
+				resources = append(resources, props)
+			}
+		}
+	}
+
+	responses := make([]*response, len(resources))
+	for i, props := range resources {
+		switch {
+		case req.PropName != nil:
+			resp := &response{href: hrefs[i]}
+			for _, p := range props {
+				resp.found = append(resp.found, propValue{name: p.name})
+			}
+			responses[i] = resp
+		case all:
+			responses[i] = selectProperties(hrefs[i], props, nil)
+		default:
+			responses[i] = selectProperties(hrefs[i], props, req.Prop)
+		}
+	}
+
+	writeMultistatus(w, responses, "")
+}
+
+// selectProperties builds the response for the requested properties, or
+// for all of them except calendar-data when names is nil.
+func selectProperties(href string, props properties, names []xml.Name) *response {
+	resp := &response{href: href}
+	if names == nil {
+		for _, p := range props {
+			if p.name != propCalendarData {
+				resp.found = append(resp.found, p)
+			}
+		}
+		return resp
+	}
+
+	for _, name := range names {
+		if value, ok := props.get(name); ok {
+			resp.found = append(resp.found, propValue{name: name, value: value})
+		} else {
+			resp.missing = append(resp.missing, name)
+		}
+	}
+	return resp
+}
+
+func (h *Handler) properties(ctx context.Context, t *target) (properties, error) {
+	var props properties
+	principal := hrefXML(Prefix + "/principal/")
+	props.add(propCurrentUserPrincipal, principal)
+
+	switch t.kind {
+	case kindRoot:
+		props.add(propResourceType, "<d:collection/>")
+	case kindPrincipal:
+		props.add(propResourceType, "<d:principal/>")
+		props.add(propDisplayName, textXML(t.user.Username))
+		props.add(propPrincipalURL, principal)
+		props.add(propCalendarHomeSet, hrefXML(Prefix+"/lists/"))
+		props.add(propUserAddressSet, hrefXML("mailto:"+t.user.Email))
+	case kindHome:
+		props.add(propResourceType, "<d:collection/>")
+		props.add(propDisplayName, "Lists")
+	case kindCalendar:
+		token, err := h.svc.GetCalendarSyncToken(ctx, t.list.ID)
+		if err != nil {
+			return nil, err
+		}
+		props.add(propResourceType, "<d:collection/><c:calendar/>")
+		props.add(propDisplayName, textXML(t.list.Name))
+		props.add(propOwner, principal)
+		props.add(propComponentSet, `<c:comp name="VTODO"/>`)
+		props.add(propSupportedReportSet, supportedReports)
+		props.add(propPrivilegeSet, privileges)
+		props.add(propGetCTag, strconv.FormatInt(token, 10))
+		props.add(propSyncToken, syncTokenPrefix+strconv.FormatInt(token, 10))
+	case kindObject:
+		obj, err := h.svc.GetCalendarObject(ctx, t.list.ID, t.name)
+		if err != nil {
+			return nil, err
+		}
+		return objectProperties(obj)
+	}
+
+	return props, nil
+}
+
+const supportedReports = "" +
+	"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+	"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+	"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>"
+
+const privileges = "" +
+	"<d:privilege><d:read/></d:privilege>" +
+	"<d:privilege><d:write/></d:privilege>" +
+	"<d:privilege><d:write-content/></d:privilege>" +
+	"<d:privilege><d:bind/></d:privilege>" +
+	"<d:privilege><d:unbind/></d:privilege>"
+
+func objectProperties(obj *models.CalendarObject) (properties, error) {
+	data, etag, err := encode(calendar.Object(obj))
+	if err != nil {
+		return nil, err
+	}
+
+	var props properties
+	props.add(propResourceType, "")
+	props.add(propGetETag, textXML(etag))
+	props.add(propGetContentType, "text/calendar; charset=utf-8; component=vtodo")
+	props.add(propGetContentLength, strconv.Itoa(len(data)))
+	props.add(propGetLastModified, obj.UpdatedAt.UTC().Format(http.TimeFormat))
+	props.add(propCalendarData, textXML(string(data)))
+	return props, nil
+}
+
+func (h *Handler) href(t *target) string {
+	switch t.kind {
+	case kindPrincipal:
+		return Prefix + "/principal/"
+	case kindHome:
+		return Prefix + "/lists/"
+	case kindCalendar:
+		return Prefix + "/lists/" + t.list.ID.String() + "/"
+	case kindObject:
+		return h.objectHref(t.list, t.name)
+	default:
+		return Prefix + "/"
+	}
+}
+
+func (h *Handler) objectHref(list *models.TodoList, name string) string {
+	return Prefix + "/lists/" + list.ID.String() + "/" + url.PathEscape(name)
+}
+
+// decodeBody decodes an optional XML request body.
+func decodeBody(body io.Reader, v any) error {
+	err := xml.NewDecoder(body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func parseSyncToken(token string) (int64, bool) {
+	if token == "" {
+		return 0, true
+	}
+	seq, err := strconv.ParseInt(strings.TrimPrefix(token, syncTokenPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(token, syncTokenPrefix) || seq < 0 {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,219 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, t *target) {
+	if t.kind != kindCalendar {
+		http.Error(w, "reports are only supported on calendars", http.StatusForbidden)
+		return
+	}
+
+	var req reportRequest
+	if err := decodeBody(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// nil selects all properties
+	props := []xml.Name(req.Prop)
+	if req.AllProp != nil {
+		props = nil
+	}
+
+	switch req.XMLName {
+	case xmlName(nsCalDAV, "calendar-query"):
+		h.calendarQuery(w, r, t, &req, props)
+	case xmlName(nsCalDAV, "calendar-multiget"):
+		h.calendarMultiget(w, r, t, &req, props)
+	case xmlName(nsDAV, "sync-collection"):
+		h.syncCollection(w, r, t, &req, props)
+	default:
+		writePrecondition(w, http.StatusForbidden, xmlName(nsDAV, "supported-report"))
+	}
+}
+
+func (h *Handler) calendarQuery(
+	w http.ResponseWriter, r *http.Request, t *target, req *reportRequest, props []xml.Name,
+) {
+	objects, err := h.svc.ListCalendarObjects(r.Context(), t.list.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	responses := make([]*response, 0, len(objects))
+	for _, obj := range objects {
+		if req.Filter != nil && !matchesCalendar(obj, &req.Filter.CompFilter) {
+			continue
+		}
+		resp, err := objectResponse(h.objectHref(t.list, obj.Name), obj, props)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		responses = append(responses, resp)
+	}
+
+	writeMultistatus(w, responses, "")
+}
+
+func (h *Handler) calendarMultiget(
+	w http.ResponseWriter, r *http.Request, t *target, req *reportRequest, props []xml.Name,
+) {
+	base := h.href(t)
+	responses := make([]*response, 0, len(req.Hrefs))
+	for _, href := range req.Hrefs {
+		href = strings.TrimSpace(href)
+		if u, err := url.Parse(href); err == nil {
+			// clients may send absolute URLs
+			href = u.EscapedPath()
+		}
+
+		name, err := url.PathUnescape(strings.TrimPrefix(href, base))
+		if err != nil || !strings.HasPrefix(href, base) || name == "" || strings.Contains(name, "/") {
+			responses = append(responses, &response{href: href, status: http.StatusNotFound})
+			continue
+		}
+
+		obj, err := h.svc.GetCalendarObject(r.Context(), t.list.ID, name)
+		if err != nil {
+			responses = append(responses, &response{href: href, status: http.StatusNotFound})
+			continue
+		}
+		resp, err := objectResponse(href, obj, props)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		responses = append(responses, resp)
+	}
+
+	writeMultistatus(w, responses, "")
+}
+
+// syncCollection reports the objects changed since the client's sync token;
+// an empty token reports all objects. Removed objects are reported with a
+// 404 status.
+func (h *Handler) syncCollection(
+	w http.ResponseWriter, r *http.Request, t *target, req *reportRequest, props []xml.Name,
+) {
+	current, err := h.svc.GetCalendarSyncToken(r.Context(), t.list.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	since, ok := parseSyncToken(req.SyncToken)
+	if !ok || since > current {
+		writePrecondition(w, http.StatusForbidden, xmlName(nsDAV, "valid-sync-token"))
+		return
+	}
+
+	objects, err := h.svc.ListCalendarObjects(r.Context(), t.list.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var responses []*response
+	if since == 0 {
+		for _, obj := range objects {
+			resp, err := objectResponse(h.objectHref(t.list, obj.Name), obj, props)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			responses = append(responses, resp)
+		}
+	} else {
+		byID := make(map[uuid.UUID]*models.CalendarObject, len(objects))
+		for _, obj := range objects {
+			byID[obj.ID] = obj
+		}
+
+		changes, err := h.svc.ListCalendarChanges(r.Context(), t.list.ID, since)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, change := range changes {
+			obj, ok := byID[change.TodoID]
+			if change.Deleted || !ok {
+				responses = append(responses, &response{
+					href:   h.objectHref(t.list, change.Name),
+					status: http.StatusNotFound,
+				})
+				continue
+			}
+			resp, err := objectResponse(h.objectHref(t.list, obj.Name), obj, props)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			responses = append(responses, resp)
+		}
+	}
+
+	writeMultistatus(w, responses, syncTokenPrefix+strconv.FormatInt(current, 10))
+}
+
+func objectResponse(href string, obj *models.CalendarObject, names []xml.Name) (*response, error) {
+	props, err := objectProperties(obj)
+	if err != nil {
+		return nil, err
+	}
+	return selectProperties(href, props, names), nil
+}
+
+// matchesCalendar evaluates the subset of calendar-query filters clients
+// use for todos: component names, a time range on the due date and
+// is-not-defined on COMPLETED.
+func matchesCalendar(obj *models.CalendarObject, f *compFilter) bool {
+	if f.Name != "VCALENDAR" {
+		return false
+	}
+	for i := range f.CompFilters {
+		if !matchesTodo(obj, &f.CompFilters[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTodo(obj *models.CalendarObject, f *compFilter) bool {
+	if f.Name != "VTODO" {
+		return false
+	}
+
+	if due, ok := obj.Deadline(time.UTC); ok && f.TimeRange != nil {
+		// all-day todos span their whole due date
+		begin := due
+		if obj.DueOn != nil {
+			begin = obj.DueOn.In(obj.Location(time.UTC))
+		}
+		if start, err := time.Parse("20060102T150405Z", f.TimeRange.Start); err == nil && due.Before(start) {
+			return false
+		}
+		if end, err := time.Parse("20060102T150405Z", f.TimeRange.End); err == nil && !begin.Before(end) {
+			return false
+		}
+	}
+
+	for _, pf := range f.PropFilters {
+		if strings.EqualFold(pf.Name, "COMPLETED") && pf.IsNotDefined != nil && obj.Status {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,171 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var prefixes = map[string]string{
+	nsDAV:    "d",
+	nsCalDAV: "c",
+	nsCS:     "cs",
+}
+
+// propList collects the names of the properties requested in a DAV:prop
+// element.
+type propList []xml.Name
+
+func (p *propList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfindRequest struct {
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     propList  `xml:"DAV: prop"`
+}
+
+// reportRequest covers calendar-query, calendar-multiget and
+// sync-collection; XMLName tells them apart.
+type reportRequest struct {
+	XMLName   xml.Name
+	AllProp   *struct{} `xml:"DAV: allprop"`
+	Prop      propList  `xml:"DAV: prop"`
+	Hrefs     []string  `xml:"DAV: href"`
+	SyncToken string    `xml:"DAV: sync-token"`
+	Filter    *filter   `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type filter struct {
+	CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	PropFilters []propFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type propFilter struct {
+	Name         string    `xml:"name,attr"`
+	IsNotDefined *struct{} `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+}
+
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// response is a single DAV:response of a multistatus. Removed resources
+// only carry a status; all others list found and missing properties.
+type response struct {
+	href    string
+	status  int
+	found   []propValue
+	missing []xml.Name
+}
+
+// propValue is a property with its value as inner XML.
+type propValue struct {
+	name  xml.Name
+	value string
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []*response, syncToken string) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, resp := range responses {
+		b.WriteString("<d:response>")
+		b.WriteString(hrefXML(resp.href))
+		if resp.status != 0 {
+			b.WriteString(statusXML(resp.status))
+		}
+		if len(resp.found) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range resp.found {
+				b.WriteString(element(p.name, p.value))
+			}
+			b.WriteString("</d:prop>" + statusXML(http.StatusOK) + "</d:propstat>")
+		}
+		if len(resp.missing) > 0 {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range resp.missing {
+				b.WriteString(element(name, ""))
+			}
+			b.WriteString("</d:prop>" + statusXML(http.StatusNotFound) + "</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	if syncToken != "" {
+		b.WriteString("<d:sync-token>" + textXML(syncToken) + "</d:sync-token>")
+	}
+	b.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	w.Write(b.Bytes())
+}
+
+// writePrecondition reports a failed DAV precondition such as
+// DAV:valid-sync-token.
+func writePrecondition(w http.ResponseWriter, status int, name xml.Name) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `%s<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">%s</d:error>`,
+		xml.Header, element(name, ""))
+}
+
+// element renders an element with the given inner XML. Elements outside the
+// known namespaces declare their namespace inline.
+func element(name xml.Name, inner string) string {
+	tag, decl := name.Local, ""
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		decl = ` xmlns:x="` + textXML(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + tag + decl + "/>"
+	}
+	return "<" + tag + decl + ">" + inner + "</" + tag + ">"
+}
+
+func hrefXML(href string) string {
+	return "<d:href>" + textXML(href) + "</d:href>"
+}
+
+func statusXML(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+func textXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package comments
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Put("/{commentID}", h.Update)
+	r.Delete("/{commentID}", h.Delete)
+	r.Get("/{commentID}/history", h.History)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.AddComment(r.Context(), todoID, req.ParentID, req.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// List returns the comment threads of a todo, replies nested under their
+// parents.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.svc.ListComments(r.Context(), todoID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(comments)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	todoID, commentID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.svc.EditComment(r.Context(), todoID, commentID, req.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	todoID, commentID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteComment(r.Context(), todoID, commentID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// History returns the earlier bodies of a comment, oldest first.
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	todoID, commentID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.svc.ListCommentRevisions(r.Context(), todoID, commentID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(revisions)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (todoID, commentID uuid.UUID, ok bool) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return todoID, commentID, false
+	}
+	commentID, err = uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		http.Error(w, "invalid comment ID", http.StatusBadRequest)
+		return todoID, commentID, false
+	}
+	return todoID, commentID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTodoNotFound),
+		errors.Is(err, repository.ErrCommentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidComment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrNotCommentAuthor):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
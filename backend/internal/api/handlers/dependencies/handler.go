@@ -0,0 +1,142 @@
+package dependencies
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterRoutes registers the dependencies of a todo on its blockers.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Put("/{blockerID}", h.Add)
+	r.Delete("/{blockerID}", h.Remove)
+}
+
+// RegisterGraphRoutes registers the dependency graph of a list.
+func (h *Handler) RegisterGraphRoutes(r chi.Router) {
+	r.Get("/", h.Graph)
+}
+
+// RegisterNextRoutes registers the work order of a list.
+func (h *Handler) RegisterNextRoutes(r chi.Router) {
+	r.Get("/", h.Next)
+}
+
+// List returns the todos a todo waits for and the todos waiting for it.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	deps, err := h.svc.ListTodoDependencies(r.Context(), todoID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(deps)
+}
+
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	todoID, blockerID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AddTodoDependency(r.Context(), todoID, blockerID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Remove(w http.ResponseWriter, r *http.Request) {
+	todoID, blockerID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RemoveTodoDependency(r.Context(), todoID, blockerID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Graph(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	graph, err := h.svc.GetDependencyGraph(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(graph)
+}
+
+// Next returns the open todos of a list in an order they can be worked on.
+func (h *Handler) Next(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	next, err := h.svc.NextTodos(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(next)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (todoID, blockerID uuid.UUID, ok bool) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return todoID, blockerID, false
+	}
+	blockerID, err = uuid.Parse(chi.URLParam(r, "blockerID"))
+	if err != nil {
+		http.Error(w, "invalid blocker ID", http.StatusBadRequest)
+		return todoID, blockerID, false
+	}
+	return todoID, blockerID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrTodoNotFound),
+		errors.Is(err, repository.ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrDependencyCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
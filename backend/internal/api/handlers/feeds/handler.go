@@ -0,0 +1,189 @@
+package feeds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/calendar"
+	"github.com/awnzl/to-do-app/internal/ical"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RegisterListRoutes registers token management for list feeds.
+func (h *Handler) RegisterListRoutes(r chi.Router) {
+	r.Get("/", h.ListForList)
+	r.Post("/", h.CreateForList)
+}
+
+// RegisterUserRoutes registers token management for user feeds.
+func (h *Handler) RegisterUserRoutes(r chi.Router) {
+	r.Get("/", h.ListForUser)
+	r.Post("/", h.CreateForUser)
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Delete("/{feedID}", h.Revoke)
+}
+
+// RegisterCalendarRoutes registers the public, token-protected feeds.
+func (h *Handler) RegisterCalendarRoutes(r chi.Router) {
+	r.Get("/{token}.ics", h.Calendar)
+}
+
+func (h *Handler) CreateForList(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+	h.create(w, r, nil, &listID)
+}
+
+func (h *Handler) CreateForUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+	h.create(w, r, &userID, nil)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request, userID, listID *uuid.UUID) {
+	token, err := h.svc.CreateFeedToken(r.Context(), userID, listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+func (h *Handler) ListForList(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, nil, &listID)
+}
+
+func (h *Handler) ListForUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, &userID, nil)
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, userID, listID *uuid.UUID) {
+	tokens, err := h.svc.ListFeedTokens(r.Context(), userID, listID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	feedID, err := uuid.Parse(chi.URLParam(r, "feedID"))
+	if err != nil {
+		http.Error(w, "invalid feed ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.RevokeFeedToken(r.Context(), feedID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Calendar serves the feed as text/calendar. The ETag is derived from the
+// rendered calendar so clients can poll with If-None-Match; Last-Modified is
+// the most recent change of a published todo.
+func (h *Handler) Calendar(w http.ResponseWriter, r *http.Request) {
+	components, err := parseComponents(r.URL.Query().Get("components"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	feed, err := h.svc.GetFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := ical.Encode(&body, calendar.Feed(feed, components)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var lastModified time.Time
+	for _, todo := range feed.Todos {
+		if todo.UpdatedAt.After(lastModified) {
+			lastModified = todo.UpdatedAt
+		}
+	}
+
+	sum := sha256.Sum256(body.Bytes())
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeContent(w, r, "", lastModified, bytes.NewReader(body.Bytes()))
+}
+
+// parseComponents reads a comma separated list of "vtodo" and "vevent".
+// Both are published by default.
+func parseComponents(s string) (calendar.Components, error) {
+	if s == "" {
+		return calendar.Components{Todos: true, Events: true}, nil
+	}
+
+	var c calendar.Components
+	for _, name := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "vtodo":
+			c.Todos = true
+		case "vevent":
+			c.Events = true
+		default:
+			return c, errors.New("components must be vtodo and/or vevent")
+		}
+	}
+	return c, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrFeedTokenNotFound),
+		errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
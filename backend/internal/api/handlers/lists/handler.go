@@ -2,12 +2,17 @@ package lists
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
-	"github.com/awnzl/to-do-app/internal/api/models"
+	apimodels "github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
 	"github.com/awnzl/to-do-app/internal/service"
 )
 
@@ -27,13 +32,33 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Put("/", h.Update)
 		r.Delete("/", h.Delete)
 		r.Get("/todos", h.ListTodos)
+		r.Put("/location", h.Move)
+		r.Post("/clone", h.Clone)
+		r.Post("/archive", h.Archive)
+		r.Post("/unarchive", h.Unarchive)
+		r.Put("/auto-archive", h.SetAutoArchive)
 	})
 }
 
+// ListAll returns all lists, or only those of the workspace or folder
+// given in the workspace_id and folder_id query parameters. Archived lists
+// are included with archived=true.
 func (h *Handler) ListAll(w http.ResponseWriter, r *http.Request) {
-	lists, err := h.svc.ListLists(r.Context())
+	var f models.ListFilter
+	var ok bool
+	if f.WorkspaceID, ok = parseOptionalID(w, r, "workspace_id"); !ok {
+		return
+	}
+	if f.FolderID, ok = parseOptionalID(w, r, "folder_id"); !ok {
+		return
+	}
+	if f.Archived, ok = parseArchived(w, r); !ok {
+		return
+	}
+
+	lists, err := h.svc.ListLists(r.Context(), f)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -41,21 +66,20 @@ func (h *Handler) ListAll(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
-	name := chi.URLParam(r, "name")
-	if name == "" {
-		http.Error(w, "invalid list name", http.StatusBadRequest)
+	var req apimodels.CreateListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	var req models.CreateListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if req.Name == "" {
+		http.Error(w, "invalid list name", http.StatusBadRequest)
 		return
 	}
 
-	list, err := h.svc.CreateList(r.Context(), req.Name)
+	list, err := h.svc.CreateList(r.Context(), req.Name, req.ListLocation)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -86,7 +110,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req models.UpdateListRequest
+	var req apimodels.UpdateListRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -130,7 +154,12 @@ func (h *Handler) ListTodos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	todos, err := h.svc.ListTodos(r.Context(), listID)
+	archived, ok := parseArchived(w, r)
+	if !ok {
+		return
+	}
+
+	todos, err := h.svc.ListTodos(r.Context(), listID, archived)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -138,3 +167,148 @@ func (h *Handler) ListTodos(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(todos)
 }
+
+// Move puts a list into the workspace and folder in the body, or takes it
+// out of its workspace when both are null.
+func (h *Handler) Move(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	var req apimodels.MoveListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.svc.MoveList(r.Context(), listID, req.ListLocation)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(list)
+}
+
+// Clone copies the list with its todos. The body is optional and may give
+// the copy's name, an offset in days for all dates and whether completed
+// todos are reopened.
+func (h *Handler) Clone(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	var req apimodels.CloneListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clone, err := h.svc.CloneList(r.Context(), listID, req.CloneOptions)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(clone)
+}
+
+// Archive hides the list from listings. It and its todos can still be
+// read.
+func (h *Handler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *Handler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *Handler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.svc.SetListArchived(r.Context(), listID, archived)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(list)
+}
+
+// SetAutoArchive sets after how many days completed todos of the list are
+// archived; null turns auto-archiving off.
+func (h *Handler) SetAutoArchive(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	var req apimodels.AutoArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Days != nil && *req.Days < 1 {
+		http.Error(w, "days must be positive", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.svc.SetListAutoArchive(r.Context(), listID, req.Days)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(list)
+}
+
+// parseArchived reads the archived query parameter, false when it is not
+// given.
+func parseArchived(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	s := r.URL.Query().Get("archived")
+	if s == "" {
+		return false, true
+	}
+	archived, err := strconv.ParseBool(s)
+	if err != nil {
+		http.Error(w, "invalid archived", http.StatusBadRequest)
+		return false, false
+	}
+	return archived, true
+}
+
+func parseOptionalID(w http.ResponseWriter, r *http.Request, param string) (*uuid.UUID, bool) {
+	s := r.URL.Query().Get(param)
+	if s == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		http.Error(w, "invalid "+param, http.StatusBadRequest)
+		return nil, false
+	}
+	return &id, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrListNotFound),
+		errors.Is(err, repository.ErrWorkspaceNotFound),
+		errors.Is(err, repository.ErrFolderNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrFolderNotInWorkspace):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
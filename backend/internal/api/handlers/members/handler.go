@@ -0,0 +1,103 @@
+package members
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Put("/{userID}", h.Add)
+	r.Delete("/{userID}", h.Remove)
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.svc.ListListMembers(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(members)
+}
+
+// Add shares the list with a user. Adding a member again changes nothing.
+func (h *Handler) Add(w http.ResponseWriter, r *http.Request) {
+	listID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AddListMember(r.Context(), listID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Remove stops sharing the list with a user, who is also unassigned from
+// its todos.
+func (h *Handler) Remove(w http.ResponseWriter, r *http.Request) {
+	listID, userID, ok := parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RemoveListMember(r.Context(), listID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request) (listID, userID uuid.UUID, ok bool) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return listID, userID, false
+	}
+	userID, err = uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return listID, userID, false
+	}
+	return listID, userID, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrListNotFound),
+		errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrListMemberNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrNoListAccess):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
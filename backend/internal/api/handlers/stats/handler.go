@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.Get)
+}
+
+func (h *Handler) RegisterBurndownRoutes(r chi.Router) {
+	r.Get("/", h.Burndown)
+}
+
+// Get returns the caller's activity per list and per assignee. The list_id
+// query parameter narrows it to one list, and from and to (YYYY-MM-DD)
+// choose the days.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	var listID *uuid.UUID
+	if s := r.URL.Query().Get("list_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, "invalid list_id", http.StatusBadRequest)
+			return
+		}
+		listID = &id
+	}
+	from, ok := parseDate(w, r, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseDate(w, r, "to")
+	if !ok {
+		return
+	}
+
+	stats, err := h.svc.Stats(r.Context(), listID, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(stats)
+}
+
+// Burndown returns the open and done todos of a list at the end of each day
+// from from to to, as JSON or, with format=csv, as a CSV file.
+func (h *Handler) Burndown(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+	from, ok := parseDate(w, r, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseDate(w, r, "to")
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	burndown, err := h.svc.Burndown(r.Context(), listID, from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if format != "csv" {
+		json.NewEncoder(w).Encode(burndown)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("burndown-%s-%s.csv", burndown.From, burndown.To),
+	}))
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"day", "open", "done"})
+	for _, d := range burndown.Days {
+		cw.Write([]string{d.Day.String(), strconv.Itoa(d.Open), strconv.Itoa(d.Done)})
+	}
+	cw.Flush()
+}
+
+func parseDate(w http.ResponseWriter, r *http.Request, param string) (*models.Date, bool) {
+	s := r.URL.Query().Get(param)
+	if s == "" {
+		return nil, true
+	}
+	d, err := models.ParseDate(s)
+	if err != nil {
+		http.Error(w, "invalid "+param, http.StatusBadRequest)
+		return nil, false
+	}
+	return &d, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidStatsRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
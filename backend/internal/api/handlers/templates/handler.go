@@ -0,0 +1,185 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	apimodels "github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.ListAll)
+	r.Post("/", h.Create)
+	r.Route("/{templateID}", func(r chi.Router) {
+		r.Get("/", h.GetByID)
+		r.Put("/", h.Update)
+		r.Delete("/", h.Delete)
+		r.Post("/instantiate", h.Instantiate)
+	})
+}
+
+func (h *Handler) ListAll(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.svc.ListListTemplates(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	t, ok := decodeTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.CreateListTemplate(r.Context(), t); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(t)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "templateID"))
+	if err != nil {
+		http.Error(w, "invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.svc.GetListTemplate(r.Context(), templateID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "templateID"))
+	if err != nil {
+		http.Error(w, "invalid template ID", http.StatusBadRequest)
+		return
+	}
+	t, ok := decodeTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	t.ID = templateID
+	if err := h.svc.UpdateListTemplate(r.Context(), t); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "templateID"))
+	if err != nil {
+		http.Error(w, "invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteListTemplate(r.Context(), templateID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Instantiate creates a list from the template, filling its placeholders
+// with the variables in the body.
+func (h *Handler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	templateID, err := uuid.Parse(chi.URLParam(r, "templateID"))
+	if err != nil {
+		http.Error(w, "invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var req apimodels.InstantiateListTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.svc.InstantiateListTemplate(r.Context(), templateID, req.Variables, req.StartOn, req.ListLocation)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(list)
+}
+
+func decodeTemplate(w http.ResponseWriter, r *http.Request) (*models.ListTemplate, bool) {
+	var req apimodels.SaveListTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Name == "" {
+		http.Error(w, "invalid template name", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.ListName == "" {
+		http.Error(w, "invalid list name", http.StatusBadRequest)
+		return nil, false
+	}
+	for i, todo := range req.Todos {
+		if todo.Title == "" {
+			http.Error(w, fmt.Sprintf("todo %d has no title", i+1), http.StatusBadRequest)
+			return nil, false
+		}
+		if todo.Recurrence != "" {
+			if _, err := recurrence.Parse(todo.Recurrence); err != nil {
+				http.Error(w, fmt.Sprintf("todo %d: %v", i+1, err), http.StatusBadRequest)
+				return nil, false
+			}
+		}
+	}
+
+	return &models.ListTemplate{Name: req.Name, ListName: req.ListName, Todos: req.Todos}, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var missing *models.MissingVariablesError
+	switch {
+	case errors.As(err, &missing):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrTemplateNotFound),
+		errors.Is(err, repository.ErrWorkspaceNotFound),
+		errors.Is(err, repository.ErrFolderNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrTemplateNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrFolderNotInWorkspace):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -2,12 +2,16 @@ package todos
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
 	"github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/quickadd"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+	"github.com/awnzl/to-do-app/internal/repository"
 	"github.com/awnzl/to-do-app/internal/service"
 )
 
@@ -21,6 +25,7 @@ func NewHandler(svc service.TodoService) *Handler {
 
 func (h *Handler) RegisterCreateRoute(r chi.Router) {
 	r.Post("/", h.Create)
+	r.Post("/quick", h.QuickAdd)
 }
 
 func (h *Handler) RegisterRoutes(r chi.Router) {
@@ -28,6 +33,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Get("/", h.GetByID)
 		r.Put("/", h.Update)
 		r.Delete("/", h.Delete)
+		r.Post("/complete", h.Complete)
 	})
 }
 
@@ -44,8 +50,17 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	todo, err := h.svc.CreateTodo(r.Context(), listID, req.Title, req.Description, req.DueDate)
-	if err != nil {
+	if err := req.Schedule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.svc.CreateTodo(r.Context(), listID, req.Title, req.Description, req.Schedule)
+	switch {
+	case errors.Is(err, service.ErrInvalidTodo):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -54,6 +69,38 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(todo)
 }
 
+// QuickAdd creates a todo from a line of text, such as "Pay rent every
+// month on the 1st #home !high", and echoes how the text was read.
+func (h *Handler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.QuickAddTodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todo, parsed, err := h.svc.QuickAddTodo(r.Context(), listID, req.Text)
+	switch {
+	case errors.Is(err, quickadd.ErrNoTitle), errors.Is(err, service.ErrInvalidTodo):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, repository.ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.QuickAddResponse{Todo: todo, Parsed: parsed})
+}
+
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
 	if err != nil {
@@ -82,6 +129,16 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if req.Recurrence != "" {
+		if _, err := recurrence.Parse(req.Recurrence); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := req.Schedule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	todo, err := h.svc.GetTodo(r.Context(), todoID)
 	if err != nil {
@@ -91,10 +148,47 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	todo.Title = req.Title
 	todo.Description = req.Description
-	todo.DueDate = req.DueDate
 	todo.Status = req.Status
+	todo.Tags = req.Tags
+	todo.Priority = req.Priority
+	todo.Recurrence = req.Recurrence
+	if err := h.svc.SetTodoSchedule(r.Context(), todo, req.Schedule); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	if err := h.svc.UpdateTodo(r.Context(), todo); err != nil {
+	err = h.svc.UpdateTodo(r.Context(), todo)
+	switch {
+	case errors.Is(err, service.ErrOpenBlockers):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Complete marks a todo as done. It fails while todos it depends on are
+// open, unless force=true is given.
+func (h *Handler) Complete(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoID"))
+	if err != nil {
+		http.Error(w, "invalid todo ID", http.StatusBadRequest)
+		return
+	}
+
+	force := r.URL.Query().Get("force") == "true"
+	err = h.svc.CompleteTodo(r.Context(), todoID, force)
+	switch {
+	case errors.Is(err, repository.ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrOpenBlockers):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
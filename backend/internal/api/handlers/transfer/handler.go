@@ -0,0 +1,150 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	apimodels "github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/logging"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+	"github.com/awnzl/to-do-app/internal/transfer"
+)
+
+const (
+	maxImportSize   = 10 << 20
+	defaultListName = "Imported"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterExportRoutes(r chi.Router) {
+	r.Get("/", h.Export)
+}
+
+func (h *Handler) RegisterImportRoutes(r chi.Router) {
+	r.Post("/", h.Import)
+}
+
+// Export writes a list with its todos in the format given by the format
+// query parameter, JSON by default. A list that does not exist is answered
+// with 404. A failure while writing is answered with 500 unless part of the
+// file was sent already; then it is only logged and the client gets a
+// truncated file.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "listID"))
+	if err != nil {
+		http.Error(w, "invalid list ID", http.StatusBadRequest)
+		return
+	}
+
+	format := transfer.FormatJSON
+	if f := r.URL.Query().Get("format"); f != "" {
+		if format, err = transfer.ParseFormat(f); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.svc.ExportList(r.Context(), listID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("%s.%s", list.Name, format.Extension()),
+	}))
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	if err := transfer.Export(ww, format, []*models.ListWithTodos{list}); err != nil {
+		if ww.BytesWritten() == 0 {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		logging.FromContext(r.Context()).ErrorContext(r.Context(), "export list",
+			"list_id", listID, "bytes", ww.BytesWritten(), "error", err)
+	}
+}
+
+// Import parses the request body and creates the lists and todos it
+// contains. Nothing is created if any entry is invalid; with dry_run the
+// parsed result is returned without creating anything.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	format, err := transfer.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if s := r.URL.Query().Get("dry_run"); s != "" {
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			http.Error(w, "invalid dry_run", http.StatusBadRequest)
+			return
+		}
+	}
+
+	listName := r.URL.Query().Get("list_name")
+	if listName == "" {
+		listName = defaultListName
+	}
+
+	lists, rowErrors, err := transfer.Parse(http.MaxBytesReader(w, r.Body, maxImportSize), format, listName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := apimodels.ImportResponse{
+		DryRun: dryRun,
+		Lists:  lists,
+		Errors: rowErrors,
+	}
+	if resp.Lists == nil {
+		resp.Lists = []*models.ListWithTodos{}
+	}
+	if resp.Errors == nil {
+		resp.Errors = []transfer.RowError{}
+	}
+
+	switch {
+	case dryRun:
+		w.WriteHeader(http.StatusOK)
+	case len(rowErrors) > 0:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	default:
+		if err := h.svc.ImportLists(r.Context(), lists); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
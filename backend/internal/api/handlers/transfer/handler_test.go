@@ -0,0 +1,45 @@
+package transfer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// fakeService exports a single list.
+type fakeService struct {
+	service.TodoService
+	list *models.ListWithTodos
+}
+
+func (f *fakeService) ExportList(_ context.Context, id uuid.UUID) (*models.ListWithTodos, error) {
+	if id != f.list.ID {
+		return nil, repository.ErrListNotFound
+	}
+	return f.list, nil
+}
+
+func TestExport(t *testing.T) {
+	svc := &fakeService{list: &models.ListWithTodos{TodoList: models.TodoList{ID: uuid.New(), Name: "Home"}}}
+	r := chi.NewRouter()
+	r.Route("/lists/{listID}/export", NewHandler(svc).RegisterExportRoutes)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lists/"+svc.list.ID.String()+"/export", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "Home.json")
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lists/"+uuid.NewString()+"/export", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/transfer"
+)
+
+func (h *Handler) RegisterImportJobRoutes(r chi.Router) {
+	r.Post("/", h.CreateImportJob)
+	r.Get("/{jobID}", h.GetImportJob)
+}
+
+// CreateImportJob queues the export file of another tool in the request
+// body for import. Uploading a file that was imported before returns the
+// existing job with 200 instead of 202.
+func (h *Handler) CreateImportJob(w http.ResponseWriter, r *http.Request) {
+	source, err := transfer.LookupSource(r.URL.Query().Get("source"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	listName := r.URL.Query().Get("list_name")
+	if listName == "" {
+		listName = defaultListName
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if len(payload) == 0 {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+
+	job, created, err := h.svc.CreateImportJob(r.Context(), source, listName, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", r.URL.Path+"/"+job.ID.String())
+	if created {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *Handler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		http.Error(w, "invalid job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.svc.GetImportJob(r.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrImportJobNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,143 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	apimodels "github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Route("/{userID}", func(r chi.Router) {
+		r.Get("/", h.GetByID)
+		r.Get("/notifications", h.GetNotificationPreferences)
+		r.Put("/notifications", h.UpdateNotificationPreferences)
+	})
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req apimodels.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Username == "" {
+		http.Error(w, "invalid username", http.StatusBadRequest)
+		return
+	}
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		http.Error(w, "invalid email", http.StatusBadRequest)
+		return
+	}
+	if req.TimeZone == "" {
+		req.TimeZone = "UTC"
+	}
+	if _, err := models.LoadTimeZone(req.TimeZone); err != nil {
+		http.Error(w, "invalid time zone", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.svc.CreateUser(r.Context(), req.Username, req.Email, req.TimeZone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.svc.GetUser(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.svc.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(prefs)
+}
+
+func (h *Handler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		http.Error(w, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req apimodels.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.DigestHour < 0 || req.DigestHour > 23 {
+		http.Error(w, "digest_hour must be between 0 and 23", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.svc.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	prefs.DigestEnabled = req.DigestEnabled
+	prefs.DigestHour = req.DigestHour
+	prefs.AssignmentEmails = req.AssignmentEmails
+	prefs.MentionEmails = req.MentionEmails
+
+	if err := h.svc.UpdateNotificationPreferences(r.Context(), prefs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrUserNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
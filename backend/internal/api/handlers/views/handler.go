@@ -0,0 +1,175 @@
+package views
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/filter"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+const defaultPageSize = 50
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.ListAll)
+	r.Post("/", h.Create)
+	r.Route("/{viewID}", func(r chi.Router) {
+		r.Get("/", h.GetByID)
+		r.Put("/", h.Update)
+		r.Delete("/", h.Delete)
+		r.Get("/todos", h.ListTodos)
+	})
+}
+
+func (h *Handler) ListAll(w http.ResponseWriter, r *http.Request) {
+	views, err := h.svc.ListViews(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(views)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.svc.CreateView(r.Context(), req.Name, req.Query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(view)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewID"))
+	if err != nil {
+		http.Error(w, "invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.svc.GetView(r.Context(), viewID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(view)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewID"))
+	if err != nil {
+		http.Error(w, "invalid view ID", http.StatusBadRequest)
+		return
+	}
+	req, ok := decodeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.svc.UpdateView(r.Context(), viewID, req.Name, req.Query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(view)
+}
+
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewID"))
+	if err != nil {
+		http.Error(w, "invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteView(r.Context(), viewID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTodos evaluates the view and returns a page of the matching todos.
+// The page is chosen with the limit and offset query parameters.
+func (h *Handler) ListTodos(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewID"))
+	if err != nil {
+		http.Error(w, "invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := defaultPageSize, 0
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < 1 || limit > service.MaxPageSize {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(service.MaxPageSize), http.StatusBadRequest)
+			return
+		}
+	}
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+			http.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.svc.ListViewTodos(r.Context(), viewID, limit, offset)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*models.SaveViewRequest, bool) {
+	var req models.SaveViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Name == "" {
+		http.Error(w, "invalid view name", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var filterErr *filter.Error
+	switch {
+	case errors.As(err, &filterErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrViewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrViewNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrNoUser):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,331 @@
+package workspaces
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	apimodels "github.com/awnzl/to-do-app/internal/api/models"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+type Handler struct {
+	svc service.TodoService
+}
+
+func NewHandler(svc service.TodoService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.ListAll)
+	r.Post("/", h.Create)
+	r.Route("/{workspaceID}", func(r chi.Router) {
+		r.Get("/", h.GetByID)
+		r.Put("/", h.Update)
+		r.Delete("/", h.Delete)
+		r.Post("/restore", h.Restore)
+		r.Get("/lists", h.ListLists)
+
+		r.Get("/members", h.ListMembers)
+		r.Put("/members/{userID}", h.AddMember)
+		r.Delete("/members/{userID}", h.RemoveMember)
+
+		r.Get("/folders", h.ListFolders)
+		r.Post("/folders", h.CreateFolder)
+		r.Put("/folders/{folderID}", h.UpdateFolder)
+		r.Delete("/folders/{folderID}", h.DeleteFolder)
+	})
+}
+
+// ListAll returns the workspaces of the user in X-User-ID.
+func (h *Handler) ListAll(w http.ResponseWriter, r *http.Request) {
+	workspaces, err := h.svc.ListWorkspaces(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(workspaces)
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	name, ok := decodeName(w, r, "workspace")
+	if !ok {
+		return
+	}
+
+	workspace, err := h.svc.CreateWorkspace(r.Context(), name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(workspace)
+}
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+
+	workspace, err := h.svc.GetWorkspace(r.Context(), workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(workspace)
+}
+
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	name, ok := decodeName(w, r, "workspace")
+	if !ok {
+		return
+	}
+
+	workspace, err := h.svc.UpdateWorkspace(r.Context(), workspaceID, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(workspace)
+}
+
+// Delete marks the workspace as deleted, hiding it and its lists until it
+// is restored.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteWorkspace(r.Context(), workspaceID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+
+	workspace, err := h.svc.RestoreWorkspace(r.Context(), workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(workspace)
+}
+
+// ListLists returns the lists of the workspace, with the archived ones
+// when archived=true.
+func (h *Handler) ListLists(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	archived := false
+	if s := r.URL.Query().Get("archived"); s != "" {
+		var err error
+		if archived, err = strconv.ParseBool(s); err != nil {
+			http.Error(w, "invalid archived", http.StatusBadRequest)
+			return
+		}
+	}
+
+	lists, err := h.svc.ListLists(r.Context(), models.ListFilter{WorkspaceID: &workspaceID, Archived: archived})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(lists)
+}
+
+func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+
+	members, err := h.svc.ListWorkspaceMembers(r.Context(), workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(members)
+}
+
+// AddMember gives a user access to all lists of the workspace. Adding a
+// member again changes nothing.
+func (h *Handler) AddMember(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	userID, ok := parseID(w, r, "userID", "user")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.AddWorkspaceMember(r.Context(), workspaceID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMember removes a user from the workspace. They are unassigned from
+// the todos of lists they can no longer access.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	userID, ok := parseID(w, r, "userID", "user")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RemoveWorkspaceMember(r.Context(), workspaceID, userID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListFolders(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+
+	folders, err := h.svc.ListFolders(r.Context(), workspaceID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(folders)
+}
+
+func (h *Handler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	name, ok := decodeName(w, r, "folder")
+	if !ok {
+		return
+	}
+
+	folder, err := h.svc.CreateFolder(r.Context(), workspaceID, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(folder)
+}
+
+func (h *Handler) UpdateFolder(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	folderID, ok := parseID(w, r, "folderID", "folder")
+	if !ok {
+		return
+	}
+	name, ok := decodeName(w, r, "folder")
+	if !ok {
+		return
+	}
+
+	folder, err := h.svc.UpdateFolder(r.Context(), workspaceID, folderID, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(folder)
+}
+
+// DeleteFolder removes a folder. Its lists stay in the workspace.
+func (h *Handler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := parseID(w, r, "workspaceID", "workspace")
+	if !ok {
+		return
+	}
+	folderID, ok := parseID(w, r, "folderID", "folder")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteFolder(r.Context(), workspaceID, folderID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param, what string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		http.Error(w, "invalid "+what+" ID", http.StatusBadRequest)
+		return id, false
+	}
+	return id, true
+}
+
+// decodeName reads the name of a workspace or folder from the body.
+func decodeName(w http.ResponseWriter, r *http.Request, what string) (string, bool) {
+	var req apimodels.SaveWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	if req.Name == "" {
+		http.Error(w, "invalid "+what+" name", http.StatusBadRequest)
+		return "", false
+	}
+	return req.Name, true
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrWorkspaceNotFound),
+		errors.Is(err, repository.ErrWorkspaceMemberNotFound),
+		errors.Is(err, repository.ErrFolderNotFound),
+		errors.Is(err, repository.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrFolderNameTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
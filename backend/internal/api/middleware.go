@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+)
+
+// UserIDHeader carries the ID of the calling user, set by the gateway in
+// front of the API.
+const UserIDHeader = "X-User-ID"
+
+// userContext stores the calling user from UserIDHeader in the request
+// context. Requests without the header are anonymous.
+func userContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(UserIDHeader)
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, err := uuid.Parse(header)
+		if err != nil {
+			http.Error(w, "invalid "+UserIDHeader+" header", http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(identity.WithUserID(r.Context(), userID)))
+	})
+}
@@ -1,32 +1,107 @@
 package models
 
 import (
-	"time"
-
 	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
 )
 
 type CreateListRequest struct {
 	Name string `json:"name"`
+	models.ListLocation
 }
 
 type UpdateListRequest struct {
 	Name string `json:"name"`
 }
 
+type MoveListRequest struct {
+	models.ListLocation
+}
+
+type AutoArchiveRequest struct {
+	Days *int `json:"days"`
+}
+
+type CloneListRequest struct {
+	models.CloneOptions
+}
+
+type SaveListTemplateRequest struct {
+	Name     string               `json:"name"`
+	ListName string               `json:"list_name"`
+	Todos    models.TemplateTodos `json:"todos"`
+}
+
+type InstantiateListTemplateRequest struct {
+	Variables map[string]string `json:"variables"`
+	StartOn   *models.Date      `json:"start_on,omitempty"`
+	models.ListLocation
+}
+
 type CreateTodoRequest struct {
-	Title       string     `json:"title"`
-	Description string     `json:"description,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	models.Schedule
 }
 
 type UpdateTodoRequest struct {
-	Title       string     `json:"title,omitempty"`
-	Description string     `json:"description,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	Status      bool       `json:"status"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Status      bool            `json:"status"`
+	Tags        []string        `json:"tags,omitempty"`
+	Priority    models.Priority `json:"priority,omitempty"`
+	Recurrence  string          `json:"recurrence,omitempty"`
+	models.Schedule
+}
+
+type QuickAddTodoRequest struct {
+	Text string `json:"text"`
 }
 
 type MoveTodoRequest struct {
 	TargetListID uuid.UUID `json:"target_list_id"`
 }
+
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	DigestEnabled    bool `json:"digest_enabled"`
+	DigestHour       int  `json:"digest_hour"`
+	AssignmentEmails bool `json:"assignment_emails"`
+	MentionEmails    bool `json:"mention_emails"`
+}
+
+type CreateCommentRequest struct {
+	Body     string     `json:"body"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+type SaveViewRequest struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+type SaveBoardColumnRequest struct {
+	Name     string `json:"name"`
+	WIPLimit *int   `json:"wip_limit"`
+	Position *int   `json:"position,omitempty"`
+}
+
+type PlaceTodoRequest struct {
+	ColumnID *uuid.UUID `json:"column_id"`
+	Position *int       `json:"position,omitempty"`
+}
+
+// SaveWorkspaceRequest names a workspace or a folder.
+type SaveWorkspaceRequest struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/quickadd"
+	"github.com/awnzl/to-do-app/internal/transfer"
+)
+
+type ImportResponse struct {
+	DryRun bool                    `json:"dry_run"`
+	Lists  []*models.ListWithTodos `json:"lists"`
+	Errors []transfer.RowError     `json:"errors"`
+}
+
+// QuickAddResponse is the todo created from a quick add text together with
+// how the text was read.
+type QuickAddResponse struct {
+	Todo   *models.Todo     `json:"todo"`
+	Parsed *quickadd.Result `json:"parsed"`
+}
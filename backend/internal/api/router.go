@@ -1,24 +1,75 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/awnzl/to-do-app/internal/api/handlers/agenda"
+	"github.com/awnzl/to-do-app/internal/api/handlers/assignees"
+	"github.com/awnzl/to-do-app/internal/api/handlers/attachments"
+	"github.com/awnzl/to-do-app/internal/api/handlers/backup"
+	"github.com/awnzl/to-do-app/internal/api/handlers/board"
+	"github.com/awnzl/to-do-app/internal/api/handlers/caldav"
+	"github.com/awnzl/to-do-app/internal/api/handlers/comments"
+	"github.com/awnzl/to-do-app/internal/api/handlers/dependencies"
+	"github.com/awnzl/to-do-app/internal/api/handlers/feeds"
 	"github.com/awnzl/to-do-app/internal/api/handlers/lists"
+	"github.com/awnzl/to-do-app/internal/api/handlers/members"
+	"github.com/awnzl/to-do-app/internal/api/handlers/stats"
+	"github.com/awnzl/to-do-app/internal/api/handlers/templates"
 	"github.com/awnzl/to-do-app/internal/api/handlers/todos"
+	"github.com/awnzl/to-do-app/internal/api/handlers/transfer"
+	"github.com/awnzl/to-do-app/internal/api/handlers/users"
+	"github.com/awnzl/to-do-app/internal/api/handlers/views"
+	"github.com/awnzl/to-do-app/internal/api/handlers/workspaces"
+	"github.com/awnzl/to-do-app/internal/logging"
 	"github.com/awnzl/to-do-app/internal/service"
 )
 
-func NewRouter(svc service.TodoService) *chi.Mux {
+// NewRouter routes the API to svc. The given middlewares wrap every
+// request before the router's own.
+func NewRouter(svc service.TodoService, middlewares ...func(http.Handler) http.Handler) *chi.Mux {
+	for _, method := range caldav.Methods {
+		chi.RegisterMethod(method)
+	}
+
 	r := chi.NewRouter()
 
 	// Middleware
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(middlewares...)
 	r.Use(middleware.RequestID)
+	r.Use(userContext)
+	r.Use(logging.Middleware)
 
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
+		// Users endpoints
+		r.Route("/users", func(r chi.Router) {
+			usersHandler := users.NewHandler(svc)
+			usersHandler.RegisterRoutes(r)
+
+			r.Route("/{userID}/feeds", func(r chi.Router) {
+				feedsHandler := feeds.NewHandler(svc)
+				feedsHandler.RegisterUserRoutes(r)
+			})
+
+			r.Route("/{userID}/assigned", func(r chi.Router) {
+				assigneesHandler := assignees.NewHandler(svc)
+				assigneesHandler.RegisterUserRoutes(r)
+			})
+
+			r.Route("/{userID}/backup", func(r chi.Router) {
+				backupHandler := backup.NewHandler(svc)
+				backupHandler.RegisterBackupRoutes(r)
+			})
+			r.Route("/{userID}/restore", func(r chi.Router) {
+				backupHandler := backup.NewHandler(svc)
+				backupHandler.RegisterRestoreRoutes(r)
+			})
+		})
+
 		// Lists endpoints
 		r.Route("/lists", func(r chi.Router) {
 			listsHandler := lists.NewHandler(svc)
@@ -30,13 +81,137 @@ func NewRouter(svc service.TodoService) *chi.Mux {
 				todosHandler.RegisterCreateRoute(r)
 				todosHandler.RegisterRoutes(r)
 			})
+
+			r.Route("/{listID}/feeds", func(r chi.Router) {
+				feedsHandler := feeds.NewHandler(svc)
+				feedsHandler.RegisterListRoutes(r)
+			})
+
+			r.Route("/{listID}/members", func(r chi.Router) {
+				membersHandler := members.NewHandler(svc)
+				membersHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/{listID}/export", func(r chi.Router) {
+				transferHandler := transfer.NewHandler(svc)
+				transferHandler.RegisterExportRoutes(r)
+			})
+
+			r.Route("/{listID}/dependencies", func(r chi.Router) {
+				dependenciesHandler := dependencies.NewHandler(svc)
+				dependenciesHandler.RegisterGraphRoutes(r)
+			})
+
+			r.Route("/{listID}/next", func(r chi.Router) {
+				dependenciesHandler := dependencies.NewHandler(svc)
+				dependenciesHandler.RegisterNextRoutes(r)
+			})
+
+			r.Route("/{listID}/columns", func(r chi.Router) {
+				boardHandler := board.NewHandler(svc)
+				boardHandler.RegisterColumnRoutes(r)
+			})
+
+			r.Route("/{listID}/board", func(r chi.Router) {
+				boardHandler := board.NewHandler(svc)
+				boardHandler.RegisterBoardRoutes(r)
+			})
+
+			r.Route("/{listID}/burndown", func(r chi.Router) {
+				statsHandler := stats.NewHandler(svc)
+				statsHandler.RegisterBurndownRoutes(r)
+			})
 		})
 
 		// Individual todo endpoints
 		r.Route("/todos", func(r chi.Router) {
 			todosHandler := todos.NewHandler(svc)
 			todosHandler.RegisterRoutes(r)
+
+			r.Route("/{todoID}/attachments", func(r chi.Router) {
+				attachmentsHandler := attachments.NewHandler(svc)
+				attachmentsHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/{todoID}/assignees", func(r chi.Router) {
+				assigneesHandler := assignees.NewHandler(svc)
+				assigneesHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/{todoID}/comments", func(r chi.Router) {
+				commentsHandler := comments.NewHandler(svc)
+				commentsHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/{todoID}/dependencies", func(r chi.Router) {
+				dependenciesHandler := dependencies.NewHandler(svc)
+				dependenciesHandler.RegisterRoutes(r)
+			})
+
+			r.Route("/{todoID}/column", func(r chi.Router) {
+				boardHandler := board.NewHandler(svc)
+				boardHandler.RegisterTodoRoutes(r)
+			})
+		})
+
+		// List template endpoints
+		r.Route("/templates", func(r chi.Router) {
+			templatesHandler := templates.NewHandler(svc)
+			templatesHandler.RegisterRoutes(r)
+		})
+
+		// Workspace endpoints
+		r.Route("/workspaces", func(r chi.Router) {
+			workspacesHandler := workspaces.NewHandler(svc)
+			workspacesHandler.RegisterRoutes(r)
+		})
+
+		// Saved views endpoints
+		r.Route("/views", func(r chi.Router) {
+			viewsHandler := views.NewHandler(svc)
+			viewsHandler.RegisterRoutes(r)
+		})
+
+		// Agenda endpoint
+		r.Route("/agenda", func(r chi.Router) {
+			agendaHandler := agenda.NewHandler(svc)
+			agendaHandler.RegisterRoutes(r)
+		})
+
+		// Stats endpoint
+		r.Route("/stats", func(r chi.Router) {
+			statsHandler := stats.NewHandler(svc)
+			statsHandler.RegisterRoutes(r)
+		})
+
+		// Import endpoints
+		r.Route("/import", func(r chi.Router) {
+			transferHandler := transfer.NewHandler(svc)
+			transferHandler.RegisterImportRoutes(r)
 		})
+		r.Route("/imports", func(r chi.Router) {
+			transferHandler := transfer.NewHandler(svc)
+			transferHandler.RegisterImportJobRoutes(r)
+		})
+
+		// Calendar feed endpoints
+		r.Route("/feeds", func(r chi.Router) {
+			feedsHandler := feeds.NewHandler(svc)
+			feedsHandler.RegisterRoutes(r)
+		})
+		r.Route("/calendar", func(r chi.Router) {
+			feedsHandler := feeds.NewHandler(svc)
+			feedsHandler.RegisterCalendarRoutes(r)
+		})
+	})
+
+	// CalDAV endpoints
+	r.Route(caldav.Prefix, func(r chi.Router) {
+		caldavHandler := caldav.NewHandler(svc)
+		caldavHandler.RegisterRoutes(r)
+	})
+	r.HandleFunc("/.well-known/caldav", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, caldav.Prefix+"/", http.StatusMovedPermanently)
 	})
 
 	return r
@@ -0,0 +1,300 @@
+// Package backup writes and reads account archives: gzip compressed tar
+// files holding a manifest and one NDJSON file per kind of record.
+//
+// The manifest names the format version and the SHA-256 checksum and record
+// count of every file, which Read verifies before returning any data.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+const (
+	// Format identifies backup archives in their manifest.
+	Format = "to-do-app-backup"
+	// Version is the newest archive version Write produces and Read
+	// understands. Version 2 added attachments.
+	Version = 2
+
+	manifestName = "manifest.json"
+
+	// MaxSize bounds the uncompressed size of the archives Read accepts.
+	MaxSize = 1 << 30
+)
+
+var ErrInvalidArchive = errors.New("invalid backup archive")
+var ErrArchiveTooLarge = fmt.Errorf("backup archive is larger than %d MiB uncompressed", MaxSize>>20)
+
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uuid.UUID `json:"user_id"`
+	Files     []File    `json:"files"`
+}
+
+type File struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// section is one NDJSON file of the archive, present since the given
+// version.
+type section struct {
+	name   string
+	since  int
+	encode func(data *models.AccountData) ([]byte, int, error)
+	decode func(content []byte, data *models.AccountData) (int, error)
+}
+
+var sections = []section{
+	{
+		name:  "user.ndjson",
+		since: 1,
+		encode: func(data *models.AccountData) ([]byte, int, error) {
+			return encodeNDJSON([]*models.User{data.User})
+		},
+		decode: func(content []byte, data *models.AccountData) (int, error) {
+			users, err := decodeNDJSON[*models.User](content)
+			if err != nil {
+				return 0, err
+			}
+			if len(users) != 1 {
+				return 0, fmt.Errorf("expected one user, got %d", len(users))
+			}
+			data.User = users[0]
+			return 1, nil
+		},
+	},
+	records("lists.ndjson", 1, func(data *models.AccountData) *[]*models.TodoList { return &data.Lists }),
+	records("todos.ndjson", 1, func(data *models.AccountData) *[]*models.Todo { return &data.Todos }),
+	records("history.ndjson", 1, func(data *models.AccountData) *[]*models.TodoChange { return &data.History }),
+	records("attachments.ndjson", 2, func(data *models.AccountData) *[]*models.Attachment { return &data.Attachments }),
+}
+
+// records is a section holding one of the record slices of AccountData.
+func records[T any](name string, since int, field func(data *models.AccountData) *[]T) section {
+	return section{
+		name:  name,
+		since: since,
+		encode: func(data *models.AccountData) ([]byte, int, error) {
+			return encodeNDJSON(*field(data))
+		},
+		decode: func(content []byte, data *models.AccountData) (int, error) {
+			recs, err := decodeNDJSON[T](content)
+			*field(data) = recs
+			return len(recs), err
+		},
+	}
+}
+
+// Write writes data as an archive.
+func Write(w io.Writer, data *models.AccountData, createdAt time.Time) error {
+	if data.User == nil {
+		return errors.New("backup needs a user")
+	}
+
+	manifest := Manifest{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: createdAt.UTC(),
+		UserID:    data.User.ID,
+	}
+	contents := make([][]byte, len(sections))
+	for i, s := range sections {
+		content, n, err := s.encode(data)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", s.name, err)
+		}
+		sum := sha256.Sum256(content)
+		contents[i] = content
+		manifest.Files = append(manifest.Files, File{Name: s.name, Records: n, SHA256: hex.EncodeToString(sum[:])})
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	// the manifest goes first so readers learn the version before anything
+	// else
+	if err := writeFile(tw, manifestName, manifestContent, createdAt); err != nil {
+		return err
+	}
+	for i, s := range sections {
+		if err := writeFile(tw, s.name, contents[i], createdAt); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// Read reads an archive written by Write. It fails with ErrInvalidArchive
+// when the archive is newer than Version, a file is missing, a checksum or
+// record count does not match or a record belongs to a list or todo that is
+// not part of the archive, and with ErrArchiveTooLarge when it unpacks to
+// more than MaxSize. Archives of older versions lack the newer sections.
+func Read(r io.Reader) (*models.AccountData, *Manifest, error) {
+	return read(r, MaxSize)
+}
+
+func read(r io.Reader, maxSize int64) (*models.AccountData, *Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer gr.Close()
+
+	// one byte more than allowed tells a cut off archive from one too large
+	lr := &io.LimitedReader{R: gr, N: maxSize + 1}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(lr)
+	for {
+		hdr, err := tr.Next()
+		if lr.N <= 0 {
+			return nil, nil, ErrArchiveTooLarge
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if lr.N <= 0 {
+			return nil, nil, ErrArchiveTooLarge
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: read %s: %w", ErrInvalidArchive, hdr.Name, err)
+		}
+		files[hdr.Name] = content
+	}
+
+	var manifest Manifest
+	content, ok := files[manifestName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no %s", ErrInvalidArchive, manifestName)
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, manifestName, err)
+	}
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, manifest.Format)
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, manifest.Version)
+	}
+
+	listed := make(map[string]File, len(manifest.Files))
+	for _, f := range manifest.Files {
+		content, ok := files[f.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, f.Name)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, nil, fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidArchive, f.Name)
+		}
+		listed[f.Name] = f
+	}
+
+	data := &models.AccountData{}
+	for _, s := range sections {
+		if manifest.Version < s.since {
+			continue
+		}
+		f, ok := listed[s.name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, s.name)
+		}
+		n, err := s.decode(files[s.name], data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, s.name, err)
+		}
+		if n != f.Records {
+			return nil, nil, fmt.Errorf(
+				"%w: %s has %d records, manifest says %d", ErrInvalidArchive, s.name, n, f.Records,
+			)
+		}
+	}
+
+	lists := make(map[uuid.UUID]bool, len(data.Lists))
+	for _, l := range data.Lists {
+		lists[l.ID] = true
+	}
+	todos := make(map[uuid.UUID]bool, len(data.Todos))
+	for _, t := range data.Todos {
+		if !lists[t.ListID] {
+			return nil, nil, fmt.Errorf("%w: todo %s belongs to a list not in the archive", ErrInvalidArchive, t.ID)
+		}
+		todos[t.ID] = true
+	}
+	for _, a := range data.Attachments {
+		if !todos[a.TodoID] {
+			return nil, nil, fmt.Errorf(
+				"%w: attachment %s belongs to a todo not in the archive", ErrInvalidArchive, a.ID,
+			)
+		}
+	}
+
+	return data, &manifest, nil
+}
+
+func encodeNDJSON[T any](recs []T) ([]byte, int, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return nil, 0, err
+		}
+	}
+	return buf.Bytes(), len(recs), nil
+}
+
+func decodeNDJSON[T any](content []byte) ([]T, error) {
+	var recs []T
+	dec := json.NewDecoder(bytes.NewReader(content))
+	for {
+		var rec T
+		if err := dec.Decode(&rec); errors.Is(err, io.EOF) {
+			return recs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(recs)+1, err)
+		}
+		recs = append(recs, rec)
+	}
+}
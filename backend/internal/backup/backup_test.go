@@ -0,0 +1,185 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func sampleData() *models.AccountData {
+	created := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	due := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Username: "ada", Email: "ada@example.com", TimeZone: "UTC", CreatedAt: created}
+	list := &models.TodoList{ID: uuid.New(), Name: "Home", OwnerID: &user.ID, CreatedAt: created}
+	todo := &models.Todo{
+		ID: uuid.New(), ListID: list.ID, Title: "Water plants", DueDate: &due,
+		Tags: []string{"garden"}, CreatedAt: created, UpdatedAt: created,
+	}
+	return &models.AccountData{
+		User:    user,
+		Lists:   []*models.TodoList{list},
+		Todos:   []*models.Todo{todo},
+		History: []*models.TodoChange{{Seq: 7, ListID: list.ID, TodoID: todo.ID, ChangedAt: created}},
+		Attachments: []*models.Attachment{{
+			ID: uuid.New(), TodoID: todo.ID, Filename: "plan.pdf", ContentType: "application/pdf", Size: 42,
+			SHA256: strings.Repeat("ab", 32), StorageKey: "attachments/plan", CreatedAt: created,
+		}},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := sampleData()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data, time.Now()))
+
+	got, manifest, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, Version, manifest.Version)
+	assert.Equal(t, data.User.ID, manifest.UserID)
+	assert.Equal(t, data, got)
+}
+
+// rewrite copies an archive, letting edit change the content of each file.
+func rewrite(t *testing.T, archive []byte, edit func(name string, content []byte) []byte) []byte {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if content = edit(hdr.Name, content); content == nil {
+			continue
+		}
+		hdr.Size = int64(len(content))
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return out.Bytes()
+}
+
+func TestReadVersion1(t *testing.T) {
+	data := sampleData()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data, time.Now()))
+
+	// version 1 archives have no attachments
+	archive := rewrite(t, buf.Bytes(), func(name string, content []byte) []byte {
+		switch name {
+		case "attachments.ndjson":
+			return nil
+		case manifestName:
+			var m Manifest
+			require.NoError(t, json.Unmarshal(content, &m))
+			m.Version = 1
+			m.Files = m.Files[:len(m.Files)-1]
+			content, err := json.Marshal(m)
+			require.NoError(t, err)
+			return content
+		}
+		return content
+	})
+
+	got, manifest, err := Read(bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.Equal(t, 1, manifest.Version)
+	assert.Empty(t, got.Attachments)
+	assert.Equal(t, data.Todos, got.Todos)
+}
+
+func TestReadRejectsInvalidArchives(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleData(), time.Now()))
+	archive := buf.Bytes()
+
+	tests := []struct {
+		name string
+		edit func(name string, content []byte) []byte
+	}{
+		{
+			name: "tampered file",
+			edit: func(name string, content []byte) []byte {
+				if name == "todos.ndjson" {
+					return bytes.Replace(content, []byte("Water"), []byte("Drain"), 1)
+				}
+				return content
+			},
+		},
+		{
+			name: "missing file",
+			edit: func(name string, content []byte) []byte {
+				if name == "lists.ndjson" {
+					return nil
+				}
+				return content
+			},
+		},
+		{
+			name: "missing manifest",
+			edit: func(name string, content []byte) []byte {
+				if name == manifestName {
+					return nil
+				}
+				return content
+			},
+		},
+		{
+			name: "newer version",
+			edit: func(name string, content []byte) []byte {
+				if name != manifestName {
+					return content
+				}
+				var m Manifest
+				require.NoError(t, json.Unmarshal(content, &m))
+				m.Version = Version + 1
+				content, err := json.Marshal(m)
+				require.NoError(t, err)
+				return content
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(bytes.NewReader(rewrite(t, archive, tt.edit)))
+			assert.ErrorIs(t, err, ErrInvalidArchive)
+		})
+	}
+
+	_, _, err := Read(bytes.NewReader([]byte("not an archive")))
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+}
+
+func TestReadRejectsLargeArchives(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, sampleData(), time.Now()))
+
+	_, _, err := read(bytes.NewReader(buf.Bytes()), 1<<20)
+	require.NoError(t, err)
+
+	_, _, err = read(bytes.NewReader(buf.Bytes()), 1024)
+	assert.ErrorIs(t, err, ErrArchiveTooLarge)
+}
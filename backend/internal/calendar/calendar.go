@@ -0,0 +1,128 @@
+// Package calendar maps todos onto iCalendar components.
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/ical"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+)
+
+const ProdID = "-//awnzl//to-do-app//EN"
+
+// Components selects which component types a feed publishes per todo.
+// Task-aware clients read VTODO, most calendar apps only show VEVENT.
+type Components struct {
+	Todos  bool
+	Events bool
+}
+
+// NewCalendar returns an empty VCALENDAR with the given display name.
+func NewCalendar(name string) *ical.Component {
+	cal := ical.NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+	cal.AddText("PRODID", ProdID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	if name != "" {
+		cal.AddText("X-WR-CALNAME", name)
+	}
+	return cal
+}
+
+// Feed builds the calendar of a feed. Only todos with a due date are
+// included.
+func Feed(feed *models.Feed, c Components) *ical.Component {
+	cal := NewCalendar(feed.Name)
+	for _, todo := range feed.Todos {
+		if todo.DueDate == nil && todo.DueOn == nil {
+			continue
+		}
+		if c.Todos {
+			cal.AddComponent(TodoComponent(todo))
+		}
+		if c.Events {
+			cal.AddComponent(EventComponent(todo))
+		}
+	}
+	return cal
+}
+
+// UID returns the iCalendar UID of a todo.
+func UID(todoID uuid.UUID) string {
+	return todoID.String() + "@to-do-app"
+}
+
+// TodoComponent maps a todo onto a VTODO.
+func TodoComponent(todo *models.Todo) *ical.Component {
+	c := ical.NewComponent("VTODO")
+	c.Add("UID", UID(todo.ID))
+	c.AddTime("DTSTAMP", todo.UpdatedAt)
+	c.AddTime("CREATED", todo.CreatedAt)
+	c.AddTime("LAST-MODIFIED", todo.UpdatedAt)
+	c.AddText("SUMMARY", todo.Title)
+	if todo.Description != "" {
+		c.AddText("DESCRIPTION", todo.Description)
+	}
+	switch {
+	case todo.DueDate != nil:
+		c.AddTime("DUE", *todo.DueDate)
+	case todo.DueOn != nil:
+		c.AddDate("DUE", todo.DueOn.In(time.UTC))
+	}
+	addRecurrence(c, todo)
+	if todo.Status {
+		c.Add("STATUS", "COMPLETED")
+		c.Add("PERCENT-COMPLETE", "100")
+		if todo.CompletedAt != nil {
+			c.AddTime("COMPLETED", *todo.CompletedAt)
+		}
+	} else {
+		c.Add("STATUS", "NEEDS-ACTION")
+	}
+	return c
+}
+
+// EventComponent maps a todo onto a zero-length VEVENT at its due date, or
+// an all-day VEVENT for all-day todos.
+// Completed todos are marked with a check mark in the summary since VEVENT
+// has no completion status.
+func EventComponent(todo *models.Todo) *ical.Component {
+	c := ical.NewComponent("VEVENT")
+	c.Add("UID", todo.ID.String()+"-event@to-do-app")
+	c.AddTime("DTSTAMP", todo.UpdatedAt)
+	c.AddTime("CREATED", todo.CreatedAt)
+	c.AddTime("LAST-MODIFIED", todo.UpdatedAt)
+	summary := todo.Title
+	if todo.Status {
+		summary = "✓ " + summary
+	}
+	c.AddText("SUMMARY", summary)
+	if todo.Description != "" {
+		c.AddText("DESCRIPTION", todo.Description)
+	}
+	switch {
+	case todo.DueDate != nil:
+		c.AddTime("DTSTART", *todo.DueDate)
+	case todo.DueOn != nil:
+		c.AddDate("DTSTART", todo.DueOn.In(time.UTC))
+	}
+	addRecurrence(c, todo)
+	c.Add("TRANSP", "TRANSPARENT")
+	return c
+}
+
+// addRecurrence adds the repeat rule of a todo as an RRULE. Rules that no
+// longer parse are left out rather than handed to clients.
+func addRecurrence(c *ical.Component, todo *models.Todo) {
+	if todo.Recurrence == "" {
+		return
+	}
+	rule, err := recurrence.Parse(todo.Recurrence)
+	if err != nil {
+		return
+	}
+	c.Add("RRULE", rule.String())
+}
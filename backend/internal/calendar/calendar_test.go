@@ -0,0 +1,72 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/ical"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+)
+
+func TestRecurrenceRoundTrip(t *testing.T) {
+	due := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	obj := &models.CalendarObject{
+		UID: "abc@client",
+		Todo: models.Todo{
+			ID:         uuid.New(),
+			Title:      "Water the plants",
+			DueDate:    &due,
+			Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ical.Encode(&buf, Object(obj)))
+	assert.Contains(t, buf.String(), "RRULE:FREQ=WEEKLY;BYDAY=MO,TH\r\n")
+
+	vtodo, err := ParseObject(&buf)
+	require.NoError(t, err)
+	todo := &models.Todo{Recurrence: "FREQ=DAILY"}
+	require.NoError(t, ApplyTodo(todo, vtodo))
+	assert.Equal(t, obj.Todo.Recurrence, todo.Recurrence)
+	assert.Equal(t, due, *todo.DueDate)
+
+	assert.Equal(t, obj.Todo.Recurrence, EventComponent(&obj.Todo).Prop("RRULE").Value)
+}
+
+func TestApplyTodoRecurrence(t *testing.T) {
+	parse := func(t *testing.T, rrule string) *ical.Component {
+		t.Helper()
+		lines := []string{"BEGIN:VCALENDAR", "BEGIN:VTODO", "UID:1", "SUMMARY:Pay rent"}
+		if rrule != "" {
+			lines = append(lines, "RRULE:"+rrule)
+		}
+		lines = append(lines, "END:VTODO", "END:VCALENDAR", "")
+		vtodo, err := ParseObject(strings.NewReader(strings.Join(lines, "\r\n")))
+		require.NoError(t, err)
+		return vtodo
+	}
+
+	todo := &models.Todo{Recurrence: "FREQ=DAILY"}
+	require.NoError(t, ApplyTodo(todo, parse(t, "freq=monthly;bymonthday=1")))
+	assert.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=1", todo.Recurrence)
+
+	require.NoError(t, ApplyTodo(todo, parse(t, "")))
+	assert.Empty(t, todo.Recurrence)
+
+	assert.ErrorIs(t, ApplyTodo(todo, parse(t, "FREQ=WEEKLY;COUNT=3")), recurrence.ErrInvalidRule)
+}
+
+func TestStoredRuleThatNoLongerParsesIsLeftOut(t *testing.T) {
+	todo := &models.Todo{ID: uuid.New(), Title: "Stretch", Recurrence: "FREQ=HOURLY"}
+
+	assert.Nil(t, TodoComponent(todo).Prop("RRULE"))
+	assert.Nil(t, EventComponent(todo).Prop("RRULE"))
+}
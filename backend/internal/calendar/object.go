@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/ical"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+)
+
+// ErrNoTodo is returned for calendar objects without a VTODO.
+var ErrNoTodo = errors.New("calendar object has no VTODO component")
+
+// Object renders a CalDAV calendar object: a VCALENDAR holding a single
+// VTODO with the UID the client knows the todo by.
+func Object(obj *models.CalendarObject) *ical.Component {
+	vtodo := TodoComponent(&obj.Todo)
+	vtodo.Prop("UID").Value = obj.UID
+
+	cal := NewCalendar("")
+	cal.AddComponent(vtodo)
+	return cal
+}
+
+// ParseObject reads a calendar object and returns its VTODO.
+func ParseObject(r io.Reader) (*ical.Component, error) {
+	cal, err := ical.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if cal.Name != "VCALENDAR" {
+		return nil, fmt.Errorf("expected VCALENDAR, got %s", cal.Name)
+	}
+	for _, c := range cal.Components {
+		if c.Name == "VTODO" {
+			return c, nil
+		}
+	}
+	return nil, ErrNoTodo
+}
+
+// ApplyTodo copies the fields of a VTODO onto todo. Properties the todo
+// has no equivalent for are ignored.
+func ApplyTodo(todo *models.Todo, vtodo *ical.Component) error {
+	todo.Title = ""
+	if p := vtodo.Prop("SUMMARY"); p != nil {
+		todo.Title = p.Text()
+	}
+	todo.Description = ""
+	if p := vtodo.Prop("DESCRIPTION"); p != nil {
+		todo.Description = p.Text()
+	}
+
+	todo.DueDate, todo.DueOn = nil, nil
+	if p := vtodo.Prop("DUE"); p != nil {
+		due, dateOnly, err := p.Time()
+		if err != nil {
+			return fmt.Errorf("parse DUE: %w", err)
+		}
+		if dateOnly {
+			day := models.DateOf(due)
+			todo.DueOn = &day
+		} else {
+			todo.DueDate = &due
+		}
+	}
+
+	todo.Recurrence = ""
+	if p := vtodo.Prop("RRULE"); p != nil {
+		rule, err := recurrence.Parse(p.Value)
+		if err != nil {
+			return fmt.Errorf("parse RRULE: %w", err)
+		}
+		todo.Recurrence = rule.String()
+	}
+
+	todo.Status = vtodo.Prop("COMPLETED") != nil
+	if p := vtodo.Prop("STATUS"); p != nil {
+		todo.Status = strings.EqualFold(p.Value, "COMPLETED")
+	}
+
+	return nil
+}
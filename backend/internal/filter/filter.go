@@ -0,0 +1,177 @@
+// Package filter parses the query language of saved views, such as
+//
+//	due < now+7d and tag:work and status:open
+//
+// into an AST that the repository compiles to SQL.
+//
+// A query combines terms with and, or and not, and parentheses; terms next
+// to each other are joined with and. The terms are:
+//
+//	tag:NAME                   todo has the tag
+//	status:open, status:done   todo is open or done
+//	list:NAME                  todo is in the list with this name or ID
+//	assignee:USER              todo is assigned to the user, "me" is the view's owner
+//	has:due, has:tags, has:assignee
+//	due|created|updated OP TIME with OP one of < <= > >= = !=
+//	WORD or "some text"        title or description contains the text
+//
+// A TIME is now or today, optionally followed by an offset such as +7d,
+// -2w or +12h, or a date like 2025-03-01. Times with a clock, such as
+// "2025-03-01T09:00:00Z", have to be quoted.
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Node is a node of a parsed query.
+type Node interface {
+	String() string
+}
+
+type And struct {
+	Left, Right Node
+}
+
+type Or struct {
+	Left, Right Node
+}
+
+type Not struct {
+	Expr Node
+}
+
+// Field is a todo attribute that a term refers to.
+type Field string
+
+const (
+	FieldTag      Field = "tag"
+	FieldStatus   Field = "status"
+	FieldList     Field = "list"
+	FieldAssignee Field = "assignee"
+	FieldHas      Field = "has"
+	FieldDue      Field = "due"
+	FieldCreated  Field = "created"
+	FieldUpdated  Field = "updated"
+)
+
+// Match is a field:value term. Values of status and has are validated by
+// the parser.
+type Match struct {
+	Field Field
+	Value string
+}
+
+// Text matches todos whose title or description contains Value.
+type Text struct {
+	Value string
+}
+
+type Op string
+
+const (
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+	OpEqual        Op = "="
+	OpNotEqual     Op = "!="
+)
+
+// Compare compares a time field of a todo with a time.
+type Compare struct {
+	Field Field
+	Op    Op
+	Value Time
+}
+
+type Base int
+
+const (
+	BaseNow Base = iota
+	BaseToday
+	BaseDate
+	BaseTime
+)
+
+type Unit byte
+
+const (
+	UnitHour Unit = 'h'
+	UnitDay  Unit = 'd'
+	UnitWeek Unit = 'w'
+)
+
+// Time is a point in time relative to when the query runs, or a fixed
+// date or time. Dates and today are resolved in the location of the time
+// passed to Resolve.
+type Time struct {
+	Base   Base
+	At     time.Time // for BaseDate and BaseTime
+	Offset int
+	Unit   Unit
+}
+
+// Resolve returns the time t stands for when the query runs at now.
+func (t Time) Resolve(now time.Time) time.Time {
+	var at time.Time
+	switch t.Base {
+	case BaseNow:
+		at = now
+	case BaseToday:
+		at = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	case BaseDate:
+		at = time.Date(t.At.Year(), t.At.Month(), t.At.Day(), 0, 0, 0, 0, now.Location())
+	case BaseTime:
+		at = t.At
+	}
+
+	switch t.Unit {
+	case UnitHour:
+		at = at.Add(time.Duration(t.Offset) * time.Hour)
+	case UnitDay:
+		at = at.AddDate(0, 0, t.Offset)
+	case UnitWeek:
+		at = at.AddDate(0, 0, 7*t.Offset)
+	}
+	return at
+}
+
+func (n And) String() string { return "(" + n.Left.String() + " and " + n.Right.String() + ")" }
+func (n Or) String() string  { return "(" + n.Left.String() + " or " + n.Right.String() + ")" }
+func (n Not) String() string { return "not " + n.Expr.String() }
+
+func (n Match) String() string   { return string(n.Field) + ":" + quote(n.Value) }
+func (n Text) String() string    { return quote(n.Value) }
+func (n Compare) String() string { return fmt.Sprintf("%s %s %s", n.Field, n.Op, n.Value) }
+
+func (t Time) String() string {
+	var s string
+	switch t.Base {
+	case BaseNow:
+		s = "now"
+	case BaseToday:
+		s = "today"
+	case BaseDate:
+		s = t.At.Format(time.DateOnly)
+	case BaseTime:
+		s = `"` + t.At.Format(time.RFC3339) + `"`
+	}
+	if t.Unit != 0 {
+		s += fmt.Sprintf("%+d%c", t.Offset, t.Unit)
+	}
+	return s
+}
+
+// quote quotes s unless it reads back as a single word.
+func quote(s string) string {
+	if s == "" || isKeyword(s) || strings.ContainsFunc(s, func(r rune) bool { return isSpecial(r) || unicode.IsSpace(r) }) {
+		return `"` + quoteReplacer.Replace(s) + `"`
+	}
+	return s
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
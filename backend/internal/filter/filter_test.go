@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"due < now+7d and tag:work and status:open", "((due < now+7d and tag:work) and status:open)"},
+		{"tag:work tag:urgent", "(tag:work and tag:urgent)"},
+		{"tag:a or tag:b and tag:c", "(tag:a or (tag:b and tag:c))"},
+		{"(tag:a or tag:b) and not status:done", "((tag:a or tag:b) and not status:done)"},
+		{"NOT not has:due", "not not has:due"},
+		{"Status:DONE", "status:done"},
+		{"due>=today-1w", "due >= today-1w"},
+		{"created != 2025-03-01", "created != 2025-03-01"},
+		{`updated > "2025-03-01T09:00:00Z"`, `updated > "2025-03-01T09:00:00Z"`},
+		{`list:"Home stuff" assignee:me`, `(list:"Home stuff" and assignee:me)`},
+		{`milk "say \"hi\""`, `(milk and "say \"hi\"")`},
+		{`"and"`, `"and"`},
+		{"due <= now+12h", "due <= now+12h"},
+		{"groceries", "groceries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			n, err := Parse(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, n.String())
+
+			// the canonical form parses to the same tree
+			again, err := Parse(n.String())
+			require.NoError(t, err)
+			assert.Equal(t, n, again)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"", 0, "empty query"},
+		{"tag:", 4, `expected a value for "tag" instead of end of query`},
+		{"color:red", 0, `unknown field "color"`},
+		{"status:closed", 7, `status must be "open" or "done"`},
+		{"tag < now", 0, `"tag" cannot be compared, use due, created or updated`},
+		{"due < tomorrow", 6, `invalid time "tomorrow", use now, today or YYYY-MM-DD`},
+		{"due < now+7y", 6, `invalid unit in "now+7y", use h, d or w`},
+		{"due < now7d", 6, `invalid offset in "now7d"`},
+		{"(tag:a", 6, `expected ")" instead of end of query`},
+		{"tag:a)", 5, `unexpected ")"`},
+		{"tag:a and", 9, "unexpected end of query"},
+		{`"open`, 0, "unterminated string"},
+		{"due ! now", 4, `"!" must be followed by "="`},
+		{`""`, 0, "empty text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var fe *Error
+			require.ErrorAs(t, err, &fe)
+			assert.Equal(t, tt.pos, fe.Pos)
+			assert.Equal(t, tt.msg, fe.Msg)
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	deep := ""
+	for range maxDepth + 1 {
+		deep += "not "
+	}
+	_, err := Parse(deep + "has:due")
+	assert.ErrorContains(t, err, "nested too deeply")
+
+	long := make([]byte, MaxLength+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	_, err = Parse(string(long))
+	assert.ErrorContains(t, err, "longer than")
+}
+
+func TestTimeResolve(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// the day before the switch to summer time
+	now := time.Date(2025, 3, 29, 15, 30, 0, 0, berlin)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"now", now},
+		{"now-12h", now.Add(-12 * time.Hour)},
+		{"today", time.Date(2025, 3, 29, 0, 0, 0, 0, berlin)},
+		{"today+1d", time.Date(2025, 3, 30, 0, 0, 0, 0, berlin)},
+		{"today+1w", time.Date(2025, 4, 5, 0, 0, 0, 0, berlin)},
+		{"2025-01-31", time.Date(2025, 1, 31, 0, 0, 0, 0, berlin)},
+		{`"2025-01-31T10:00:00Z"`, time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			n, err := Parse("due < " + tt.value)
+			require.NoError(t, err)
+			got := n.(Compare).Value.Resolve(now)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxLength is the longest query accepted, in bytes.
+const MaxLength = 1000
+
+// maxDepth limits the nesting of parentheses and not.
+const maxDepth = 32
+
+// Error is a syntax error at a byte offset of the query.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("filter: %s at position %d", e.Msg, e.Pos)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokColon
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+func isSpecial(r rune) bool {
+	return strings.ContainsRune(`():<>=!"`, r)
+}
+
+func isKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "and", "or", "not":
+		return true
+	}
+	return false
+}
+
+func lex(query string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		r := rune(query[i])
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{tokColon, ":", i})
+			i++
+		case r == '<' || r == '>' || r == '=' || r == '!':
+			op := query[i : i+1]
+			if i+1 < len(query) && query[i+1] == '=' && r != '=' {
+				op = query[i : i+2]
+			}
+			if op == "!" {
+				return nil, &Error{i, `"!" must be followed by "="`}
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(query) && query[j] != '"'; j++ {
+				if query[j] == '\\' && j+1 < len(query) {
+					j++
+				}
+				b.WriteByte(query[j])
+			}
+			if j == len(query) {
+				return nil, &Error{i, "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, b.String(), i})
+			i = j + 1
+		default:
+			j := i
+			for j < len(query) {
+				r := rune(query[j])
+				if r < 0x80 && (isSpecial(r) || unicode.IsSpace(r)) {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, token{tokWord, query[i:j], i})
+			i = j
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(query)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// Parse parses a query. The returned error is an *Error.
+func Parse(query string) (Node, error) {
+	if len(query) > MaxLength {
+		return nil, &Error{MaxLength, fmt.Sprintf("query is longer than %d bytes", MaxLength)}
+	}
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &Error{0, "empty query"}
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) keyword(t token, kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+func (p *parser) unexpected(t token) error {
+	return &Error{t.pos, "unexpected " + t.describe()}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if p.keyword(t, "and") {
+			p.next()
+		} else if t.kind == tokEOF || t.kind == tokRParen || p.keyword(t, "or") {
+			return left, nil
+		}
+		// terms next to each other are joined with and
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And{left, right}
+	}
+}
+
+func (p *parser) parseNot() (Node, error) {
+	t := p.peek()
+	if !p.keyword(t, "not") && t.kind != tokLParen {
+		return p.parseTerm()
+	}
+
+	if p.depth++; p.depth > maxDepth {
+		return nil, &Error{t.pos, "query is nested too deeply"}
+	}
+	defer func() { p.depth-- }()
+
+	p.next()
+	if t.kind == tokLParen {
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, &Error{r.pos, `expected ")" instead of ` + r.describe()}
+		}
+		return n, nil
+	}
+
+	n, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return Not{n}, nil
+}
+
+func (p *parser) parseTerm() (Node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokString && strings.TrimSpace(t.text) == "":
+		return nil, &Error{t.pos, "empty text"}
+	case t.kind == tokString:
+		return Text{t.text}, nil
+	case t.kind != tokWord || isKeyword(t.text):
+		return nil, p.unexpected(t)
+	}
+
+	switch p.peek().kind {
+	case tokColon:
+		p.next()
+		return p.parseMatch(t)
+	case tokOp:
+		return p.parseCompare(t)
+	}
+	return Text{t.text}, nil
+}
+
+func (p *parser) parseMatch(field token) (Node, error) {
+	v := p.next()
+	if v.kind != tokWord && v.kind != tokString {
+		return nil, &Error{v.pos, fmt.Sprintf("expected a value for %q instead of %s", field.text, v.describe())}
+	}
+	m := Match{Field: Field(strings.ToLower(field.text)), Value: v.text}
+
+	switch m.Field {
+	case FieldTag, FieldList, FieldAssignee:
+		if m.Value == "" {
+			return nil, &Error{v.pos, fmt.Sprintf("empty value for %q", field.text)}
+		}
+	case FieldStatus:
+		m.Value = strings.ToLower(m.Value)
+		if m.Value != "open" && m.Value != "done" {
+			return nil, &Error{v.pos, `status must be "open" or "done"`}
+		}
+	case FieldHas:
+		m.Value = strings.ToLower(m.Value)
+		if m.Value != "due" && m.Value != "tags" && m.Value != "assignee" {
+			return nil, &Error{v.pos, `has must be "due", "tags" or "assignee"`}
+		}
+	default:
+		return nil, &Error{field.pos, fmt.Sprintf("unknown field %q", field.text)}
+	}
+	return m, nil
+}
+
+func (p *parser) parseCompare(field token) (Node, error) {
+	c := Compare{Field: Field(strings.ToLower(field.text))}
+	switch c.Field {
+	case FieldDue, FieldCreated, FieldUpdated:
+	default:
+		return nil, &Error{field.pos, fmt.Sprintf("%q cannot be compared, use due, created or updated", field.text)}
+	}
+
+	c.Op = Op(p.next().text)
+	v := p.next()
+	value, err := parseTime(v)
+	if err != nil {
+		return nil, err
+	}
+	c.Value = value
+	return c, nil
+}
+
+func parseTime(t token) (Time, error) {
+	if t.kind == tokString {
+		at, err := time.Parse(time.RFC3339, t.text)
+		if err != nil {
+			return Time{}, &Error{t.pos, fmt.Sprintf("invalid time %s, use RFC 3339", t.describe())}
+		}
+		return Time{Base: BaseTime, At: at}, nil
+	}
+	if t.kind != tokWord {
+		return Time{}, &Error{t.pos, "expected a time instead of " + t.describe()}
+	}
+
+	s := strings.ToLower(t.text)
+	var v Time
+	switch {
+	case strings.HasPrefix(s, "now"):
+		v.Base, s = BaseNow, s[len("now"):]
+	case strings.HasPrefix(s, "today"):
+		v.Base, s = BaseToday, s[len("today"):]
+	default:
+		at, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return Time{}, &Error{t.pos, fmt.Sprintf("invalid time %q, use now, today or YYYY-MM-DD", t.text)}
+		}
+		return Time{Base: BaseDate, At: at}, nil
+	}
+	if s == "" {
+		return v, nil
+	}
+
+	// an offset such as +7d
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return Time{}, &Error{t.pos, fmt.Sprintf("invalid offset in %q", t.text)}
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n > 100000 || n < -100000 {
+		return Time{}, &Error{t.pos, fmt.Sprintf("invalid offset in %q", t.text)}
+	}
+	switch u := Unit(s[len(s)-1]); u {
+	case UnitHour, UnitDay, UnitWeek:
+		v.Offset, v.Unit = n, u
+	default:
+		return Time{}, &Error{t.pos, fmt.Sprintf("invalid unit in %q, use h, d or w", t.text)}
+	}
+	return v, nil
+}
@@ -0,0 +1,184 @@
+// Package health tells an orchestrator whether the server is alive and
+// whether it is ready for traffic: the database answers, its schema is
+// migrated to the version the server was built for, every background
+// worker is running and the server is not shutting down.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout bounds the queries run for a readiness check.
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusOK           = "ok"
+	StatusFailing      = "failing"
+	StatusReady        = "ready"
+	StatusNotReady     = "not ready"
+	StatusShuttingDown = "shutting down"
+
+	WorkerRunning = "running"
+	WorkerStopped = "stopped"
+)
+
+// Pinger checks the database connection.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// MigrationVersion returns the version the schema is migrated to and
+// whether the migration to it was left dirty.
+type MigrationVersion func(ctx context.Context) (version uint, dirty bool, err error)
+
+// Check is the outcome of one readiness check.
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MigrationCheck compares the schema version with the latest migration.
+type MigrationCheck struct {
+	Check
+	Version uint `json:"version"`
+	Latest  uint `json:"latest"`
+	Dirty   bool `json:"dirty"`
+}
+
+// WorkerCheck has the state, running or stopped, of every worker.
+type WorkerCheck struct {
+	Check
+	Workers map[string]string `json:"workers"`
+}
+
+// Report is the readiness of the server and the checks it is made of.
+type Report struct {
+	Status     string         `json:"status"`
+	Database   Check          `json:"database"`
+	Migrations MigrationCheck `json:"migrations"`
+	Workers    WorkerCheck    `json:"workers"`
+}
+
+type Checker struct {
+	db           Pinger
+	version      MigrationVersion
+	latest       uint
+	shuttingDown atomic.Bool
+
+	mu      sync.Mutex
+	workers map[string]bool
+}
+
+// NewChecker checks db and that version reports latest, the last migration
+// the server ships with.
+func NewChecker(db Pinger, version MigrationVersion, latest uint) *Checker {
+	return &Checker{db: db, version: version, latest: latest, workers: map[string]bool{}}
+}
+
+// Go runs a background worker named name on a goroutine of its own. The
+// server is not ready once run has returned. The returned channel is closed
+// then.
+func (c *Checker) Go(ctx context.Context, name string, run func(ctx context.Context)) <-chan struct{} {
+	c.setWorker(name, true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer c.setWorker(name, false)
+		run(ctx)
+	}()
+	return done
+}
+
+func (c *Checker) setWorker(name string, running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers[name] = running
+}
+
+// BeginShutdown makes the server not ready from now on, so that no new
+// traffic is sent to it while it drains.
+func (c *Checker) BeginShutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check runs the readiness checks.
+func (c *Checker) Check(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := &Report{
+		Database:   check(c.db.PingContext(ctx)),
+		Migrations: c.checkMigrations(ctx),
+		Workers:    c.checkWorkers(),
+	}
+	switch {
+	case c.shuttingDown.Load():
+		report.Status = StatusShuttingDown
+	case report.Database.Status == StatusOK &&
+		report.Migrations.Status == StatusOK &&
+		report.Workers.Status == StatusOK:
+		report.Status = StatusReady
+	default:
+		report.Status = StatusNotReady
+	}
+	return report
+}
+
+func (c *Checker) checkMigrations(ctx context.Context) MigrationCheck {
+	version, dirty, err := c.version(ctx)
+	mc := MigrationCheck{Check: check(err), Version: version, Latest: c.latest, Dirty: dirty}
+	switch {
+	case err != nil:
+	case dirty:
+		mc.Check = Check{Status: StatusFailing, Error: "the last migration failed and left the schema dirty"}
+	case version < c.latest:
+		mc.Check = Check{Status: StatusFailing, Error: "the schema is behind the latest migration"}
+	}
+	return mc
+}
+
+func (c *Checker) checkWorkers() WorkerCheck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wc := WorkerCheck{Check: Check{Status: StatusOK}, Workers: make(map[string]string, len(c.workers))}
+	for name, running := range c.workers {
+		if !running {
+			wc.Workers[name] = WorkerStopped
+			wc.Check = Check{Status: StatusFailing, Error: "a worker has stopped"}
+			continue
+		}
+		wc.Workers[name] = WorkerRunning
+	}
+	return wc
+}
+
+func check(err error) Check {
+	if err != nil {
+		return Check{Status: StatusFailing, Error: err.Error()}
+	}
+	return Check{Status: StatusOK}
+}
+
+// Live answers as long as the process serves requests at all.
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Check{Status: StatusOK})
+}
+
+// Ready writes the readiness report, with status 503 unless the server is
+// ready.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Check(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != StatusReady {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDB struct {
+	err error
+}
+
+func (db fakeDB) PingContext(context.Context) error {
+	return db.err
+}
+
+func migratedTo(version uint, dirty bool) MigrationVersion {
+	return func(context.Context) (uint, bool, error) {
+		return version, dirty, nil
+	}
+}
+
+func ready(t *testing.T, c *Checker) (int, *Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, &report
+}
+
+func TestReady(t *testing.T) {
+	c := NewChecker(fakeDB{}, migratedTo(18, false), 18)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Go(ctx, "imports", func(ctx context.Context) { <-ctx.Done() })
+
+	code, report := ready(t, c)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusReady, report.Status)
+	assert.Equal(t, StatusOK, report.Database.Status)
+	assert.Equal(t, StatusOK, report.Migrations.Status)
+	assert.Equal(t, uint(18), report.Migrations.Version)
+	assert.Equal(t, map[string]string{"imports": WorkerRunning}, report.Workers.Workers)
+}
+
+func TestNotReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker *Checker
+		failing func(*Report) Check
+	}{
+		{
+			name:    "database down",
+			checker: NewChecker(fakeDB{err: errors.New("connection refused")}, migratedTo(18, false), 18),
+			failing: func(r *Report) Check { return r.Database },
+		},
+		{
+			name:    "dirty schema",
+			checker: NewChecker(fakeDB{}, migratedTo(18, true), 18),
+			failing: func(r *Report) Check { return r.Migrations.Check },
+		},
+		{
+			name:    "schema behind",
+			checker: NewChecker(fakeDB{}, migratedTo(17, false), 18),
+			failing: func(r *Report) Check { return r.Migrations.Check },
+		},
+		{
+			name: "migration version unknown",
+			checker: NewChecker(fakeDB{}, func(context.Context) (uint, bool, error) {
+				return 0, false, errors.New("no such table")
+			}, 18),
+			failing: func(r *Report) Check { return r.Migrations.Check },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, report := ready(t, tt.checker)
+
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.Equal(t, StatusNotReady, report.Status)
+			assert.Equal(t, StatusFailing, tt.failing(report).Status)
+			assert.NotEmpty(t, tt.failing(report).Error)
+		})
+	}
+}
+
+func TestNotReadyWhenWorkerStops(t *testing.T) {
+	c := NewChecker(fakeDB{}, migratedTo(18, false), 18)
+	<-c.Go(context.Background(), "archiver", func(context.Context) {})
+
+	code, report := ready(t, c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFailing, report.Workers.Status)
+	assert.Equal(t, map[string]string{"archiver": WorkerStopped}, report.Workers.Workers)
+}
+
+func TestNotReadyWhenShuttingDown(t *testing.T) {
+	c := NewChecker(fakeDB{}, migratedTo(18, false), 18)
+	c.BeginShutdown()
+
+	code, report := ready(t, c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusShuttingDown, report.Status)
+
+	rec := httptest.NewRecorder()
+	c.Live(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
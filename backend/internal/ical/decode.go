@@ -0,0 +1,138 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Decode parses an iCalendar stream and returns its top-level component.
+func Decode(r io.Reader) (*Component, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// unfold continuation lines; bare LF line endings are accepted as well
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\n ", "")
+	text = strings.ReplaceAll(text, "\n\t", "")
+
+	var root *Component
+	var stack []*Component
+	for n, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		switch strings.ToUpper(prop.Name) {
+		case "BEGIN":
+			c := NewComponent(strings.ToUpper(prop.Value))
+			if len(stack) > 0 {
+				stack[len(stack)-1].AddComponent(c)
+			} else if root != nil {
+				return nil, fmt.Errorf("line %d: more than one top-level component", n+1)
+			} else {
+				root = c
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: property outside of a component", n+1)
+			}
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("no component found")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("component %s is not terminated", stack[len(stack)-1].Name)
+	}
+
+	return root, nil
+}
+
+// parseLine splits a content line into name, parameters and value.
+func parseLine(line string) (*Property, error) {
+	prop := &Property{}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return nil, errors.New("missing property name")
+	}
+	prop.Name = strings.ToUpper(line[:end])
+	line = line[end:]
+
+	for strings.HasPrefix(line, ";") {
+		line = line[1:]
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("malformed parameter of %s", prop.Name)
+		}
+		param := Param{Name: strings.ToUpper(line[:eq])}
+		line = line[eq+1:]
+
+		if strings.HasPrefix(line, `"`) {
+			closing := strings.IndexByte(line[1:], '"')
+			if closing < 0 {
+				return nil, fmt.Errorf("unterminated quoted parameter of %s", prop.Name)
+			}
+			param.Value = line[1 : closing+1]
+			line = line[closing+2:]
+		} else {
+			end := strings.IndexAny(line, ";:")
+			if end < 0 {
+				return nil, fmt.Errorf("missing value of %s", prop.Name)
+			}
+			param.Value = line[:end]
+			line = line[end:]
+		}
+		prop.Params = append(prop.Params, param)
+	}
+
+	if !strings.HasPrefix(line, ":") {
+		return nil, fmt.Errorf("missing value of %s", prop.Name)
+	}
+	prop.Value = line[1:]
+
+	return prop, nil
+}
+
+// Time parses a DATE or DATE-TIME property. Date-times with a TZID are read
+// in that zone; floating values and unknown zones are read as UTC. dateOnly
+// reports a DATE value.
+func (p *Property) Time() (t time.Time, dateOnly bool, err error) {
+	loc := time.UTC
+	if tzid := p.Param("TZID"); tzid != "" && tzid != "Local" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	value := strings.TrimSpace(p.Value)
+	if strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == len(dateFormat) {
+		t, err = time.ParseInLocation(dateFormat, value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(dateTimeFormat, value)
+		return t, false, err
+	}
+	t, err = time.ParseInLocation(strings.TrimSuffix(dateTimeFormat, "Z"), value, loc)
+	return t, false, err
+}
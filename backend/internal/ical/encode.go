@@ -0,0 +1,59 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line allowed before folding.
+const maxLineOctets = 75
+
+// Encode writes c and all its sub-components to w.
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	encodeComponent(bw, c)
+	return bw.Flush()
+}
+
+func encodeComponent(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Props {
+		var line strings.Builder
+		line.WriteString(p.Name)
+		for _, param := range p.Params {
+			line.WriteString(";" + param.Name + "=")
+			if strings.ContainsAny(param.Value, ":;,") {
+				line.WriteString(`"` + param.Value + `"`)
+			} else {
+				line.WriteString(param.Value)
+			}
+		}
+		line.WriteString(":" + p.Value)
+		writeLine(w, line.String())
+	}
+	for _, sub := range c.Components {
+		encodeComponent(w, sub)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// writeLine folds lines longer than 75 octets without splitting UTF-8
+// sequences.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts towards its length
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
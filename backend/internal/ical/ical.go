@@ -0,0 +1,117 @@
+// Package ical implements the subset of the iCalendar format (RFC 5545)
+// needed to publish and exchange todos: a generic component tree with
+// property parameters, text escaping and line folding.
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	dateFormat     = "20060102"
+)
+
+type Param struct {
+	Name  string
+	Value string
+}
+
+// Property is a content line. Value holds the encoded value, i.e. text
+// values are already escaped.
+type Property struct {
+	Name   string
+	Params []Param
+	Value  string
+}
+
+// Param returns the value of the named parameter.
+func (p *Property) Param(name string) string {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// Text returns the unescaped text value.
+func (p *Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Component is a calendar component such as VCALENDAR, VTODO or VEVENT.
+type Component struct {
+	Name       string
+	Props      []*Property
+	Components []*Component
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add appends a property with an already encoded value.
+func (c *Component) Add(name, value string, params ...Param) {
+	c.Props = append(c.Props, &Property{Name: name, Params: params, Value: value})
+}
+
+// AddText appends a property with an escaped text value.
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddTime appends a UTC date-time property.
+func (c *Component) AddTime(name string, t time.Time) {
+	c.Add(name, FormatTime(t))
+}
+
+// AddDate appends a date-only property.
+func (c *Component) AddDate(name string, t time.Time) {
+	c.Add(name, t.Format(dateFormat), Param{Name: "VALUE", Value: "DATE"})
+}
+
+// AddComponent appends a sub-component.
+func (c *Component) AddComponent(sub *Component) {
+	c.Components = append(c.Components, sub)
+}
+
+// Prop returns the first property with the given name.
+func (c *Component) Prop(name string) *Property {
+	for _, p := range c.Props {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// FormatTime formats t as a UTC date-time value.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func UnescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
@@ -0,0 +1,109 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	cal := NewComponent("VCALENDAR")
+	cal.Add("VERSION", "2.0")
+
+	todo := NewComponent("VTODO")
+	todo.AddText("SUMMARY", "Buy milk, eggs; bread\nand butter")
+	todo.AddTime("DUE", time.Date(2025, 3, 14, 17, 0, 0, 0, time.FixedZone("CET", 3600)))
+	todo.AddDate("DTSTART", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	todo.Add("X-TEST", "v", Param{Name: "X-LABEL", Value: "a:b"})
+	cal.AddComponent(todo)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, cal))
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTODO",
+		`SUMMARY:Buy milk\, eggs\; bread\nand butter`,
+		"DUE:20250314T160000Z",
+		"DTSTART;VALUE=DATE:20250310",
+		`X-TEST;X-LABEL="a:b":v`,
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), buf.String())
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	c := NewComponent("VTODO")
+	c.AddText("DESCRIPTION", strings.Repeat("ä", 60))
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, c))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Len(t, lines, 4)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+	}
+	assert.True(t, strings.HasPrefix(lines[2], " "))
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("ä", 60),
+		strings.ReplaceAll(lines[1]+"\r\n"+lines[2], "\r\n ", ""))
+}
+
+func TestDecode(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTODO",
+		`SUMMARY:Buy milk\, eggs\; bread\nand butter`,
+		"DESCRIPTION:a long line that was",
+		"  folded",
+		`DUE;TZID="Europe/Berlin":20250314T170000`,
+		"DTSTART;VALUE=DATE:20250310",
+		"COMPLETED:20250313T080000Z",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	cal, err := Decode(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, "VCALENDAR", cal.Name)
+	require.Len(t, cal.Components, 1)
+
+	todo := cal.Components[0]
+	assert.Equal(t, "Buy milk, eggs; bread\nand butter", todo.Prop("SUMMARY").Text())
+	assert.Equal(t, "a long line that was folded", todo.Prop("DESCRIPTION").Text())
+
+	due, dateOnly, err := todo.Prop("DUE").Time()
+	require.NoError(t, err)
+	assert.False(t, dateOnly)
+	assert.Equal(t, time.Date(2025, 3, 14, 16, 0, 0, 0, time.UTC), due.UTC())
+
+	start, dateOnly, err := todo.Prop("DTSTART").Time()
+	require.NoError(t, err)
+	assert.True(t, dateOnly)
+	assert.Equal(t, "2025-03-10", start.Format(time.DateOnly))
+
+	completed, _, err := todo.Prop("completed").Time()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 13, 8, 0, 0, 0, time.UTC), completed)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"SUMMARY:outside",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nX-BROKEN;PARAM\r\nEND:VCALENDAR\r\n",
+	} {
+		_, err := Decode(strings.NewReader(input))
+		assert.Error(t, err, "input %q", input)
+	}
+}
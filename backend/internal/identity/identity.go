@@ -0,0 +1,24 @@
+// Package identity carries the calling user through a request context.
+//
+// The API does not authenticate users itself; it trusts the user ID that an
+// upstream gateway forwards in the X-User-ID header.
+package identity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ctxKey struct{}
+
+// WithUserID returns a copy of ctx that carries the given user ID.
+func WithUserID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// UserID returns the user ID stored in ctx, if any.
+func UserID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ctxKey{}).(uuid.UUID)
+	return id, ok
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// Archiver applies the auto-archive policies of lists, archiving todos
+// that have been completed for longer than their list keeps them.
+type Archiver struct {
+	svc      service.TodoService
+	interval time.Duration
+}
+
+func NewArchiver(svc service.TodoService, interval time.Duration) *Archiver {
+	return &Archiver{svc: svc, interval: interval}
+}
+
+// Run archives completed todos every interval until ctx is cancelled,
+// finishing a run that has started.
+func (a *Archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := a.svc.ArchiveCompletedTodos(context.WithoutCancel(ctx))
+			if err != nil {
+				slog.ErrorContext(ctx, "jobs: archive completed todos", "error", err)
+				continue
+			}
+			if n > 0 {
+				slog.InfoContext(ctx, "jobs: archived completed todos", "count", n)
+			}
+		}
+	}
+}
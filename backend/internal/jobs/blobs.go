@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// BlobCleaner removes the blobs of deleted attachments from the blob
+// store, so deleting a todo or list never waits for the store.
+type BlobCleaner struct {
+	svc      service.TodoService
+	interval time.Duration
+}
+
+func NewBlobCleaner(svc service.TodoService, interval time.Duration) *BlobCleaner {
+	return &BlobCleaner{svc: svc, interval: interval}
+}
+
+// Run removes queued blobs every interval until ctx is cancelled. Blobs
+// that fail to delete stay queued for the next run. A batch that has
+// started is finished after ctx is cancelled.
+func (c *BlobCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				n, err := c.svc.CleanupBlobs(context.WithoutCancel(ctx))
+				if err != nil {
+					slog.ErrorContext(ctx, "jobs: clean up blobs", "error", err)
+				}
+				if n == 0 {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package jobs runs background work that is queued in the database.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/service"
+)
+
+// ImportWorker runs pending import jobs. Several workers, also across
+// instances, can run side by side as every job is claimed by exactly one,
+// until that worker stops renewing its claim.
+type ImportWorker struct {
+	svc      service.TodoService
+	interval time.Duration
+}
+
+func NewImportWorker(svc service.TodoService, interval time.Duration) *ImportWorker {
+	return &ImportWorker{svc: svc, interval: interval}
+}
+
+// Run polls for pending jobs every interval until ctx is cancelled. Jobs
+// are run back to back while there are any. A job that has been claimed is
+// run to the end after ctx is cancelled, but no new one is claimed.
+func (w *ImportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				ran, err := w.svc.RunNextImportJob(context.WithoutCancel(ctx))
+				if err != nil {
+					slog.ErrorContext(ctx, "jobs: run import job", "error", err)
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package logging sets up structured logging with log/slog: the handler
+// writing JSON lines, the redaction of sensitive attributes and a logger
+// carried in the context of every request.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Redacted replaces the values of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are parts of the names of attributes whose values must not
+// be logged.
+var sensitiveKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey", "credential",
+}
+
+// New returns a logger writing to w at level ("debug", "info", "warn" or
+// "error", info by default) in format ("json", the default, or "text").
+// Sensitive attributes are redacted, and lines logged with a request's
+// context carry the chi route pattern it matched.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("unknown log level %q", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redact}
+	var h slog.Handler
+	switch format {
+	case "", "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return slog.New(routeHandler{h}), nil
+}
+
+type ctxKey struct{}
+
+// WithLogger returns a copy of ctx that carries l.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// Sensitive tells whether the values of attributes or parameters called
+// key must be redacted.
+func Sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindGroup && Sensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+// routeHandler adds the route pattern of the request whose context a line
+// is logged with. The pattern is only complete once the request has been
+// routed, so it is read when the line is written.
+type routeHandler struct {
+	slog.Handler
+}
+
+func (h routeHandler) Handle(ctx context.Context, r slog.Record) error {
+	if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+		r.AddAttrs(slog.String("route", rctx.RoutePattern()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h routeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return routeHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h routeHandler) WithGroup(name string) slog.Handler {
+	return routeHandler{h.Handler.WithGroup(name)}
+}
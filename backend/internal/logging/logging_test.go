@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+)
+
+// lines decodes the JSON lines written to buf.
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "")
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("login", "user", "ada", "password", "hunter2", "smtp", slog.GroupValue(
+		slog.String("host", "mail"), slog.String("SMTP_PASSWORD", "secret"),
+	), "feed_token", "abc")
+
+	logged := lines(t, &buf)
+	require.Len(t, logged, 1)
+	assert.Equal(t, "login", logged[0]["msg"])
+	assert.Equal(t, "ada", logged[0]["user"])
+	assert.Equal(t, Redacted, logged[0]["password"])
+	assert.Equal(t, Redacted, logged[0]["feed_token"])
+	assert.Equal(t, map[string]any{"host": "mail", "SMTP_PASSWORD": Redacted}, logged[0]["smtp"])
+
+	_, err = New(&buf, "loud", "json")
+	assert.Error(t, err)
+	_, err = New(&buf, "debug", "xml")
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logger)
+
+	userID := uuid.New()
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(identity.WithUserID(r.Context(), userID)))
+		})
+	})
+	r.Use(Middleware)
+	r.Get("/calendar/{token}.ics", func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).InfoContext(r.Context(), "serving feed")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Get("/boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar/s3cr3t.ics", nil))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/boom", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	logged := lines(t, &buf)
+	require.Len(t, logged, 4)
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	for _, line := range logged {
+		assert.NotEmpty(t, line["request_id"])
+		assert.Equal(t, userID.String(), line["user_id"])
+	}
+
+	assert.Equal(t, "serving feed", logged[0]["msg"])
+	assert.Equal(t, "/calendar/{token}.ics", logged[0]["route"])
+
+	assert.Equal(t, "request", logged[1]["msg"])
+	assert.Equal(t, "INFO", logged[1]["level"])
+	assert.Equal(t, "/calendar/"+Redacted+".ics", logged[1]["path"])
+	assert.Equal(t, float64(http.StatusNotFound), logged[1]["status"])
+
+	assert.Equal(t, "panic serving request", logged[2]["msg"])
+	assert.Equal(t, "boom", logged[2]["panic"])
+	assert.Contains(t, logged[2]["stack"], "runtime/debug.Stack")
+
+	assert.Equal(t, "request", logged[3]["msg"])
+	assert.Equal(t, "ERROR", logged[3]["level"])
+	assert.Equal(t, "/boom", logged[3]["route"])
+	assert.Equal(t, float64(http.StatusInternalServerError), logged[3]["status"])
+}
+
+func TestFromContextDefault(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(t.Context()))
+}
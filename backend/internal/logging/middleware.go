@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+)
+
+// Middleware carries a logger with the request ID and the calling user in
+// the context of every request and logs the request once it is served,
+// with sensitive URL parameters such as feed tokens redacted from its path.
+// A panic is logged with its stack and answered with 500 Internal Server
+// Error. It has to run inside a chi router, after the middlewares setting
+// the request ID and the user.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		logger := slog.Default().With("request_id", middleware.GetReqID(r.Context()))
+		if userID, ok := identity.UserID(r.Context()); ok {
+			logger = logger.With("user_id", userID.String())
+		}
+		ctx := WithLogger(r.Context(), logger)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			if p := recover(); p != nil {
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				logger.ErrorContext(ctx, "panic serving request", "panic", p, "stack", string(debug.Stack()))
+				if ww.Status() == 0 {
+					http.Error(ww, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(ctx, level, "request",
+				slog.String("method", r.Method),
+				slog.String("path", redactPath(r)),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+			)
+		}()
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+	})
+}
+
+// redactPath returns the request's path with the values of sensitive URL
+// parameters replaced.
+func redactPath(r *http.Request) string {
+	path := r.URL.Path
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return path
+	}
+	for i, key := range rctx.URLParams.Keys {
+		if value := rctx.URLParams.Values[i]; value != "" && Sensitive(key) {
+			path = strings.Replace(path, value, Redacted, 1)
+		}
+	}
+	return path
+}
@@ -0,0 +1,143 @@
+// Package metrics exposes the server's metrics in the Prometheus text
+// format: HTTP requests per route, the database connection pool,
+// transactions and the number of open and overdue todos.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// scrapeTimeout bounds the queries run for a scrape.
+const scrapeTimeout = 5 * time.Second
+
+// TodoCounter counts the todos reported by the business gauges.
+type TodoCounter interface {
+	CountTodos(ctx context.Context) (*models.TodoCounts, error)
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	txs      *prometheus.CounterVec
+}
+
+// New registers the metrics of the server, reading the pool stats of db
+// and counting todos with todos on every scrape.
+func New(db *sql.DB, todos TodoCounter) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests by method and route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		txs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_transactions_total",
+			Help: "Database transactions by outcome, commit or rollback.",
+		}, []string{"outcome"}),
+	}
+	// both outcomes are reported from the start
+	m.txs.WithLabelValues(string(repository.TxCommitted))
+	m.txs.WithLabelValues(string(repository.TxRolledBack))
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.txs,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "todo"),
+		newTodoCollector(todos),
+	)
+	return m
+}
+
+// Handler serves the metrics. A failing todo count leaves out the todo
+// gauges rather than failing the scrape.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorLog:      slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// Middleware counts and times requests by the chi route pattern they
+// matched rather than their path, so that IDs don't make new series.
+// Requests that match no route are labelled "unmatched". It has to run
+// inside a chi router.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		m.duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// TxDone counts a finished transaction. It makes Metrics a
+// repository.TxObserver.
+func (m *Metrics) TxDone(outcome repository.TxOutcome) {
+	m.txs.WithLabelValues(string(outcome)).Inc()
+}
+
+// todoCollector counts the open and overdue todos on every scrape.
+type todoCollector struct {
+	todos   TodoCounter
+	open    *prometheus.Desc
+	overdue *prometheus.Desc
+}
+
+func newTodoCollector(todos TodoCounter) *todoCollector {
+	return &todoCollector{
+		todos:   todos,
+		open:    prometheus.NewDesc("todos_open", "Open todos in all lists.", nil, nil),
+		overdue: prometheus.NewDesc("todos_overdue", "Open todos past their due date.", nil, nil),
+	}
+}
+
+func (c *todoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.overdue
+}
+
+func (c *todoCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.todos.CountTodos(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.open, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(counts.Open))
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(counts.Overdue))
+}
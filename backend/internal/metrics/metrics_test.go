@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+type fakeCounter struct {
+	counts *models.TodoCounts
+	err    error
+}
+
+func (c fakeCounter) CountTodos(context.Context) (*models.TodoCounts, error) {
+	return c.counts, c.err
+}
+
+func TestMiddlewareLabelsRoutePattern(t *testing.T) {
+	m := New(&sql.DB{}, fakeCounter{counts: &models.TodoCounts{}})
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Route("/lists", func(r chi.Router) {
+		r.Get("/{listID}", func(w http.ResponseWriter, r *http.Request) {})
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})
+	})
+
+	for _, path := range []string{"/lists/1", "/lists/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/lists", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "/lists/{listID}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("POST", "/lists", "201")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("GET", "unmatched", "404")))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.duration))
+}
+
+func TestTxDone(t *testing.T) {
+	m := New(&sql.DB{}, fakeCounter{counts: &models.TodoCounts{}})
+
+	m.TxDone(repository.TxCommitted)
+	m.TxDone(repository.TxCommitted)
+	m.TxDone(repository.TxRolledBack)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.txs.WithLabelValues("commit")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.txs.WithLabelValues("rollback")))
+}
+
+func TestHandlerReportsTodos(t *testing.T) {
+	m := New(&sql.DB{}, fakeCounter{counts: &models.TodoCounts{Open: 7, Overdue: 2}})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "todos_open 7\n")
+	assert.Contains(t, body, "todos_overdue 2\n")
+	assert.Contains(t, body, `db_transactions_total{outcome="rollback"} 0`)
+	assert.Contains(t, body, "go_sql_open_connections")
+}
+
+func TestHandlerSurvivesFailingCount(t *testing.T) {
+	m := New(&sql.DB{}, fakeCounter{err: assert.AnError})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "todos_open")
+	assert.Contains(t, rec.Body.String(), "db_transactions_total")
+}
@@ -0,0 +1,13 @@
+package models
+
+// Agenda groups the open todos of a user by the day they are due or
+// scheduled for, as seen in the user's time zone on Day.
+type Agenda struct {
+	Day      Date    `json:"day"`
+	TimeZone string  `json:"time_zone"`
+	Overdue  []*Todo `json:"overdue"`
+	Today    []*Todo `json:"today"`
+	Tomorrow []*Todo `json:"tomorrow"`
+	ThisWeek []*Todo `json:"this_week"`
+	Later    []*Todo `json:"later"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file attached to a todo. Its content lives in the blob
+// store under StorageKey.
+type Attachment struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TodoID      uuid.UUID `db:"todo_id" json:"todo_id"`
+	Filename    string    `db:"filename" json:"filename"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size" json:"size"`
+	SHA256      string    `db:"sha256" json:"sha256"`
+	StorageKey  string    `db:"storage_key" json:"storage_key"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// BlobDeletion is a blob left behind by a deleted attachment.
+type BlobDeletion struct {
+	ID         int64  `db:"id"`
+	StorageKey string `db:"storage_key"`
+}
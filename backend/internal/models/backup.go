@@ -0,0 +1,22 @@
+package models
+
+// AccountData is everything stored for a user, as written to and read from
+// a backup archive.
+type AccountData struct {
+	User    *User         `json:"user"`
+	Lists   []*TodoList   `json:"lists"`
+	Todos   []*Todo       `json:"todos"`
+	History []*TodoChange `json:"history"`
+	// Attachments only carries metadata, the content stays in the blob
+	// store.
+	Attachments []*Attachment `json:"attachments"`
+}
+
+// RestoreResult counts the records created by a restore.
+type RestoreResult struct {
+	Lists       int  `json:"lists"`
+	Todos       int  `json:"todos"`
+	History     int  `json:"history"`
+	Attachments int  `json:"attachments"`
+	RemappedIDs bool `json:"remapped_ids"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BoardColumn is a Kanban column of a list. Columns with a WIP limit hold
+// at most that many todos.
+type BoardColumn struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	ListID    uuid.UUID `db:"list_id" json:"list_id"`
+	Name      string    `db:"name" json:"name"`
+	Position  int       `db:"position" json:"position"`
+	WIPLimit  *int      `db:"wip_limit" json:"wip_limit,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// BoardColumnTodos is a column together with its todos in rank order.
+type BoardColumnTodos struct {
+	*BoardColumn
+	Todos []*Todo `json:"todos"`
+}
+
+// Board is a list shown as Kanban board. Todos that were not put into a
+// column yet are Unplaced.
+type Board struct {
+	ListID   uuid.UUID           `json:"list_id"`
+	Columns  []*BoardColumnTodos `json:"columns"`
+	Unplaced []*Todo             `json:"unplaced"`
+}
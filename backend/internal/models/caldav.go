@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// CalendarObject is a todo as exposed through CalDAV, together with the
+// resource name and UID its calendar client knows it by.
+type CalendarObject struct {
+	Todo
+	Name string `db:"dav_name"`
+	UID  string `db:"dav_uid"`
+}
+
+// CalendarChange is the latest change of a calendar object since a sync
+// token.
+type CalendarChange struct {
+	TodoID  uuid.UUID `db:"todo_id"`
+	Name    string    `db:"dav_name"`
+	Deleted bool      `db:"deleted"`
+}
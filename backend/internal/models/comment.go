@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Comment is a Markdown comment on a todo. Replies point at their parent;
+// a deleted comment keeps its place in the thread with an empty body.
+type Comment struct {
+	ID        uuid.UUID      `db:"id" json:"id"`
+	TodoID    uuid.UUID      `db:"todo_id" json:"todo_id"`
+	ParentID  *uuid.UUID     `db:"parent_id" json:"parent_id,omitempty"`
+	AuthorID  *uuid.UUID     `db:"author_id" json:"author_id,omitempty"`
+	Body      string         `db:"body" json:"body"`
+	Mentions  pq.StringArray `db:"mentions" json:"mentions,omitempty"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	EditedAt  *time.Time     `db:"edited_at" json:"edited_at,omitempty"`
+	DeletedAt *time.Time     `db:"deleted_at" json:"deleted_at,omitempty"`
+	Replies   []*Comment     `db:"-" json:"replies,omitempty"`
+}
+
+// CommentRevision is a body a comment had before it was edited.
+type CommentRevision struct {
+	ID         int64     `db:"id" json:"id"`
+	CommentID  uuid.UUID `db:"comment_id" json:"comment_id"`
+	Body       string    `db:"body" json:"body"`
+	WrittenAt  time.Time `db:"written_at" json:"written_at"`
+	ReplacedAt time.Time `db:"replaced_at" json:"replaced_at"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate(t *testing.T) {
+	d, err := ParseDate("2024-02-28")
+	require.NoError(t, err)
+	assert.Equal(t, Date{Year: 2024, Month: time.February, Day: 28}, d)
+	assert.Equal(t, "2024-02-29", d.AddDays(1).String())
+	assert.Equal(t, "2024-03-01", d.AddDays(2).String())
+	assert.True(t, d.Before(d.AddDays(1)))
+	assert.False(t, d.Before(d))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-27T23:00:00Z", d.In(berlin).UTC().Format(time.RFC3339))
+
+	_, err = ParseDate("28.02.2024")
+	assert.Error(t, err)
+}
+
+func TestDateJSON(t *testing.T) {
+	var todo struct {
+		DueOn *Date `json:"due_on,omitempty"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"due_on":"2024-06-07"}`), &todo))
+	require.NotNil(t, todo.DueOn)
+	assert.Equal(t, Date{Year: 2024, Month: time.June, Day: 7}, *todo.DueOn)
+
+	out, err := json.Marshal(todo)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"due_on":"2024-06-07"}`, string(out))
+
+	var scanned Date
+	require.NoError(t, scanned.Scan(time.Date(2024, time.June, 7, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, *todo.DueOn, scanned)
+	require.NoError(t, scanned.Scan([]byte("2024-06-08")))
+	assert.Equal(t, "2024-06-08", scanned.String())
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dependency records that a todo cannot be completed before another one,
+// its blocker.
+type Dependency struct {
+	TodoID    uuid.UUID `db:"todo_id" json:"todo_id"`
+	BlockedBy uuid.UUID `db:"blocked_by" json:"blocked_by"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// TodoDependencies are the todos a todo waits for and those waiting for it.
+type TodoDependencies struct {
+	BlockedBy []*Todo `json:"blocked_by"`
+	Blocks    []*Todo `json:"blocks"`
+}
+
+// DependencyGraph holds the todos of a list, the todos of other lists they
+// depend on or that depend on them, and the dependencies between them.
+type DependencyGraph struct {
+	Todos        []*Todo      `json:"todos"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// NextTodo is an open todo in work order together with the open todos it
+// still waits for. Todos without open blockers can be worked on now.
+type NextTodo struct {
+	*Todo
+	BlockedBy []uuid.UUID `json:"blocked_by"`
+}
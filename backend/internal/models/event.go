@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	// EventMention tells a user they were mentioned in a comment.
+	EventMention EventType = "mention"
+	// EventAssigned tells a user a todo was assigned to them.
+	EventAssigned EventType = "assigned"
+	// EventUnassigned records that a user was removed from a todo.
+	EventUnassigned EventType = "unassigned"
+)
+
+// Event is something a user should hear about, such as being mentioned or
+// assigned. Events are written together with the change that caused them
+// and consumed by the notifier.
+type Event struct {
+	ID        int64      `db:"id" json:"id"`
+	Type      EventType  `db:"type" json:"type"`
+	UserID    uuid.UUID  `db:"user_id" json:"user_id"`
+	ActorID   *uuid.UUID `db:"actor_id" json:"actor_id,omitempty"`
+	TodoID    uuid.UUID  `db:"todo_id" json:"todo_id"`
+	CommentID *uuid.UUID `db:"comment_id" json:"comment_id,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
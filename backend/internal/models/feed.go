@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken grants read access to the calendar feed of either a user or a
+// single list. The secret token itself is only known when it is created.
+type FeedToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	UserID    *uuid.UUID `db:"user_id" json:"user_id,omitempty"`
+	ListID    *uuid.UUID `db:"list_id" json:"list_id,omitempty"`
+	Token     string     `db:"-" json:"token,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+}
+
+// Feed is the content of a calendar feed.
+type Feed struct {
+	Name  string
+	Todos []*Todo
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoChange is an entry of the append-only todo change log. A todo that
+// moves to another list is logged as deleted in its old list. Status is
+// whether the todo was done after the change.
+type TodoChange struct {
+	Seq       int64     `db:"seq" json:"seq"`
+	ListID    uuid.UUID `db:"list_id" json:"list_id"`
+	TodoID    uuid.UUID `db:"todo_id" json:"todo_id"`
+	Deleted   bool      `db:"deleted" json:"deleted"`
+	Status    bool      `db:"status" json:"status"`
+	ChangedAt time.Time `db:"changed_at" json:"changed_at"`
+}
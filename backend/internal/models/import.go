@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportSucceeded ImportStatus = "succeeded"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// RowError describes an entry of an imported file that could not be
+// imported. Location is a line number for line based formats and a path
+// for JSON.
+type RowError struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// RowErrors is stored as a JSONB array. Values are passed as strings since
+// lib/pq sends byte slices as bytea.
+type RowErrors []RowError
+
+func (e RowErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(e)
+	return string(data), err
+}
+
+func (e *RowErrors) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, e)
+	case string:
+		return json.Unmarshal([]byte(src), e)
+	default:
+		return fmt.Errorf("cannot scan %T into RowErrors", src)
+	}
+}
+
+// ImportJob tracks an asynchronous import of another tool's export file.
+type ImportJob struct {
+	ID           uuid.UUID    `db:"id" json:"id"`
+	OwnerID      *uuid.UUID   `db:"owner_id" json:"owner_id,omitempty"`
+	Source       string       `db:"source" json:"source"`
+	ListName     string       `db:"list_name" json:"list_name"`
+	FileHash     []byte       `db:"file_hash" json:"-"`
+	Payload      []byte       `db:"payload" json:"-"`
+	Status       ImportStatus `db:"status" json:"status"`
+	Total        int          `db:"total" json:"total"`
+	Processed    int          `db:"processed" json:"processed"`
+	CreatedLists int          `db:"created_lists" json:"created_lists"`
+	CreatedTodos int          `db:"created_todos" json:"created_todos"`
+	Errors       RowErrors    `db:"errors" json:"errors"`
+	Error        string       `db:"error" json:"error,omitempty"`
+	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time    `db:"updated_at" json:"updated_at"`
+	FinishedAt   *time.Time   `db:"finished_at" json:"finished_at,omitempty"`
+	// ClaimedAt is when a worker last started running the job
+	ClaimedAt   *time.Time `db:"claimed_at" json:"claimed_at,omitempty"`
+	HeartbeatAt *time.Time `db:"heartbeat_at" json:"-"`
+	// Attempts counts the workers that have claimed the job
+	Attempts int `db:"attempts" json:"attempts"`
+}
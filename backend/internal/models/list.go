@@ -6,8 +6,48 @@ import (
 	"github.com/google/uuid"
 )
 
+// TodoList is a list of todos. Archived lists, with ArchivedAt set, are
+// left out of listings but can still be read. AutoArchiveDays archives the
+// todos of the list that were completed that many days ago.
 type TodoList struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID              uuid.UUID  `db:"id" json:"id"`
+	Name            string     `db:"name" json:"name"`
+	OwnerID         *uuid.UUID `db:"owner_id" json:"owner_id,omitempty"`
+	WorkspaceID     *uuid.UUID `db:"workspace_id" json:"workspace_id,omitempty"`
+	FolderID        *uuid.UUID `db:"folder_id" json:"folder_id,omitempty"`
+	ArchivedAt      *time.Time `db:"archived_at" json:"archived_at,omitempty"`
+	AutoArchiveDays *int       `db:"auto_archive_days" json:"auto_archive_days,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ListFilter selects lists: only those of a workspace or folder when set,
+// and archived lists too with Archived.
+type ListFilter struct {
+	WorkspaceID *uuid.UUID
+	FolderID    *uuid.UUID
+	Archived    bool
+}
+
+// ListMember is a user a list is shared with.
+type ListMember struct {
+	ListID   uuid.UUID `db:"list_id" json:"list_id"`
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	Username string    `db:"username" json:"username"`
+	AddedAt  time.Time `db:"added_at" json:"added_at"`
+}
+
+// ListWithTodos is a list together with its todos, as moved in and out of
+// the app by import and export.
+type ListWithTodos struct {
+	TodoList
+	Todos []*Todo `json:"todos"`
+}
+
+// CloneOptions controls how a list is copied. OffsetDays moves every date
+// of the todos by that many days and ResetCompletion reopens completed
+// todos.
+type CloneOptions struct {
+	Name            string `json:"name,omitempty"`
+	OffsetDays      int    `json:"offset_days,omitempty"`
+	ResetCompletion bool   `json:"reset_completion,omitempty"`
 }
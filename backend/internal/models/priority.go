@@ -0,0 +1,40 @@
+package models
+
+import "fmt"
+
+// Priority of a todo. It is stored as a number and written as a word in
+// JSON; todos without a priority leave it out.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = [...]string{"none", "low", "medium", "high"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityHigh {
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+func (p Priority) MarshalText() ([]byte, error) {
+	if p < PriorityNone || p > PriorityHigh {
+		return nil, fmt.Errorf("invalid priority %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	for i, name := range priorityNames {
+		if string(text) == name {
+			*p = Priority(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid priority %q, use none, low, medium or high", text)
+}
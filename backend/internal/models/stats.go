@@ -0,0 +1,84 @@
+package models
+
+import "github.com/google/uuid"
+
+// StatsDay is the activity of a list or user on one day: the todos created
+// and completed that day and those overdue at its end.
+type StatsDay struct {
+	Day       Date `db:"day" json:"day"`
+	Created   int  `db:"created" json:"created"`
+	Completed int  `db:"completed" json:"completed"`
+	Overdue   int  `db:"overdue" json:"overdue"`
+	// CompletionSeconds is the time the todos completed that day took from
+	// creation to completion, summed.
+	CompletionSeconds float64 `db:"completion_seconds" json:"-"`
+}
+
+// StatsRow is a day of activity of the list ListID, or of the todos
+// assigned to the user UserID.
+type StatsRow struct {
+	ListID *uuid.UUID `db:"list_id"`
+	UserID *uuid.UUID `db:"user_id"`
+	Name   string     `db:"name"`
+	StatsDay
+}
+
+// GroupStats sums up the activity of a list or user over a range of days.
+// A streak is a run of consecutive days with at least one completion; the
+// current streak ends on the last day of the range, or the day before when
+// that is today and nothing has been completed yet.
+type GroupStats struct {
+	ListID                 *uuid.UUID  `json:"list_id,omitempty"`
+	UserID                 *uuid.UUID  `json:"user_id,omitempty"`
+	Name                   string      `json:"name"`
+	Created                int         `json:"created"`
+	Completed              int         `json:"completed"`
+	AverageCompletionHours *float64    `json:"average_completion_hours,omitempty"`
+	CurrentStreak          int         `json:"current_streak"`
+	LongestStreak          int         `json:"longest_streak"`
+	Days                   []*StatsDay `json:"days"`
+}
+
+// Stats is the activity in the lists a user can access from From to To,
+// both included, with days in the user's time zone.
+type Stats struct {
+	From     Date          `json:"from"`
+	To       Date          `json:"to"`
+	TimeZone string        `json:"time_zone"`
+	Lists    []*GroupStats `json:"lists"`
+	Users    []*GroupStats `json:"users"`
+}
+
+// StatsQuery selects the activity to sum up: that of the lists a user can
+// access, or only of the list ListID, on the days from From to To.
+type StatsQuery struct {
+	UserID   uuid.UUID
+	ListID   *uuid.UUID
+	From     Date
+	To       Date
+	TimeZone string
+}
+
+// BurndownDay counts the open and done todos in a list at the end of a day.
+type BurndownDay struct {
+	Day  Date `db:"day" json:"day"`
+	Open int  `db:"open" json:"open"`
+	Done int  `db:"done" json:"done"`
+}
+
+// Burndown is the number of open and done todos in a list at the end of
+// every day from From to To in TimeZone, as recorded by the change log.
+type Burndown struct {
+	ListID   uuid.UUID      `json:"list_id"`
+	From     Date           `json:"from"`
+	To       Date           `json:"to"`
+	TimeZone string         `json:"time_zone"`
+	Days     []*BurndownDay `json:"days"`
+}
+
+// TodoCounts counts the open todos across all lists and those of them that
+// are overdue.
+type TodoCounts struct {
+	Open    int `db:"open"`
+	Overdue int `db:"overdue"`
+}
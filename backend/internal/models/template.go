@@ -0,0 +1,148 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ListTemplate is a saved list that new lists are created from. Its list
+// name and the titles, descriptions and tags of its todos may contain
+// placeholders such as {{client}}, which are filled in by Instantiate.
+type ListTemplate struct {
+	ID        uuid.UUID     `db:"id" json:"id"`
+	OwnerID   *uuid.UUID    `db:"owner_id" json:"owner_id,omitempty"`
+	Name      string        `db:"name" json:"name"`
+	ListName  string        `db:"list_name" json:"list_name"`
+	Todos     TemplateTodos `db:"todos" json:"todos"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// TemplateTodo is a todo of a template. A todo with DueInDays is due all
+// day that many days after the day the template is instantiated for.
+type TemplateTodo struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Priority    Priority `json:"priority,omitempty"`
+	Recurrence  string   `json:"recurrence,omitempty"`
+	DueInDays   *int     `json:"due_in_days,omitempty"`
+}
+
+// TemplateTodos is stored as a JSONB array. Values are passed as strings
+// since lib/pq sends byte slices as bytea.
+type TemplateTodos []TemplateTodo
+
+func (t TemplateTodos) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(t)
+	return string(data), err
+}
+
+func (t *TemplateTodos) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, t)
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	default:
+		return fmt.Errorf("cannot scan %T into TemplateTodos", src)
+	}
+}
+
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// MissingVariablesError is returned by Instantiate when placeholders have
+// no value.
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return "missing template variables: " + strings.Join(e.Names, ", ")
+}
+
+// Variables returns the names of the placeholders used in the template in
+// alphabetical order.
+func (t *ListTemplate) Variables() []string {
+	seen := map[string]bool{}
+	t.eachText(func(s string) string {
+		for _, m := range placeholder.FindAllStringSubmatch(s, -1) {
+			seen[m[1]] = true
+		}
+		return s
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Instantiate fills in the placeholders with vars and returns the list and
+// todos to create, without IDs. Due dates are counted from start.
+func (t *ListTemplate) Instantiate(vars map[string]string, start Date) (*ListWithTodos, error) {
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingVariablesError{Names: missing}
+	}
+
+	filled := *t
+	filled.Todos = make(TemplateTodos, len(t.Todos))
+	for i, todo := range t.Todos {
+		filled.Todos[i] = todo
+		filled.Todos[i].Tags = append([]string(nil), todo.Tags...)
+	}
+	filled.eachText(func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			return vars[placeholder.FindStringSubmatch(m)[1]]
+		})
+	})
+
+	l := &ListWithTodos{TodoList: TodoList{Name: filled.ListName}, Todos: make([]*Todo, len(filled.Todos))}
+	for i, todo := range filled.Todos {
+		l.Todos[i] = &Todo{
+			Title:       todo.Title,
+			Description: todo.Description,
+			Tags:        pq.StringArray(todo.Tags),
+			Priority:    todo.Priority,
+			Recurrence:  todo.Recurrence,
+		}
+		if todo.DueInDays != nil {
+			due := start.AddDays(*todo.DueInDays)
+			l.Todos[i].DueOn = &due
+		}
+	}
+	return l, nil
+}
+
+// eachText replaces every text of the template that may hold placeholders
+// with the result of fn.
+func (t *ListTemplate) eachText(fn func(string) string) {
+	t.ListName = fn(t.ListName)
+	for i := range t.Todos {
+		todo := &t.Todos[i]
+		todo.Title = fn(todo.Title)
+		todo.Description = fn(todo.Description)
+		for j := range todo.Tags {
+			todo.Tags[j] = fn(todo.Tags[j])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTemplateInstantiate(t *testing.T) {
+	days := func(n int) *int { return &n }
+	tmpl := &ListTemplate{
+		Name:     "Onboarding",
+		ListName: "Onboarding {{name}}",
+		Todos: TemplateTodos{
+			{Title: "Create account for {{ name }}", Tags: []string{"{{team}}"}, Priority: PriorityHigh, DueInDays: days(0)},
+			{Title: "Introduce to {{team}}", Description: "Ask {{buddy}} to show {{name}} around", DueInDays: days(3)},
+			{Title: "Weekly check-in", Recurrence: "FREQ=WEEKLY"},
+		},
+	}
+	assert.Equal(t, []string{"buddy", "name", "team"}, tmpl.Variables())
+
+	_, err := tmpl.Instantiate(map[string]string{"name": "Ada"}, Date{})
+	var missing *MissingVariablesError
+	require.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{"buddy", "team"}, missing.Names)
+
+	monday := Date{Year: 2025, Month: time.March, Day: 10}
+	l, err := tmpl.Instantiate(map[string]string{"name": "Ada", "team": "platform", "buddy": ""}, monday)
+	require.NoError(t, err)
+	assert.Equal(t, "Onboarding Ada", l.Name)
+	require.Len(t, l.Todos, 3)
+
+	assert.Equal(t, "Create account for Ada", l.Todos[0].Title)
+	assert.Equal(t, pq.StringArray{"platform"}, l.Todos[0].Tags)
+	assert.Equal(t, PriorityHigh, l.Todos[0].Priority)
+	assert.Equal(t, &monday, l.Todos[0].DueOn)
+
+	assert.Equal(t, "Introduce to platform", l.Todos[1].Title)
+	assert.Equal(t, "Ask  to show Ada around", l.Todos[1].Description)
+	assert.Equal(t, &Date{Year: 2025, Month: time.March, Day: 13}, l.Todos[1].DueOn)
+
+	assert.Nil(t, l.Todos[2].DueOn)
+	assert.Equal(t, "FREQ=WEEKLY", l.Todos[2].Recurrence)
+
+	// the template itself is left as it was
+	assert.Equal(t, "Onboarding {{name}}", tmpl.ListName)
+	assert.Equal(t, []string{"{{team}}"}, tmpl.Todos[0].Tags)
+}
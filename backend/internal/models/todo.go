@@ -1,18 +1,175 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/awnzl/to-do-app/internal/recurrence"
 )
 
+// MaxTitleLength matches the VARCHAR(255) column of todo titles.
+const MaxTitleLength = 255
+
 type Todo struct {
-	ID          uuid.UUID  `db:"id" json:"id"`
-	ListID      uuid.UUID  `db:"list_id" json:"list_id"`
-	Title       string     `db:"title" json:"title"`
-	Description string     `db:"description" json:"description,omitempty"`
-	DueDate     *time.Time `db:"due_date" json:"due_date,omitempty"`
-	Status      bool       `db:"status" json:"status"`
-	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	ID           uuid.UUID      `db:"id" json:"id"`
+	ListID       uuid.UUID      `db:"list_id" json:"list_id"`
+	Title        string         `db:"title" json:"title"`
+	Description  string         `db:"description" json:"description,omitempty"`
+	DueDate      *time.Time     `db:"due_date" json:"due_date,omitempty"`
+	DueOn        *Date          `db:"due_on" json:"due_on,omitempty"`
+	TimeZone     string         `db:"time_zone" json:"time_zone,omitempty"`
+	StartDate    *Date          `db:"start_date" json:"start_date,omitempty"`
+	ScheduledFor *Date          `db:"scheduled_for" json:"scheduled_for,omitempty"`
+	ColumnID     *uuid.UUID     `db:"column_id" json:"column_id,omitempty"`
+	Rank         int            `db:"rank" json:"rank"`
+	Status       bool           `db:"status" json:"status"`
+	Tags         pq.StringArray `db:"tags" json:"tags,omitempty"`
+	Priority     Priority       `db:"priority" json:"priority,omitempty"`
+	Recurrence   string         `db:"recurrence" json:"recurrence,omitempty"`
+	CompletedAt  *time.Time     `db:"completed_at" json:"completed_at,omitempty"`
+	ArchivedAt   *time.Time     `db:"archived_at" json:"archived_at,omitempty"`
+	Assignees    pq.StringArray `db:"assignees" json:"assignees,omitempty"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+	// ListTimeZone is the time zone of the list owner, or UTC, which todos
+	// without a time zone of their own are due in. Only queries that need
+	// it load it.
+	ListTimeZone string `db:"list_time_zone" json:"-"`
+}
+
+// LoadTimeZone loads an IANA time zone such as "Europe/Berlin". Unlike
+// time.LoadLocation it rejects "Local", the zone of the server.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// ListLocation returns the time zone of the list owner when it was loaded
+// with the todo, or fallback.
+func (t *Todo) ListLocation(fallback *time.Location) *time.Location {
+	if t.ListTimeZone == "" {
+		return fallback
+	}
+	loc, err := LoadTimeZone(t.ListTimeZone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// Location returns the time zone the todo's due date is evaluated in, or
+// fallback when the todo has none of its own.
+func (t *Todo) Location(fallback *time.Location) *time.Location {
+	if t.TimeZone == "" {
+		return fallback
+	}
+	loc, err := LoadTimeZone(t.TimeZone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// Deadline returns the instant the todo becomes overdue: its due time, or
+// the end of its all-day due date in its time zone. fallback is the time
+// zone of todos without one of their own, usually the list owner's.
+func (t *Todo) Deadline(fallback *time.Location) (time.Time, bool) {
+	switch {
+	case t.DueDate != nil:
+		return *t.DueDate, true
+	case t.DueOn != nil:
+		return t.DueOn.AddDays(1).In(t.Location(fallback)), true
+	}
+	return time.Time{}, false
+}
+
+// Validate checks the fields of a todo that the database stores as given
+// but later relies on: a title, a known priority, a recurrence rule the
+// app can represent, a single kind of due date and an IANA time zone.
+func (t *Todo) Validate() error {
+	switch {
+	case strings.TrimSpace(t.Title) == "":
+		return fmt.Errorf("title is required")
+	case utf8.RuneCountInString(t.Title) > MaxTitleLength:
+		return fmt.Errorf("title is longer than %d characters", MaxTitleLength)
+	case t.Priority < PriorityNone || t.Priority > PriorityHigh:
+		return fmt.Errorf("invalid priority %d", int(t.Priority))
+	case t.DueDate != nil && t.DueOn != nil:
+		return fmt.Errorf("set either due_date or due_on, not both")
+	}
+	if t.Recurrence != "" {
+		if _, err := recurrence.Parse(t.Recurrence); err != nil {
+			return err
+		}
+	}
+	if t.TimeZone != "" {
+		if _, err := LoadTimeZone(t.TimeZone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Schedule is when a todo is due and planned as a client gives it. It is
+// due at an instant, or on a day with an optional time of day (HH:MM) in a
+// time zone; a day without a time of day makes an all-day todo. Todos are
+// hidden from the agenda before their start date and listed on the day they
+// are scheduled for.
+type Schedule struct {
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	DueOn        *Date      `json:"due_on,omitempty"`
+	DueTime      string     `json:"due_time,omitempty"`
+	TimeZone     string     `json:"time_zone,omitempty"`
+	StartDate    *Date      `json:"start_date,omitempty"`
+	ScheduledFor *Date      `json:"scheduled_for,omitempty"`
+}
+
+const dueTimeLayout = "15:04"
+
+func (d Schedule) Validate() error {
+	if d.DueDate != nil && d.DueOn != nil {
+		return fmt.Errorf("set either due_date or due_on, not both")
+	}
+	if d.DueTime != "" {
+		if d.DueOn == nil {
+			return fmt.Errorf("due_time needs a due_on date")
+		}
+		if _, err := time.Parse(dueTimeLayout, d.DueTime); err != nil {
+			return fmt.Errorf("invalid due_time %q, use HH:MM", d.DueTime)
+		}
+	}
+	if d.TimeZone != "" {
+		if _, err := LoadTimeZone(d.TimeZone); err != nil {
+			return fmt.Errorf("unknown time zone %q", d.TimeZone)
+		}
+	}
+	return nil
+}
+
+// Apply sets when todo is due and planned. A time of day is read in the
+// given time zone, or in fallback when there is none.
+func (d Schedule) Apply(todo *Todo, fallback *time.Location) {
+	todo.TimeZone = d.TimeZone
+	todo.StartDate, todo.ScheduledFor = d.StartDate, d.ScheduledFor
+	todo.DueDate, todo.DueOn = d.DueDate, nil
+	if d.DueOn == nil {
+		return
+	}
+
+	clock, err := time.Parse(dueTimeLayout, d.DueTime)
+	if err != nil {
+		day := *d.DueOn
+		todo.DueOn = &day
+		return
+	}
+	day := d.DueOn
+	due := time.Date(day.Year, day.Month, day.Day, clock.Hour(), clock.Minute(), 0, 0, todo.Location(fallback))
+	todo.DueDate = &due
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleApply(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	friday := Date{Year: 2025, Month: time.March, Day: 14}
+
+	var todo Todo
+	Schedule{DueOn: &friday}.Apply(&todo, berlin)
+	assert.Nil(t, todo.DueDate)
+	assert.Equal(t, &friday, todo.DueOn)
+
+	deadline, ok := todo.Deadline(berlin)
+	require.True(t, ok)
+	assert.Equal(t, "2025-03-14T23:00:00Z", deadline.UTC().Format(time.RFC3339))
+
+	Schedule{DueOn: &friday, DueTime: "17:30"}.Apply(&todo, berlin)
+	assert.Nil(t, todo.DueOn)
+	require.NotNil(t, todo.DueDate)
+	assert.Equal(t, "2025-03-14T16:30:00Z", todo.DueDate.UTC().Format(time.RFC3339))
+
+	Schedule{DueOn: &friday, DueTime: "17:30", TimeZone: "America/New_York"}.Apply(&todo, berlin)
+	assert.Equal(t, "America/New_York", todo.TimeZone)
+	assert.Equal(t, "2025-03-14T21:30:00Z", todo.DueDate.UTC().Format(time.RFC3339))
+
+	monday := friday.AddDays(3)
+	Schedule{StartDate: &friday, ScheduledFor: &monday}.Apply(&todo, berlin)
+	assert.Equal(t, &friday, todo.StartDate)
+	assert.Equal(t, &monday, todo.ScheduledFor)
+	_, ok = todo.Deadline(berlin)
+	assert.False(t, ok)
+}
+
+func TestScheduleValidate(t *testing.T) {
+	friday := Date{Year: 2025, Month: time.March, Day: 14}
+	now := time.Now()
+
+	assert.NoError(t, Schedule{DueOn: &friday, DueTime: "09:00", TimeZone: "Europe/Berlin"}.Validate())
+	assert.Error(t, Schedule{DueDate: &now, DueOn: &friday}.Validate())
+	assert.Error(t, Schedule{DueTime: "09:00"}.Validate())
+	assert.Error(t, Schedule{DueOn: &friday, DueTime: "9am"}.Validate())
+	assert.Error(t, Schedule{TimeZone: "Mars/Olympus"}.Validate())
+	// the server's own zone is not a zone clients can name
+	assert.Error(t, Schedule{TimeZone: "Local"}.Validate())
+	assert.Equal(t, time.UTC, (&Todo{TimeZone: "Local"}).Location(time.UTC))
+}
+
+func TestTodoValidate(t *testing.T) {
+	friday := Date{Year: 2025, Month: time.March, Day: 14}
+	now := time.Now()
+
+	assert.NoError(t, (&Todo{Title: "Pay rent", Priority: PriorityHigh, Recurrence: "FREQ=MONTHLY", TimeZone: "Europe/Berlin"}).Validate())
+	assert.Error(t, (&Todo{Title: " "}).Validate())
+	assert.Error(t, (&Todo{Title: strings.Repeat("a", MaxTitleLength+1)}).Validate())
+	assert.Error(t, (&Todo{Title: "a", Priority: PriorityHigh + 1}).Validate())
+	assert.Error(t, (&Todo{Title: "a", Recurrence: "FREQ=SOMETIMES"}).Validate())
+	assert.Error(t, (&Todo{Title: "a", DueDate: &now, DueOn: &friday}).Validate())
+	assert.Error(t, (&Todo{Title: "a", TimeZone: "Mars/Olympus"}).Validate())
+	assert.Error(t, (&Todo{Title: "a", TimeZone: "Local"}).Validate())
+}
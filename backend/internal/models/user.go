@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Username  string    `db:"username" json:"username"`
+	Email     string    `db:"email" json:"email"`
+	TimeZone  string    `db:"time_zone" json:"time_zone"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Location returns the user's time zone, falling back to UTC when the stored
+// name is unknown to the tz database.
+func (u *User) Location() *time.Location {
+	loc, err := LoadTimeZone(u.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type NotificationPreferences struct {
+	UserID           uuid.UUID  `db:"user_id" json:"user_id"`
+	DigestEnabled    bool       `db:"digest_enabled" json:"digest_enabled"`
+	DigestHour       int        `db:"digest_hour" json:"digest_hour"`
+	AssignmentEmails bool       `db:"assignment_emails" json:"assignment_emails"`
+	MentionEmails    bool       `db:"mention_emails" json:"mention_emails"`
+	LastDigestOn     *time.Time `db:"last_digest_on" json:"last_digest_on,omitempty"`
+}
+
+// DigestSubscriber is a user who receives the daily digest together with
+// the preferences that drive its scheduling.
+type DigestSubscriber struct {
+	User
+	DigestHour   int        `db:"digest_hour"`
+	LastDigestOn *time.Time `db:"last_digest_on"`
+}
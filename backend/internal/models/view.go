@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// View is a saved filter query, such as "due < now+7d and tag:work". See
+// package filter for the query language.
+type View struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	Name      string    `db:"name" json:"name"`
+	Query     string    `db:"query" json:"query"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TodoPage is one page of a longer list of todos. NextOffset is set when
+// there are more todos.
+type TodoPage struct {
+	Todos      []*Todo `json:"todos"`
+	Offset     int     `json:"offset"`
+	NextOffset *int    `json:"next_offset,omitempty"`
+}
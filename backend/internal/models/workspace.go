@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace groups the lists of a team. Its members can access all of its
+// lists. A deleted workspace and its lists are hidden until it is restored.
+type Workspace struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Name      string     `db:"name" json:"name"`
+	OwnerID   *uuid.UUID `db:"owner_id" json:"owner_id,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// WorkspaceMember is a user who belongs to a workspace.
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Username    string    `db:"username" json:"username"`
+	AddedAt     time.Time `db:"added_at" json:"added_at"`
+}
+
+// Folder groups lists within a workspace.
+type Folder struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// ListLocation is where a list is kept: in a folder of a workspace, at the
+// top of a workspace, or outside of any workspace when both are nil. A
+// folder alone stands for its workspace.
+type ListLocation struct {
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty"`
+	FolderID    *uuid.UUID `json:"folder_id,omitempty"`
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+type digestData struct {
+	User     *models.User
+	Day      time.Time
+	Overdue  []*models.Todo
+	DueToday []*models.Todo
+	Location *time.Location
+}
+
+// RunDigests checks every interval whose daily digest is due until ctx is
+// cancelled, finishing a check that has started.
+func (n *Notifier) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := n.SendDigests(context.WithoutCancel(ctx), now); err != nil {
+				slog.ErrorContext(ctx, "notify: send digests", "error", err)
+			}
+		}
+	}
+}
+
+// SendDigests enqueues the digest for every subscriber whose local digest
+// hour has passed and who has not received one for their local day yet. A
+// digest counts as sent once it has been delivered. Subscribers whose digest
+// cannot be prepared are logged and tried again on the next check.
+func (n *Notifier) SendDigests(ctx context.Context, now time.Time) error {
+	subscribers, err := n.repo.ListDigestSubscribers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subscribers {
+		if err := n.sendDigest(ctx, sub, now); err != nil {
+			slog.ErrorContext(ctx, "notify: send digest", "user_id", sub.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) sendDigest(ctx context.Context, sub *models.DigestSubscriber, now time.Time) error {
+	loc := sub.Location()
+	day, ok := digestDay(now, loc, sub.DigestHour, sub.LastDigestOn)
+	if !ok || !n.claimDigest(sub.ID, day) {
+		return nil
+	}
+
+	todos, err := n.repo.ListDueTodosForUser(ctx, sub.ID, day.AddDate(0, 0, 1))
+	if err != nil {
+		n.releaseDigest(sub.ID)
+		return err
+	}
+	if len(todos) == 0 {
+		return n.repo.MarkDigestSent(ctx, sub.ID, day)
+	}
+
+	data := digestData{User: &sub.User, Day: day, Location: loc}
+	data.Overdue, data.DueToday = splitOverdue(todos, now, loc)
+
+	subject := fmt.Sprintf("Your todos for %s", day.Format("Mon, Jan 2"))
+	msg, err := n.templates.Render("digest", sub.Email, subject, data)
+	if err != nil {
+		n.releaseDigest(sub.ID)
+		return err
+	}
+	queued := n.enqueue(func(ctx context.Context) error {
+		if err := n.sender.Send(ctx, msg); err != nil {
+			n.releaseDigest(sub.ID)
+			return fmt.Errorf("send %q to %s: %w", msg.Subject, msg.To, err)
+		}
+		return n.repo.MarkDigestSent(ctx, sub.ID, day)
+	})
+	if !queued {
+		// try again on the next tick
+		n.releaseDigest(sub.ID)
+	}
+	return nil
+}
+
+// claimDigest reports whether the digest of a subscriber for day is neither
+// queued nor delivered yet, and records that it is about to be queued.
+func (n *Notifier) claimDigest(userID uuid.UUID, day time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	d := day.Format(time.DateOnly)
+	if n.digestDays[userID] >= d {
+		return false
+	}
+	n.digestDays[userID] = d
+	return true
+}
+
+// releaseDigest forgets a digest that was not delivered, so that it is
+// tried again.
+func (n *Notifier) releaseDigest(userID uuid.UUID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.digestDays, userID)
+}
+
+// digestDay returns the start of the local day a digest is due for, if the
+// local digest hour has passed and no digest was sent for that day yet.
+func digestDay(now time.Time, loc *time.Location, hour int, lastSent *time.Time) (time.Time, bool) {
+	local := now.In(loc)
+	if local.Hour() < hour {
+		return time.Time{}, false
+	}
+
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if lastSent != nil && lastSent.Format(time.DateOnly) >= day.Format(time.DateOnly) {
+		return time.Time{}, false
+	}
+
+	return day, true
+}
+
+// splitOverdue separates the todos whose deadline has passed at now from
+// those still due. loc is the time zone of todos without one of their own.
+func splitOverdue(todos []*models.Todo, now time.Time, loc *time.Location) (overdue, due []*models.Todo) {
+	for _, todo := range todos {
+		if deadline, _ := todo.Deadline(loc); deadline.Before(now) {
+			overdue = append(overdue, todo)
+		} else {
+			due = append(due, todo)
+		}
+	}
+	return overdue, due
+}
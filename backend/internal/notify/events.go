@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// eventBatch is the number of events claimed per ProcessEvents call.
+const eventBatch = 50
+
+type mentionData struct {
+	User    *models.User
+	Actor   *models.User
+	Todo    *models.Todo
+	Comment *models.Comment
+}
+
+// RunEvents turns pending events into notifications every interval until
+// ctx is cancelled, finishing the events it has claimed.
+func (n *Notifier) RunEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.ProcessEvents(context.WithoutCancel(ctx)); err != nil {
+				slog.ErrorContext(ctx, "notify: process events", "error", err)
+			}
+		}
+	}
+}
+
+// ProcessEvents claims pending events and enqueues a notification for each
+// of them. An event that cannot be turned into a notification is logged and
+// not retried.
+func (n *Notifier) ProcessEvents(ctx context.Context) error {
+	events, err := n.repo.ClaimEvents(ctx, eventBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		var err error
+		switch e.Type {
+		case models.EventMention:
+			err = n.mentioned(ctx, e)
+		case models.EventAssigned:
+			// nobody needs an email about assigning themselves
+			if e.ActorID == nil || *e.ActorID != e.UserID {
+				n.TodoAssigned(e.UserID, e.TodoID)
+			}
+		case models.EventUnassigned:
+			// nothing to send, the event only records the change
+		default:
+			err = fmt.Errorf("unknown type %q", e.Type)
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "notify: handle event", "event_id", e.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// mentioned notifies a user mentioned in a comment, unless they opted out
+// of mention emails or the comment was deleted in the meantime.
+func (n *Notifier) mentioned(ctx context.Context, e *models.Event) error {
+	if e.CommentID == nil {
+		return fmt.Errorf("mention without comment")
+	}
+
+	prefs, err := n.repo.GetNotificationPreferences(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("get preferences of '%s': %w", e.UserID, err)
+	}
+	if !prefs.MentionEmails {
+		return nil
+	}
+
+	comment, err := n.repo.GetComment(ctx, e.TodoID, *e.CommentID)
+	if err != nil {
+		return fmt.Errorf("get comment '%s': %w", *e.CommentID, err)
+	}
+	if comment.DeletedAt != nil {
+		return nil
+	}
+
+	user, err := n.repo.GetUser(ctx, e.UserID)
+	if err != nil {
+		return fmt.Errorf("get user '%s': %w", e.UserID, err)
+	}
+	todo, err := n.repo.GetTodo(ctx, e.TodoID)
+	if err != nil {
+		return fmt.Errorf("get todo '%s': %w", e.TodoID, err)
+	}
+	data := mentionData{User: user, Todo: todo, Comment: comment}
+	if e.ActorID != nil {
+		if data.Actor, err = n.repo.GetUser(ctx, *e.ActorID); err != nil {
+			return fmt.Errorf("get user '%s': %w", *e.ActorID, err)
+		}
+	}
+
+	subject := "You were mentioned on " + todo.Title
+	if data.Actor != nil {
+		subject = data.Actor.Username + " mentioned you on " + todo.Title
+	}
+	msg, err := n.templates.Render("mentioned", user.Email, subject, data)
+	if err != nil {
+		return err
+	}
+	n.send(msg)
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package notify sends email notifications: the daily digest of due todos,
+// assignment notices and mentions in comments.
+//
+// All delivery happens on a background worker. Callers on request paths only
+// enqueue work, which never blocks; when the queue is full the notification
+// is dropped and logged.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+const sendTimeout = 30 * time.Second
+
+type job func(ctx context.Context) error
+
+type Notifier struct {
+	repo      repository.Repository
+	templates *Templates
+	sender    Sender
+	queue     chan job
+
+	mu sync.Mutex
+	// digestDays holds the day of the last digest queued for a subscriber,
+	// so that it is not queued again while it waits to be delivered
+	digestDays map[uuid.UUID]string
+}
+
+func NewNotifier(repo repository.Repository, templates *Templates, sender Sender, queueSize int) *Notifier {
+	return &Notifier{
+		repo:      repo,
+		templates: templates,
+		sender:    sender,
+		queue:     make(chan job, queueSize),
+
+		digestDays: map[uuid.UUID]string{},
+	}
+}
+
+// Run processes queued notifications until ctx is cancelled. A notification
+// that is being sent then is sent to the end; those still queued are left
+// for Drain.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-n.queue:
+			n.run(context.WithoutCancel(ctx), j)
+		}
+	}
+}
+
+// Drain processes the queued notifications until the queue is empty, so
+// that none are lost when the server stops. It gives up on those left when
+// ctx is done.
+func (n *Notifier) Drain(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			slog.Warn("notify: dropping queued notifications", "count", len(n.queue))
+			return err
+		}
+		select {
+		case j := <-n.queue:
+			n.run(ctx, j)
+		default:
+			return nil
+		}
+	}
+}
+
+func (n *Notifier) run(ctx context.Context, j job) {
+	jobCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if err := j(jobCtx); err != nil {
+		slog.ErrorContext(jobCtx, "notify: send notification", "error", err)
+	}
+}
+
+func (n *Notifier) enqueue(j job) bool {
+	select {
+	case n.queue <- j:
+		return true
+	default:
+		slog.Warn("notify: queue is full, dropping notification")
+		return false
+	}
+}
+
+func (n *Notifier) send(msg *Message) bool {
+	return n.enqueue(func(ctx context.Context) error {
+		if err := n.sender.Send(ctx, msg); err != nil {
+			return fmt.Errorf("send %q to %s: %w", msg.Subject, msg.To, err)
+		}
+		return nil
+	})
+}
+
+type assignmentData struct {
+	User     *models.User
+	Todo     *models.Todo
+	List     *models.TodoList
+	Location *time.Location
+}
+
+// TodoAssigned notifies the assignee that a todo was assigned to them,
+// unless they opted out of assignment emails.
+func (n *Notifier) TodoAssigned(assigneeID, todoID uuid.UUID) {
+	n.enqueue(func(ctx context.Context) error {
+		prefs, err := n.repo.GetNotificationPreferences(ctx, assigneeID)
+		if err != nil {
+			return fmt.Errorf("get preferences of '%s': %w", assigneeID, err)
+		}
+		if !prefs.AssignmentEmails {
+			return nil
+		}
+
+		user, err := n.repo.GetUser(ctx, assigneeID)
+		if err != nil {
+			return fmt.Errorf("get user '%s': %w", assigneeID, err)
+		}
+		todo, err := n.repo.GetTodo(ctx, todoID)
+		if err != nil {
+			return fmt.Errorf("get todo '%s': %w", todoID, err)
+		}
+		list, err := n.repo.GetList(ctx, todo.ListID)
+		if err != nil {
+			return fmt.Errorf("get list '%s': %w", todo.ListID, err)
+		}
+
+		msg, err := n.templates.Render("assigned", user.Email, "Assigned to you: "+todo.Title, assignmentData{
+			User:     user,
+			Todo:     todo,
+			List:     list,
+			Location: user.Location(),
+		})
+		if err != nil {
+			return err
+		}
+		return n.sender.Send(ctx, msg)
+	})
+}
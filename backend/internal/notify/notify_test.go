@@ -0,0 +1,300 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// fakeSMTPServer accepts a single connection, speaks just enough SMTP for
+// net/smtp and hands the received DATA payload to the returned channel.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.Fields(line)[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received <- string(data)
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return host, p, received
+}
+
+func TestSMTPSenderSendsRenderedDigest(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	due := time.Date(2025, 3, 14, 16, 0, 0, 0, time.UTC)
+	msg, err := templates.Render("digest", "ann@example.com", "Your todos", digestData{
+		User:     &models.User{Username: "ann"},
+		Day:      time.Date(2025, 3, 14, 0, 0, 0, 0, loc),
+		DueToday: []*models.Todo{{Title: "Water <plants>", DueDate: &due}},
+		Location: loc,
+	})
+	require.NoError(t, err)
+
+	sender := NewSMTPSender(SMTPConfig{Host: host, Port: port, From: "todo@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, sender.Send(ctx, msg))
+
+	var data string
+	select {
+	case data = <-received:
+	case <-ctx.Done():
+		t.Fatal("no message received")
+	}
+
+	tp := textproto.NewReader(bufio.NewReader(strings.NewReader(data)))
+	header, err := tp.ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Equal(t, "ann@example.com", header.Get("To"))
+	assert.Equal(t, "Your todos", header.Get("Subject"))
+	assert.Contains(t, header.Get("Content-Type"), "multipart/alternative")
+
+	assert.Contains(t, data, "Water <plants> (due Fri Mar 14 17:00)")
+	assert.Contains(t, data, "Water &lt;plants&gt;")
+}
+
+func TestDigestDay(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2025-03-14 23:30 UTC is already the 15th in Tokyo and still the 14th
+	// in New York.
+	now := time.Date(2025, 3, 14, 23, 30, 0, 0, time.UTC)
+	sentOn := func(s string) *time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		hour     int
+		lastSent *time.Time
+		wantDay  string
+		wantOK   bool
+	}{
+		{"before digest hour", tokyo, 9, nil, "", false},
+		{"after digest hour", newYork, 8, nil, "2025-03-14", true},
+		{"already sent today", newYork, 8, sentOn("2025-03-14"), "", false},
+		{"sent yesterday", newYork, 8, sentOn("2025-03-13"), "2025-03-14", true},
+		{"local day is ahead of UTC", tokyo, 8, sentOn("2025-03-14"), "2025-03-15", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, ok := digestDay(now, tt.loc, tt.hour, tt.lastSent)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantDay, day.Format(time.DateOnly))
+				assert.Equal(t, tt.loc, day.Location())
+			}
+		})
+	}
+}
+
+func TestSplitOverdue(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// 2025-03-14 23:30 UTC is already the 15th in Berlin
+	now := time.Date(2025, 3, 14, 23, 30, 0, 0, time.UTC)
+	dueAt := now.Add(-time.Hour)
+	friday := models.Date{Year: 2025, Month: time.March, Day: 14}
+	saturday := friday.AddDays(1)
+
+	timed := &models.Todo{Title: "timed", DueDate: &dueAt}
+	yesterday := &models.Todo{Title: "yesterday", DueOn: &friday}
+	today := &models.Todo{Title: "today", DueOn: &saturday}
+	// still Friday in New York
+	pinned := &models.Todo{Title: "pinned", DueOn: &friday, TimeZone: "America/New_York"}
+
+	overdue, due := splitOverdue([]*models.Todo{timed, yesterday, today, pinned}, now, berlin)
+	assert.Equal(t, []*models.Todo{timed, yesterday}, overdue)
+	assert.Equal(t, []*models.Todo{today, pinned}, due)
+
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+	msg, err := templates.Render("digest", "ann@example.com", "Your todos", digestData{
+		User:     &models.User{Username: "ann"},
+		Day:      saturday.In(berlin),
+		Overdue:  overdue,
+		DueToday: due,
+		Location: berlin,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, msg.Text, "timed (was due Fri Mar 14 23:30)")
+	assert.Contains(t, msg.Text, "yesterday (was due Fri Mar 14)")
+	assert.Contains(t, msg.Text, "today (due Sat Mar 15)")
+}
+
+func TestRenderMentioned(t *testing.T) {
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+
+	msg, err := templates.Render("mentioned", "ada@example.com", "grace mentioned you", mentionData{
+		User:    &models.User{Username: "ada"},
+		Actor:   &models.User{Username: "grace"},
+		Todo:    &models.Todo{Title: "Ship release"},
+		Comment: &models.Comment{Body: "@ada <b>please</b> review"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, msg.Text, "grace mentioned you in a comment on \"Ship release\"")
+	assert.Contains(t, msg.Text, "@ada <b>please</b> review")
+	assert.Contains(t, msg.HTML, "@ada &lt;b&gt;please&lt;/b&gt; review")
+}
+
+type recordingSender struct {
+	sent []string
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.sent = append(s.sent, msg.Subject)
+	return nil
+}
+
+func TestDrainSendsQueuedNotifications(t *testing.T) {
+	sender := &recordingSender{}
+	n := NewNotifier(nil, nil, sender, 4)
+	require.True(t, n.send(&Message{To: "ada@example.com", Subject: "first"}))
+	require.True(t, n.send(&Message{To: "ada@example.com", Subject: "second"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n.Run(ctx)
+	require.NoError(t, n.Drain(context.Background()))
+
+	assert.Equal(t, []string{"first", "second"}, sender.sent)
+}
+
+func TestDrainStopsWhenShutdownTimesOut(t *testing.T) {
+	sender := &recordingSender{}
+	n := NewNotifier(nil, nil, sender, 4)
+	require.True(t, n.send(&Message{To: "ada@example.com", Subject: "first"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, n.Drain(ctx), context.Canceled)
+	assert.Empty(t, sender.sent)
+}
+
+// digestRepo has a todo due for every subscriber except those in failing,
+// and records the digests marked as sent.
+type digestRepo struct {
+	repository.Repository
+	subscribers []*models.DigestSubscriber
+	failing     map[uuid.UUID]bool
+	sent        []uuid.UUID
+}
+
+func (r *digestRepo) ListDigestSubscribers(context.Context) ([]*models.DigestSubscriber, error) {
+	return r.subscribers, nil
+}
+
+func (r *digestRepo) ListDueTodosForUser(_ context.Context, userID uuid.UUID, _ time.Time) ([]*models.Todo, error) {
+	if r.failing[userID] {
+		return nil, errors.New("connection reset")
+	}
+	due := models.Date{Year: 2025, Month: time.March, Day: 12}
+	return []*models.Todo{{Title: "Pay rent", DueOn: &due}}, nil
+}
+
+func (r *digestRepo) MarkDigestSent(_ context.Context, userID uuid.UUID, _ time.Time) error {
+	r.sent = append(r.sent, userID)
+	return nil
+}
+
+func TestSendDigestsMarksDeliveredDigests(t *testing.T) {
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+	subscriber := func(name string) *models.DigestSubscriber {
+		return &models.DigestSubscriber{
+			User:       models.User{ID: uuid.New(), Username: name, Email: name + "@example.com", TimeZone: "UTC"},
+			DigestHour: 8,
+		}
+	}
+	ada, grace := subscriber("ada"), subscriber("grace")
+	repo := &digestRepo{subscribers: []*models.DigestSubscriber{ada, grace}, failing: map[uuid.UUID]bool{ada.ID: true}}
+	sender := &recordingSender{}
+	n := NewNotifier(repo, templates, sender, 4)
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+
+	// ada's failure does not hold up grace's digest
+	require.NoError(t, n.SendDigests(context.Background(), now))
+	assert.Len(t, n.queue, 1)
+	assert.Empty(t, repo.sent, "not sent before it is delivered")
+
+	// a queued digest is not queued again
+	require.NoError(t, n.SendDigests(context.Background(), now.Add(time.Minute)))
+	assert.Len(t, n.queue, 1)
+
+	require.NoError(t, n.Drain(context.Background()))
+	assert.Equal(t, []string{"Your todos for Wed, Mar 12"}, sender.sent)
+	assert.Equal(t, []uuid.UUID{grace.ID}, repo.sent)
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates renders notification emails from the embedded text and HTML
+// templates. Each notification kind has a <name>.txt.tmpl and a
+// <name>.html.tmpl file.
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templateFuncs = map[string]any{
+	// due formats when a todo is due, its time in loc or just the day for
+	// all-day todos
+	"due": func(todo *models.Todo, loc *time.Location) string {
+		switch {
+		case todo.DueDate != nil:
+			return todo.DueDate.In(loc).Format("Mon Jan 2 15:04")
+		case todo.DueOn != nil:
+			return todo.DueOn.In(time.UTC).Format("Mon Jan 2")
+		}
+		return ""
+	},
+}
+
+func LoadTemplates() (*Templates, error) {
+	text, err := texttemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parse text templates: %w", err)
+	}
+	html, err := htmltemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("parse html templates: %w", err)
+	}
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render executes both variants of the named template.
+func (t *Templates) Render(name, to, subject string, data any) (*Message, error) {
+	var text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+	return &Message{
+		To:      to,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,507 @@
+// Package quickadd turns a line of text such as
+//
+//	Pay rent every month on the 1st #home !high
+//
+// into the fields of a todo. Words that are recognized as a due date, time,
+// recurrence, tag or priority are taken out; the rest is the title.
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/recurrence"
+)
+
+// ErrNoTitle is returned for text that has nothing left for a title.
+var ErrNoTitle = errors.New("quick add text has no title")
+
+// Kind is the kind of a recognized phrase.
+type Kind string
+
+const (
+	KindDate       Kind = "date"
+	KindTime       Kind = "time"
+	KindRecurrence Kind = "recurrence"
+	KindTag        Kind = "tag"
+	KindPriority   Kind = "priority"
+)
+
+// Match is a phrase of the text that was recognized.
+type Match struct {
+	Kind Kind   `json:"kind"`
+	Text string `json:"text"`
+}
+
+// Result is the interpretation of a quick add text.
+type Result struct {
+	Title      string          `json:"title"`
+	DueDate    *time.Time      `json:"due_date,omitempty"`
+	DueOn      *models.Date    `json:"due_on,omitempty"`
+	Recurrence string          `json:"recurrence,omitempty"`
+	Tags       []string        `json:"tags,omitempty"`
+	Priority   models.Priority `json:"priority,omitempty"`
+	Matches    []Match         `json:"matches"`
+}
+
+// connectors are dropped together with a date, time or recurrence that
+// follows them, as in "call mom on friday at 5pm".
+var connectors = map[string]bool{"on": true, "at": true, "by": true, "due": true}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var priorities = map[string]models.Priority{
+	"!high": models.PriorityHigh, "!1": models.PriorityHigh,
+	"!medium": models.PriorityMedium, "!med": models.PriorityMedium, "!2": models.PriorityMedium,
+	"!low": models.PriorityLow, "!3": models.PriorityLow,
+}
+
+var (
+	clock12  = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	clockNum = regexp.MustCompile(`^\d{1,2}(?::\d{2})?$`)
+	clock24  = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	dayOfMon = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	year     = regexp.MustCompile(`^\d{4}$`)
+)
+
+type clock struct {
+	hour, minute int
+}
+
+type parser struct {
+	words []string // as written
+	norm  []string // lower case without trailing punctuation
+	used  []bool
+	now   time.Time
+	today time.Time
+
+	res     Result
+	date    *time.Time // midnight of the due day
+	clock   *clock
+	tonight bool
+	rule    *recurrence.Rule
+}
+
+// Parse interprets text. Relative dates and times are resolved against now
+// and in its location.
+func Parse(text string, now time.Time) (*Result, error) {
+	p := &parser{
+		words: strings.Fields(text),
+		now:   now,
+		today: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+		res:   Result{Matches: make([]Match, 0)},
+	}
+	p.used = make([]bool, len(p.words))
+	for _, w := range p.words {
+		p.norm = append(p.norm, strings.TrimRight(strings.ToLower(w), ",.;"))
+	}
+
+	matchers := []func(i int) int{p.tag, p.priority, p.recurrence, p.dueDate, p.timeOfDay}
+	for i := 0; i < len(p.words); i++ {
+		for _, match := range matchers {
+			if n := match(i); n > 0 {
+				i += n - 1
+				break
+			}
+		}
+	}
+
+	var title []string
+	for i, w := range p.words {
+		if !p.used[i] {
+			title = append(title, w)
+		}
+	}
+	p.res.Title = strings.Join(title, " ")
+	if p.res.Title == "" {
+		return nil, ErrNoTitle
+	}
+
+	p.resolveDue()
+	if p.rule != nil {
+		p.res.Recurrence = p.rule.String()
+	}
+	return &p.res, nil
+}
+
+// take records words i to i+n-1 as a match of the given kind, together
+// with a connector in front of them.
+func (p *parser) take(kind Kind, i, n int) int {
+	start := i
+	if kind != KindTag && kind != KindPriority && i > 0 && !p.used[i-1] && connectors[p.norm[i-1]] {
+		start = i - 1
+	}
+	for j := start; j < i+n; j++ {
+		p.used[j] = true
+	}
+	p.res.Matches = append(p.res.Matches, Match{Kind: kind, Text: strings.Join(p.words[start:i+n], " ")})
+	return n
+}
+
+func (p *parser) word(i int) string {
+	if i < len(p.norm) && !p.used[i] {
+		return p.norm[i]
+	}
+	return ""
+}
+
+func (p *parser) tag(i int) int {
+	name := strings.TrimRight(strings.TrimPrefix(p.words[i], "#"), ",.;")
+	if !strings.HasPrefix(p.words[i], "#") || name == "" {
+		return 0
+	}
+	if !slices.Contains(p.res.Tags, name) {
+		p.res.Tags = append(p.res.Tags, name)
+	}
+	return p.take(KindTag, i, 1)
+}
+
+func (p *parser) priority(i int) int {
+	prio, ok := priorities[p.norm[i]]
+	if !ok || p.res.Priority != models.PriorityNone {
+		return 0
+	}
+	p.res.Priority = prio
+	return p.take(KindPriority, i, 1)
+}
+
+var recurrenceWords = map[string]recurrence.Freq{
+	"daily": recurrence.Daily, "everyday": recurrence.Daily,
+	"weekly": recurrence.Weekly, "monthly": recurrence.Monthly,
+	"yearly": recurrence.Yearly, "annually": recurrence.Yearly,
+}
+
+var recurrenceUnits = map[string]recurrence.Freq{
+	"day": recurrence.Daily, "days": recurrence.Daily,
+	"week": recurrence.Weekly, "weeks": recurrence.Weekly,
+	"month": recurrence.Monthly, "months": recurrence.Monthly,
+	"year": recurrence.Yearly, "years": recurrence.Yearly,
+}
+
+// recurrence matches "daily", "every week", "every other month", "every 3
+// days", "every weekday", "every monday and thursday" and "every month on
+// the 1st".
+func (p *parser) recurrence(i int) int {
+	if p.rule != nil {
+		return 0
+	}
+	r := recurrence.Rule{Interval: 1}
+	n := 0
+
+	if freq, ok := recurrenceWords[p.word(i)]; ok {
+		r.Freq, n = freq, 1
+	} else if p.word(i) == "every" {
+		next := p.word(i + 1)
+		switch {
+		case next == "weekday":
+			r.Freq, n = recurrence.Weekly, 2
+			r.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		case next == "weekend":
+			r.Freq, n = recurrence.Weekly, 2
+			r.ByDay = []time.Weekday{time.Saturday, time.Sunday}
+		case next == "other":
+			if freq, ok := recurrenceUnits[p.word(i+2)]; ok {
+				r.Freq, r.Interval, n = freq, 2, 3
+			}
+		case recurrenceUnits[next] != "":
+			r.Freq, n = recurrenceUnits[next], 2
+		default:
+			if count, err := strconv.Atoi(next); err == nil && count > 0 && count < 1000 {
+				if freq, ok := recurrenceUnits[p.word(i+2)]; ok {
+					r.Freq, r.Interval, n = freq, count, 3
+				}
+			} else if days, m := p.weekdayList(i + 1); m > 0 {
+				r.Freq, r.ByDay, n = recurrence.Weekly, days, 1+m
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+
+	// "on the 1st" for monthly rules, "on monday" for weekly ones
+	if p.word(i+n) == "on" {
+		j := i + n + 1
+		if p.word(j) == "the" {
+			j++
+		}
+		if m := dayOfMon.FindStringSubmatch(p.word(j)); m != nil && r.Freq == recurrence.Monthly {
+			if day, _ := strconv.Atoi(m[1]); day >= 1 && day <= 31 {
+				r.ByMonthDay, n = day, j-i+1
+			}
+		} else if days, m := p.weekdayList(i + n + 1); m > 0 && r.Freq == recurrence.Weekly && len(r.ByDay) == 0 {
+			r.ByDay, n = days, n+1+m
+		}
+	}
+
+	p.rule = &r
+	return p.take(KindRecurrence, i, n)
+}
+
+// weekdayList matches weekdays joined by "and" or commas, such as "monday,
+// wednesday and friday".
+func (p *parser) weekdayList(i int) ([]time.Weekday, int) {
+	var days []time.Weekday
+	n := 0
+	for {
+		day, ok := weekdays[p.word(i+n)]
+		if !ok {
+			break
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+		n++
+		if p.word(i+n) != "and" {
+			continue
+		}
+		if _, ok := weekdays[p.word(i+n+1)]; !ok {
+			break
+		}
+		n++
+	}
+	return days, n
+}
+
+// dueDate matches "today", "tonight", "tomorrow", weekdays ("friday", "next
+// friday", "this friday"), "next week", "next month", "in 3 days",
+// "2025-03-14", "march 14th" and "14 march 2026".
+func (p *parser) dueDate(i int) int {
+	if p.date != nil {
+		return 0
+	}
+	w := p.word(i)
+
+	switch w {
+	case "today":
+		return p.setDate(p.today, i, 1)
+	case "tonight":
+		p.tonight = true
+		return p.setDate(p.today, i, 1)
+	case "tomorrow", "tmrw", "tmr":
+		return p.setDate(p.today.AddDate(0, 0, 1), i, 1)
+	case "next":
+		switch next := p.word(i + 1); next {
+		case "week":
+			return p.setDate(p.weekday(time.Monday, false), i, 2)
+		case "month":
+			return p.setDate(time.Date(p.today.Year(), p.today.Month()+1, 1, 0, 0, 0, 0, p.today.Location()), i, 2)
+		default:
+			if day, ok := weekdays[next]; ok {
+				return p.setDate(p.weekday(day, false), i, 2)
+			}
+		}
+		return 0
+	case "this":
+		if day, ok := weekdays[p.word(i+1)]; ok {
+			return p.setDate(p.weekday(day, true), i, 2)
+		}
+		return 0
+	case "in":
+		return p.relative(i)
+	}
+
+	if day, ok := weekdays[w]; ok {
+		return p.setDate(p.weekday(day, false), i, 1)
+	}
+	if d, err := time.ParseInLocation(time.DateOnly, w, p.now.Location()); err == nil {
+		return p.setDate(d, i, 1)
+	}
+
+	// "march 14th [2026]" or "14 march [2026]"
+	var month time.Month
+	var day string
+	if m, ok := months[w]; ok && dayOfMon.MatchString(p.word(i+1)) {
+		month, day = m, p.word(i+1)
+	} else if m, ok := months[p.word(i+1)]; ok && dayOfMon.MatchString(w) {
+		month, day = m, w
+	} else {
+		return 0
+	}
+	dom, _ := strconv.Atoi(dayOfMon.FindStringSubmatch(day)[1])
+	n := 2
+	y := p.today.Year()
+	if year.MatchString(p.word(i + 2)) {
+		y, _ = strconv.Atoi(p.word(i + 2))
+		n = 3
+	}
+	d := time.Date(y, month, dom, 0, 0, 0, 0, p.now.Location())
+	if d.Month() != month || d.Day() != dom {
+		return 0
+	}
+	if n == 2 && d.Before(p.today) {
+		// without a year the date is the next one to come
+		d = d.AddDate(1, 0, 0)
+	}
+	return p.setDate(d, i, n)
+}
+
+// relative matches "in 3 days", "in a week" or "in 2 hours".
+func (p *parser) relative(i int) int {
+	count, err := strconv.Atoi(p.word(i + 1))
+	if w := p.word(i + 1); w == "a" || w == "an" {
+		count, err = 1, nil
+	}
+	if err != nil || count < 0 || count > 1000 {
+		return 0
+	}
+
+	var at time.Time
+	switch strings.TrimSuffix(p.word(i+2), "s") {
+	case "minute", "min":
+		at = p.now.Add(time.Duration(count) * time.Minute)
+	case "hour":
+		at = p.now.Add(time.Duration(count) * time.Hour)
+	case "day":
+		return p.setDate(p.today.AddDate(0, 0, count), i, 3)
+	case "week":
+		return p.setDate(p.today.AddDate(0, 0, 7*count), i, 3)
+	case "month":
+		return p.setDate(addMonths(p.today, count), i, 3)
+	default:
+		return 0
+	}
+	if p.clock != nil {
+		return 0
+	}
+	p.clock = &clock{at.Hour(), at.Minute()}
+	return p.setDate(time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location()), i, 3)
+}
+
+// addMonths adds months to a date, ending on the last day of the month
+// instead of spilling into the next one.
+func addMonths(d time.Time, months int) time.Time {
+	first := time.Date(d.Year(), d.Month()+time.Month(months), 1, 0, 0, 0, 0, d.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d.Day(), last)-1)
+}
+
+func (p *parser) setDate(d time.Time, i, n int) int {
+	p.date = &d
+	return p.take(KindDate, i, n)
+}
+
+// weekday returns the next day that falls on day, today included only when
+// asked for.
+func (p *parser) weekday(day time.Weekday, includeToday bool) time.Time {
+	d := p.today
+	if !includeToday {
+		d = d.AddDate(0, 0, 1)
+	}
+	for d.Weekday() != day {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// timeOfDay matches "5pm", "5:30 pm", "17:00" and "noon".
+func (p *parser) timeOfDay(i int) int {
+	if p.clock != nil {
+		return 0
+	}
+	w := p.word(i)
+	n := 1
+	if next := p.word(i + 1); (next == "am" || next == "pm") && clockNum.MatchString(w) {
+		w, n = w+next, 2
+	}
+
+	var c clock
+	if w == "noon" {
+		c = clock{12, 0}
+	} else if m := clock12.FindStringSubmatch(w); m != nil {
+		c.hour, _ = strconv.Atoi(m[1])
+		c.minute, _ = strconv.Atoi(m[2])
+		if c.hour < 1 || c.hour > 12 {
+			return 0
+		}
+		c.hour %= 12
+		if m[3] == "pm" {
+			c.hour += 12
+		}
+	} else if m := clock24.FindStringSubmatch(w); m != nil {
+		c.hour, _ = strconv.Atoi(m[1])
+		c.minute, _ = strconv.Atoi(m[2])
+		if c.hour > 23 {
+			return 0
+		}
+	} else {
+		return 0
+	}
+	if c.minute > 59 {
+		return 0
+	}
+
+	p.clock = &c
+	return p.take(KindTime, i, n)
+}
+
+// resolveDue combines the recognized date, time and recurrence into the
+// due date. A time alone means its next occurrence; a recurrence without a
+// date starts on its first day from today on. Without a time the todo is
+// due all day.
+func (p *parser) resolveDue() {
+	var c clock
+	timed := true
+	switch {
+	case p.clock != nil:
+		c = *p.clock
+	case p.tonight:
+		c = clock{20, 0}
+	default:
+		timed = false
+	}
+	at := func(d time.Time) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), c.hour, c.minute, 0, 0, d.Location())
+	}
+
+	var due time.Time
+	switch {
+	case p.date != nil:
+		due = at(*p.date)
+	case p.clock != nil || p.rule != nil:
+		due = at(p.today)
+		if timed && due.Before(p.now) {
+			due = at(p.today.AddDate(0, 0, 1))
+		}
+		if p.rule != nil {
+			due = p.rule.First(due)
+		}
+	default:
+		return
+	}
+
+	if !timed {
+		day := models.DateOf(due)
+		p.res.DueOn = &day
+		return
+	}
+	p.res.DueDate = &due
+}
@@ -0,0 +1,148 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Wednesday morning
+	now := time.Date(2025, 1, 29, 10, 0, 0, 0, berlin)
+	at := func(month time.Month, day, hour, minute int) *time.Time {
+		t := time.Date(2025, month, day, hour, minute, 0, 0, berlin)
+		return &t
+	}
+	on := func(year int, month time.Month, day int) *models.Date {
+		return &models.Date{Year: year, Month: month, Day: day}
+	}
+
+	tests := []struct {
+		text       string
+		title      string
+		due        *time.Time
+		dueOn      *models.Date
+		recurrence string
+		tags       []string
+		priority   models.Priority
+	}{
+		{
+			text:       "Pay rent every month on the 1st #home !high",
+			title:      "Pay rent",
+			dueOn:      on(2025, time.February, 1),
+			recurrence: "FREQ=MONTHLY;BYMONTHDAY=1",
+			tags:       []string{"home"},
+			priority:   models.PriorityHigh,
+		},
+		{text: "Buy milk", title: "Buy milk"},
+		{text: "Call mom tomorrow 5pm", title: "Call mom", due: at(time.January, 30, 17, 0)},
+		{text: "Call mom tomorrow at 5:30 pm", title: "Call mom", due: at(time.January, 30, 17, 30)},
+		{text: "Submit report next friday", title: "Submit report", dueOn: on(2025, time.January, 31)},
+		{text: "Team lunch on friday at noon", title: "Team lunch", due: at(time.January, 31, 12, 0)},
+		{text: "Standup this wednesday 9:15am", title: "Standup", due: at(time.January, 29, 9, 15)},
+		{text: "Review PR wednesday", title: "Review PR", dueOn: on(2025, time.February, 5)},
+		{text: "Water plants today", title: "Water plants", dueOn: on(2025, time.January, 29)},
+		{text: "Movie tonight", title: "Movie", due: at(time.January, 29, 20, 0)},
+		{text: "Movie tonight 21:30", title: "Movie", due: at(time.January, 29, 21, 30)},
+		{text: "Stretch at 8am", title: "Stretch", due: at(time.January, 30, 8, 0)},
+		{text: "Stretch at 18:00", title: "Stretch", due: at(time.January, 29, 18, 0)},
+		{text: "Check oven in 2 hours", title: "Check oven", due: at(time.January, 29, 12, 0)},
+		{text: "Renew passport in 3 weeks", title: "Renew passport", dueOn: on(2025, time.February, 19)},
+		{text: "Dentist in a month", title: "Dentist", dueOn: on(2025, time.February, 28)},
+		{text: "Plan sprint next week", title: "Plan sprint", dueOn: on(2025, time.February, 3)},
+		{text: "Pay invoices next month", title: "Pay invoices", dueOn: on(2025, time.February, 1)},
+		{text: "Tax return by 2025-05-31", title: "Tax return", dueOn: on(2025, time.May, 31)},
+		{text: "Mum's birthday march 14th", title: "Mum's birthday", dueOn: on(2025, time.March, 14)},
+		{text: "Anniversary 3 jan", title: "Anniversary", dueOn: on(2026, time.January, 3)},
+		{text: "Conference 14 may 2026 9am", title: "Conference", due: func() *time.Time {
+			t := time.Date(2026, time.May, 14, 9, 0, 0, 0, berlin)
+			return &t
+		}()},
+		{
+			text:       "Take vitamins every day at 9am",
+			title:      "Take vitamins",
+			due:        at(time.January, 30, 9, 0),
+			recurrence: "FREQ=DAILY",
+		},
+		{
+			text:       "Gym every monday and thursday 7am",
+			title:      "Gym",
+			due:        at(time.January, 30, 7, 0),
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		{
+			text:       "Timesheet every weekday at 17:00",
+			title:      "Timesheet",
+			due:        at(time.January, 29, 17, 0),
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		},
+		{
+			text:       "Clean gutters every 3 months",
+			title:      "Clean gutters",
+			dueOn:      on(2025, time.January, 29),
+			recurrence: "FREQ=MONTHLY;INTERVAL=3",
+		},
+		{
+			text:       "Payroll every other week on friday",
+			title:      "Payroll",
+			dueOn:      on(2025, time.January, 31),
+			recurrence: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR",
+		},
+		{
+			text:       "Backup weekly starting next monday",
+			title:      "Backup starting",
+			dueOn:      on(2025, time.February, 3),
+			recurrence: "FREQ=WEEKLY",
+		},
+		{
+			text:     "Fix bug #work #urgent #work !1",
+			title:    "Fix bug",
+			tags:     []string{"work", "urgent"},
+			priority: models.PriorityHigh,
+		},
+		{text: "Read book !low !high", title: "Read book !high", priority: models.PriorityLow},
+		{text: "Buy 5 apples", title: "Buy 5 apples"},
+		{text: "Meet at cafe", title: "Meet at cafe"},
+		{text: "Pay 13:75 fee", title: "Pay 13:75 fee"},
+		{text: "Email the team today, then relax", title: "Email the team then relax", dueOn: on(2025, time.January, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			res, err := Parse(tt.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, res.Title)
+			if tt.due == nil {
+				assert.Nil(t, res.DueDate)
+			} else if assert.NotNil(t, res.DueDate) {
+				assert.True(t, tt.due.Equal(*res.DueDate), "due %s, want %s", res.DueDate, tt.due)
+			}
+			assert.Equal(t, tt.dueOn, res.DueOn)
+			assert.Equal(t, tt.recurrence, res.Recurrence)
+			assert.Equal(t, tt.tags, res.Tags)
+			assert.Equal(t, tt.priority, res.Priority)
+		})
+	}
+}
+
+func TestParseMatches(t *testing.T) {
+	now := time.Date(2025, 1, 29, 10, 0, 0, 0, time.UTC)
+	res, err := Parse("Pay rent every month on the 1st #home !high", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Match{
+		{Kind: KindRecurrence, Text: "every month on the 1st"},
+		{Kind: KindTag, Text: "#home"},
+		{Kind: KindPriority, Text: "!high"},
+	}, res.Matches)
+}
+
+func TestParseNoTitle(t *testing.T) {
+	_, err := Parse("tomorrow at 5pm #home", time.Now())
+	assert.ErrorIs(t, err, ErrNoTitle)
+}
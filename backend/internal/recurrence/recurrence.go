@@ -0,0 +1,131 @@
+// Package recurrence handles the repeat rules of todos. Rules are stored
+// in the iCalendar RRULE syntax (RFC 5545), restricted to what the app
+// can create: a frequency with an optional interval, weekdays and day of
+// the month, e.g. FREQ=MONTHLY;BYMONTHDAY=1.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+	Yearly  Freq = "YEARLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Rule is a parsed repeat rule.
+type Rule struct {
+	Freq     Freq
+	Interval int            // 1 when not set
+	ByDay    []time.Weekday // weekly rules only
+	// ByMonthDay is the day of the month for monthly rules, 0 when not set
+	ByMonthDay int
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,TH".
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = Freq(strings.ToUpper(value))
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 999 {
+				return Rule{}, fmt.Errorf("%w: interval %q", ErrInvalidRule, value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(strings.ToUpper(value), ",") {
+				day := slices.Index(weekdayCodes[:], code)
+				if day < 0 {
+					return Rule{}, fmt.Errorf("%w: weekday %q", ErrInvalidRule, code)
+				}
+				r.ByDay = append(r.ByDay, time.Weekday(day))
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return Rule{}, fmt.Errorf("%w: day of month %q", ErrInvalidRule, value)
+			}
+			r.ByMonthDay = n
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, key)
+		}
+	}
+	return r, r.validate()
+}
+
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	default:
+		return fmt.Errorf("%w: frequency %q", ErrInvalidRule, r.Freq)
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return fmt.Errorf("%w: BYDAY needs FREQ=WEEKLY", ErrInvalidRule)
+	}
+	if r.ByMonthDay != 0 && r.Freq != Monthly {
+		return fmt.Errorf("%w: BYMONTHDAY needs FREQ=MONTHLY", ErrInvalidRule)
+	}
+	return nil
+}
+
+// String formats r as an RRULE value.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			codes = append(codes, weekdayCodes[d])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// First returns the first day on or after day that the rule falls on,
+// keeping the clock time of day. The interval only matters from the second
+// occurrence on.
+func (r Rule) First(day time.Time) time.Time {
+	switch {
+	case len(r.ByDay) > 0:
+		for !slices.Contains(r.ByDay, day.Weekday()) {
+			day = day.AddDate(0, 0, 1)
+		}
+	case r.ByMonthDay != 0:
+		for {
+			y, m, d := day.Date()
+			at := time.Date(y, m, r.ByMonthDay, day.Hour(), day.Minute(), day.Second(), 0, day.Location())
+			// months without the day are skipped
+			if at.Month() == m && r.ByMonthDay >= d {
+				return at
+			}
+			day = time.Date(y, m+1, 1, day.Hour(), day.Minute(), day.Second(), 0, day.Location())
+		}
+	}
+	return day
+}
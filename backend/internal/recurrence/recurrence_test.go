@@ -0,0 +1,66 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"freq=weekly;byday=mo,th", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=15", "FREQ=MONTHLY;BYMONTHDAY=15"},
+		{"FREQ=YEARLY;INTERVAL=2", "FREQ=YEARLY;INTERVAL=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.String())
+		})
+	}
+
+	for _, in := range []string{
+		"", "FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;BYDAY=MO", "FREQ=WEEKLY;BYMONTHDAY=3", "FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3", "FREQ",
+	} {
+		_, err := Parse(in)
+		assert.ErrorIs(t, err, ErrInvalidRule, in)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	// a Wednesday
+	day := time.Date(2025, 1, 29, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want time.Time
+	}{
+		{"FREQ=DAILY", day},
+		{"FREQ=WEEKLY;BYDAY=WE", day},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYMONTHDAY=29", day},
+		{"FREQ=MONTHLY;BYMONTHDAY=1", time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)},
+		// February has no 30th
+		{"FREQ=MONTHLY;BYMONTHDAY=30", time.Date(2025, 1, 30, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.First(day))
+		})
+	}
+
+	r, err := Parse("FREQ=MONTHLY;BYMONTHDAY=30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 30, 9, 0, 0, 0, time.UTC), r.First(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)))
+}
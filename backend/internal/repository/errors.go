@@ -4,3 +4,21 @@ import "fmt"
 
 var ErrTodoNotFound = fmt.Errorf("todo entry not found")
 var ErrListNotFound = fmt.Errorf("list entry not found")
+var ErrUserNotFound = fmt.Errorf("user entry not found")
+var ErrFeedTokenNotFound = fmt.Errorf("feed token not found")
+var ErrImportJobNotFound = fmt.Errorf("import job not found")
+var ErrIDConflict = fmt.Errorf("id already in use")
+var ErrAttachmentNotFound = fmt.Errorf("attachment not found")
+var ErrCommentNotFound = fmt.Errorf("comment not found")
+var ErrListMemberNotFound = fmt.Errorf("list member not found")
+var ErrViewNotFound = fmt.Errorf("view not found")
+var ErrViewNameTaken = fmt.Errorf("a view with this name already exists")
+var ErrColumnNotFound = fmt.Errorf("board column not found")
+var ErrColumnNameTaken = fmt.Errorf("a column with this name already exists in the list")
+var ErrWorkspaceNotFound = fmt.Errorf("workspace not found")
+var ErrWorkspaceMemberNotFound = fmt.Errorf("workspace member not found")
+var ErrFolderNotFound = fmt.Errorf("folder not found")
+var ErrFolderNameTaken = fmt.Errorf("a folder with this name already exists in the workspace")
+var ErrTemplateNotFound = fmt.Errorf("list template not found")
+var ErrTemplateNameTaken = fmt.Errorf("a template with this name already exists")
+var ErrCalendarObjectNameTaken = fmt.Errorf("a calendar object with this name already exists in the list")
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// ListAgendaTodos returns the open todos with a due or scheduled date in
+// the lists a user can access, leaving out those that start after today.
+func (r *todoRepo) ListAgendaTodos(ctx context.Context, userID uuid.UUID, today models.Date) ([]*models.Todo, error) {
+	todos := make([]*models.Todo, 0)
+	query := `
+		SELECT` + todoColumns + `, ` + listZone + ` AS list_time_zone
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE ` + listAccess + `
+			AND NOT t.status
+			AND (t.due_date IS NOT NULL OR t.due_on IS NOT NULL OR t.scheduled_for IS NOT NULL)
+			AND (t.start_date IS NULL OR t.start_date <= $2)
+		ORDER BY ` + todoDue + ` NULLS LAST, t.created_at, t.id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, userID, today); err != nil {
+		return nil, fmt.Errorf("failed to list agenda todos: %w", err)
+	}
+
+	return todos, nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// SetListArchived archives or unarchives a list. Archiving an archived
+// list keeps its original archive time.
+func (r *todoRepo) SetListArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	query := `
+		UPDATE todo_lists
+		SET archived_at = CASE WHEN $1 THEN COALESCE(archived_at, NOW()) END
+		WHERE id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, archived, id)
+	if err != nil {
+		return fmt.Errorf("failed to archive list: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrListNotFound
+	}
+
+	return nil
+}
+
+// SetListAutoArchive sets after how many days completed todos of a list
+// are archived, never when days is nil.
+func (r *todoRepo) SetListAutoArchive(ctx context.Context, id uuid.UUID, days *int) error {
+	query := `
+		UPDATE todo_lists
+		SET auto_archive_days = $1
+		WHERE id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, days, id)
+	if err != nil {
+		return fmt.Errorf("failed to set auto-archive policy: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrListNotFound
+	}
+
+	return nil
+}
+
+// ArchiveCompletedTodos archives the todos that were completed longer ago
+// than the auto-archive policy of their list allows, and returns how many
+// it archived.
+func (r *todoRepo) ArchiveCompletedTodos(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE todos t
+		SET archived_at = NOW()
+		FROM todo_lists l
+		WHERE l.id = t.list_id AND l.auto_archive_days IS NOT NULL
+			AND t.status AND t.archived_at IS NULL
+			AND t.completed_at < NOW() - make_interval(days => l.auto_archive_days)`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive completed todos: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive completed todos: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// AddTodoAssignee assigns a todo to a user and reports whether it was not
+// assigned to them before.
+func (r *todoRepo) AddTodoAssignee(ctx context.Context, todoID, userID uuid.UUID, assignedBy *uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO todo_assignees (todo_id, user_id, assigned_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, userID, assignedBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to add todo assignee: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add todo assignee: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// RemoveTodoAssignee unassigns a user from a todo and reports whether the
+// todo was assigned to them.
+func (r *todoRepo) RemoveTodoAssignee(ctx context.Context, todoID, userID uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM todo_assignees
+		WHERE todo_id = $1 AND user_id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove todo assignee: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove todo assignee: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// RemoveListAssignees unassigns a user from every todo of a list and
+// returns the IDs of those todos.
+func (r *todoRepo) RemoveListAssignees(ctx context.Context, listID, userID uuid.UUID) ([]uuid.UUID, error) {
+	todoIDs := make([]uuid.UUID, 0)
+	query := `
+		DELETE FROM todo_assignees a
+		USING todos t
+		WHERE t.id = a.todo_id AND t.list_id = $1 AND a.user_id = $2
+		RETURNING a.todo_id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &todoIDs, query, listID, userID); err != nil {
+		return nil, fmt.Errorf("failed to remove list assignees: %w", err)
+	}
+
+	return todoIDs, nil
+}
+
+// ListAssignedTodos returns the todos assigned to a user in the lists they
+// can access, those due first at the top.
+func (r *todoRepo) ListAssignedTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error) {
+	todos := make([]*models.Todo, 0)
+	query := `
+		SELECT` + todoColumns + `
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		JOIN todo_assignees a ON a.todo_id = t.id AND a.user_id = $1
+		WHERE ` + listAccess + `
+		ORDER BY ` + todoDue + ` NULLS LAST, t.created_at, t.id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list assigned todos: %w", err)
+	}
+
+	return todos, nil
+}
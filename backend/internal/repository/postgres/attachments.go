@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+const attachmentColumns = `
+	a.id, a.todo_id, a.filename, a.content_type, a.size, a.sha256, a.storage_key, a.created_at`
+
+// CreateAttachment stores an attachment, assigning a new ID when it has
+// none. A zero CreatedAt is set from the database.
+func (r *todoRepo) CreateAttachment(ctx context.Context, a *models.Attachment) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO attachments (id, todo_id, filename, content_type, size, sha256, storage_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()))
+		RETURNING created_at`
+
+	if err := r.conn(ctx).GetContext(
+		ctx,
+		&a.CreatedAt,
+		query,
+		a.ID,
+		a.TodoID,
+		a.Filename,
+		a.ContentType,
+		a.Size,
+		a.SHA256,
+		a.StorageKey,
+		timestamp(a.CreatedAt),
+	); err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetAttachment(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, error) {
+	a := &models.Attachment{}
+	query := `
+		SELECT` + attachmentColumns + `
+		FROM attachments a
+		WHERE a.todo_id = $1 AND a.id = $2`
+
+	if err := r.conn(ctx).GetContext(ctx, a, query, todoID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return a, nil
+}
+
+func (r *todoRepo) ListAttachments(ctx context.Context, todoID uuid.UUID) ([]*models.Attachment, error) {
+	attachments := make([]*models.Attachment, 0)
+	query := `
+		SELECT` + attachmentColumns + `
+		FROM attachments a
+		WHERE a.todo_id = $1
+		ORDER BY a.created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &attachments, query, todoID); err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// ListUserAttachments returns the attachments of the todos in the lists a
+// user owns that are not in a deleted workspace.
+func (r *todoRepo) ListUserAttachments(ctx context.Context, userID uuid.UUID) ([]*models.Attachment, error) {
+	attachments := make([]*models.Attachment, 0)
+	query := `
+		SELECT` + attachmentColumns + `
+		FROM attachments a
+		JOIN todos t ON t.id = a.todo_id
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE l.owner_id = $1 AND ` + liveList + `
+		ORDER BY a.created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &attachments, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list user attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *todoRepo) DeleteAttachment(ctx context.Context, todoID, id uuid.UUID) error {
+	query := `
+		DELETE FROM attachments
+		WHERE todo_id = $1 AND id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// ListBlobDeletions returns the oldest queued blob deletions. Queue entries
+// whose blob is still used by another attachment, as happens after a
+// restore with new IDs, are dropped instead.
+func (r *todoRepo) ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error) {
+	query := `
+		DELETE FROM blob_deletions d
+		WHERE EXISTS (SELECT 1 FROM attachments a WHERE a.storage_key = d.storage_key)`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query); err != nil {
+		return nil, fmt.Errorf("failed to drop blob deletions in use: %w", err)
+	}
+
+	deletions := make([]*models.BlobDeletion, 0)
+	query = `
+		SELECT id, storage_key
+		FROM blob_deletions
+		ORDER BY id
+		LIMIT $1`
+
+	if err := r.conn(ctx).SelectContext(ctx, &deletions, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to list blob deletions: %w", err)
+	}
+
+	return deletions, nil
+}
+
+func (r *todoRepo) DeleteBlobDeletion(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM blob_deletions
+		WHERE id = $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete blob deletion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// CreateBoardColumn adds a column after the existing columns of its list.
+func (r *todoRepo) CreateBoardColumn(ctx context.Context, c *models.BoardColumn) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO board_columns (id, list_id, name, wip_limit, position)
+		VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM board_columns WHERE list_id = $2))
+		RETURNING position, created_at`
+
+	if err := r.conn(ctx).QueryRowContext(
+		ctx, query, c.ID, c.ListID, c.Name, c.WIPLimit,
+	).Scan(&c.Position, &c.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrColumnNameTaken
+		}
+		return fmt.Errorf("failed to create board column: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetBoardColumn(ctx context.Context, id uuid.UUID) (*models.BoardColumn, error) {
+	return r.getBoardColumn(ctx, id, "")
+}
+
+// LockBoardColumn returns a column and locks it until the end of the
+// transaction, so that moves into it are checked against its WIP limit one
+// at a time.
+func (r *todoRepo) LockBoardColumn(ctx context.Context, id uuid.UUID) (*models.BoardColumn, error) {
+	return r.getBoardColumn(ctx, id, "FOR UPDATE")
+}
+
+func (r *todoRepo) getBoardColumn(ctx context.Context, id uuid.UUID, lock string) (*models.BoardColumn, error) {
+	c := &models.BoardColumn{}
+	query := `
+		SELECT id, list_id, name, position, wip_limit, created_at
+		FROM board_columns
+		WHERE id = $1
+		` + lock
+
+	if err := r.conn(ctx).GetContext(ctx, c, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrColumnNotFound
+		}
+		return nil, fmt.Errorf("failed to get board column: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *todoRepo) ListBoardColumns(ctx context.Context, listID uuid.UUID) ([]*models.BoardColumn, error) {
+	columns := make([]*models.BoardColumn, 0)
+	query := `
+		SELECT id, list_id, name, position, wip_limit, created_at
+		FROM board_columns
+		WHERE list_id = $1
+		ORDER BY position, created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &columns, query, listID); err != nil {
+		return nil, fmt.Errorf("failed to list board columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+func (r *todoRepo) UpdateBoardColumn(ctx context.Context, c *models.BoardColumn) error {
+	query := `
+		UPDATE board_columns
+		SET name = $1, wip_limit = $2
+		WHERE id = $3`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, c.Name, c.WIPLimit, c.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrColumnNameTaken
+		}
+		return fmt.Errorf("failed to update board column: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update board column: %w", err)
+	}
+	if n == 0 {
+		return repository.ErrColumnNotFound
+	}
+
+	return nil
+}
+
+// SetBoardColumnPositions numbers the given columns of a list in order.
+func (r *todoRepo) SetBoardColumnPositions(ctx context.Context, listID uuid.UUID, ids []uuid.UUID) error {
+	query := `
+		UPDATE board_columns c
+		SET position = o.n - 1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, n)
+		WHERE c.id = o.id AND c.list_id = $1 AND c.position <> o.n - 1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, listID, uuidArray(ids)); err != nil {
+		return fmt.Errorf("failed to reorder board columns: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBoardColumn removes a column; its todos are taken off the board.
+func (r *todoRepo) DeleteBoardColumn(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM board_columns
+		WHERE id = $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete board column: %w", err)
+	}
+
+	return nil
+}
+
+// ListColumnTodoIDs returns the todos of a list in a column, or those in
+// no column when columnID is nil, in rank order.
+func (r *todoRepo) ListColumnTodoIDs(ctx context.Context, listID uuid.UUID, columnID *uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	query := `
+		SELECT id
+		FROM todos
+		WHERE list_id = $1 AND column_id IS NOT DISTINCT FROM $2
+		ORDER BY rank, created_at, id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &ids, query, listID, columnID); err != nil {
+		return nil, fmt.Errorf("failed to list column todos: %w", err)
+	}
+
+	return ids, nil
+}
+
+// PlaceTodos puts the given todos into a column, or into none when
+// columnID is nil, ranked in order.
+func (r *todoRepo) PlaceTodos(ctx context.Context, columnID *uuid.UUID, ids []uuid.UUID) error {
+	query := `
+		UPDATE todos t
+		SET column_id = $1, rank = o.n - 1
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, n)
+		WHERE t.id = o.id AND (t.column_id IS DISTINCT FROM $1 OR t.rank <> o.n - 1)`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, columnID, uuidArray(ids)); err != nil {
+		return fmt.Errorf("failed to place todos: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// Todos that were never written through CalDAV, or were moved out of the
+// list their client created them in, are exposed as <id>.ics with the UID
+// <id>@to-do-app.
+const calendarObjectColumns = todoColumns + `,
+	COALESCE(r.name, t.id::text || '.ics') AS dav_name,
+	COALESCE(r.uid, t.id::text || '@to-do-app') AS dav_uid`
+
+func (r *todoRepo) ListOwnedLists(ctx context.Context, ownerID uuid.UUID) ([]*models.TodoList, error) {
+	lists := make([]*models.TodoList, 0)
+	query := `
+		SELECT ` + listColumns + `
+		FROM todo_lists l
+		WHERE l.owner_id = $1 AND ` + liveList + `
+		ORDER BY l.created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &lists, query, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to list owned lists: %w", err)
+	}
+
+	return lists, nil
+}
+
+func (r *todoRepo) ListCalendarObjects(ctx context.Context, listID uuid.UUID) ([]*models.CalendarObject, error) {
+	var objects []*models.CalendarObject
+	query := `
+		SELECT` + calendarObjectColumns + `
+		FROM todos t
+		LEFT JOIN caldav_resources r ON r.todo_id = t.id AND r.list_id = t.list_id
+		WHERE t.list_id = $1`
+
+	if err := r.conn(ctx).SelectContext(ctx, &objects, query, listID); err != nil {
+		return nil, fmt.Errorf("failed to list calendar objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+func (r *todoRepo) GetCalendarObject(
+	ctx context.Context, listID uuid.UUID, name string,
+) (*models.CalendarObject, error) {
+	object := &models.CalendarObject{}
+	query := `
+		SELECT` + calendarObjectColumns + `
+		FROM todos t
+		LEFT JOIN caldav_resources r ON r.todo_id = t.id AND r.list_id = t.list_id
+		WHERE t.list_id = $1 AND COALESCE(r.name, t.id::text || '.ics') = $2`
+
+	if err := r.conn(ctx).GetContext(ctx, object, query, listID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrTodoNotFound
+		}
+		return nil, fmt.Errorf("failed to get calendar object: %w", err)
+	}
+
+	return object, nil
+}
+
+// SaveCalendarObject names a todo in a list. The name of a deleted todo,
+// kept for sync reports, is taken over.
+func (r *todoRepo) SaveCalendarObject(ctx context.Context, listID, todoID uuid.UUID, name, uid string) error {
+	release := `
+		DELETE FROM caldav_resources r
+		WHERE r.list_id = $1 AND r.name = $2 AND r.todo_id <> $3
+			AND NOT EXISTS (SELECT 1 FROM todos t WHERE t.id = r.todo_id AND t.list_id = r.list_id)`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, release, listID, name, todoID); err != nil {
+		return fmt.Errorf("failed to release calendar object name: %w", err)
+	}
+
+	query := `
+		INSERT INTO caldav_resources (list_id, todo_id, name, uid)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (todo_id) DO UPDATE SET list_id = EXCLUDED.list_id, name = EXCLUDED.name, uid = EXCLUDED.uid`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, listID, todoID, name, uid); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrCalendarObjectNameTaken
+		}
+		return fmt.Errorf("failed to save calendar object: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetCalendarSyncToken(ctx context.Context, listID uuid.UUID) (int64, error) {
+	var seq int64
+	query := `
+		SELECT COALESCE(MAX(seq), 0)
+		FROM todo_changes
+		WHERE list_id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, &seq, query, listID); err != nil {
+		return 0, fmt.Errorf("failed to get calendar sync token: %w", err)
+	}
+
+	return seq, nil
+}
+
+func (r *todoRepo) ListCalendarChanges(
+	ctx context.Context, listID uuid.UUID, since int64,
+) ([]*models.CalendarChange, error) {
+	var changes []*models.CalendarChange
+	query := `
+		SELECT DISTINCT ON (c.todo_id)
+			c.todo_id, c.deleted, COALESCE(r.name, c.todo_id::text || '.ics') AS dav_name
+		FROM todo_changes c
+		LEFT JOIN caldav_resources r ON r.todo_id = c.todo_id AND r.list_id = c.list_id
+		WHERE c.list_id = $1 AND c.seq > $2
+		ORDER BY c.todo_id, c.seq DESC`
+
+	if err := r.conn(ctx).SelectContext(ctx, &changes, query, listID, since); err != nil {
+		return nil, fmt.Errorf("failed to list calendar changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+const commentColumns = `
+	c.id, c.todo_id, c.parent_id, c.author_id, c.body, c.created_at, c.edited_at, c.deleted_at,
+	ARRAY(
+		SELECT u.username
+		FROM comment_mentions m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.comment_id = c.id
+		ORDER BY u.username
+	) AS mentions`
+
+func (r *todoRepo) CreateComment(ctx context.Context, c *models.Comment) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO comments (id, todo_id, parent_id, author_id, body)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	if err := r.conn(ctx).GetContext(
+		ctx, &c.CreatedAt, query, c.ID, c.TodoID, c.ParentID, c.AuthorID, c.Body,
+	); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetComment(ctx context.Context, todoID, id uuid.UUID) (*models.Comment, error) {
+	c := &models.Comment{}
+	query := `
+		SELECT` + commentColumns + `
+		FROM comments c
+		WHERE c.todo_id = $1 AND c.id = $2`
+
+	if err := r.conn(ctx).GetContext(ctx, c, query, todoID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListComments returns all comments of a todo, oldest first.
+func (r *todoRepo) ListComments(ctx context.Context, todoID uuid.UUID) ([]*models.Comment, error) {
+	comments := make([]*models.Comment, 0)
+	query := `
+		SELECT` + commentColumns + `
+		FROM comments c
+		WHERE c.todo_id = $1
+		ORDER BY c.created_at, c.id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &comments, query, todoID); err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// UpdateCommentBody replaces the body of a comment, keeping the previous
+// one as a revision.
+func (r *todoRepo) UpdateCommentBody(ctx context.Context, c *models.Comment, body string) error {
+	writtenAt := c.CreatedAt
+	if c.EditedAt != nil {
+		writtenAt = *c.EditedAt
+	}
+	query := `
+		INSERT INTO comment_revisions (comment_id, body, written_at)
+		VALUES ($1, $2, $3)`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, c.ID, c.Body, writtenAt); err != nil {
+		return fmt.Errorf("failed to save comment revision: %w", err)
+	}
+
+	query = `
+		UPDATE comments
+		SET body = $1, edited_at = NOW()
+		WHERE id = $2
+		RETURNING edited_at`
+
+	var editedAt time.Time
+	if err := r.conn(ctx).GetContext(ctx, &editedAt, query, body, c.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrCommentNotFound
+		}
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	c.Body = body
+	c.EditedAt = &editedAt
+
+	return nil
+}
+
+// DeleteComment empties a comment and drops its revisions and mentions.
+// The row stays so that replies keep their parent.
+func (r *todoRepo) DeleteComment(ctx context.Context, todoID, id uuid.UUID) error {
+	query := `
+		UPDATE comments
+		SET body = '', deleted_at = NOW()
+		WHERE todo_id = $1 AND id = $2 AND deleted_at IS NULL`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrCommentNotFound
+	}
+
+	for _, query := range []string{
+		`DELETE FROM comment_revisions WHERE comment_id = $1`,
+		`DELETE FROM comment_mentions WHERE comment_id = $1`,
+	} {
+		if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *todoRepo) ListCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]*models.CommentRevision, error) {
+	revisions := make([]*models.CommentRevision, 0)
+	query := `
+		SELECT id, comment_id, body, written_at, replaced_at
+		FROM comment_revisions
+		WHERE comment_id = $1
+		ORDER BY id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &revisions, query, commentID); err != nil {
+		return nil, fmt.Errorf("failed to list comment revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// SetCommentMentions replaces the users mentioned by a comment and returns
+// those that were not mentioned before.
+func (r *todoRepo) SetCommentMentions(
+	ctx context.Context, commentID uuid.UUID, userIDs []uuid.UUID,
+) ([]uuid.UUID, error) {
+	query := `
+		DELETE FROM comment_mentions
+		WHERE comment_id = $1 AND NOT (user_id = ANY($2::uuid[]))`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, commentID, uuidArray(userIDs)); err != nil {
+		return nil, fmt.Errorf("failed to remove comment mentions: %w", err)
+	}
+
+	added := make([]uuid.UUID, 0)
+	query = `
+		INSERT INTO comment_mentions (comment_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+		RETURNING user_id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &added, query, commentID, uuidArray(userIDs)); err != nil {
+		return nil, fmt.Errorf("failed to add comment mentions: %w", err)
+	}
+
+	return added, nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// LockTodoDependencies takes a lock held until the end of the transaction
+// that serializes changes to dependencies. Dependencies cross lists, so
+// checking that a new one closes no cycle needs the whole graph to stay
+// put.
+func (r *todoRepo) LockTodoDependencies(ctx context.Context) error {
+	query := `SELECT pg_advisory_xact_lock(hashtext('todo_dependencies'))`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to lock todo dependencies: %w", err)
+	}
+
+	return nil
+}
+
+// AddTodoDependency makes a todo wait for its blocker and reports whether
+// it did not before.
+func (r *todoRepo) AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO todo_dependencies (todo_id, blocked_by)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, blockedBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to add todo dependency: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add todo dependency: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// RemoveTodoDependency stops a todo from waiting for its blocker and
+// reports whether it did.
+func (r *todoRepo) RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM todo_dependencies
+		WHERE todo_id = $1 AND blocked_by = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, todoID, blockedBy)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove todo dependency: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove todo dependency: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// ListBlockers returns the dependencies of the given todos on their
+// blockers.
+func (r *todoRepo) ListBlockers(ctx context.Context, todoIDs []uuid.UUID) ([]models.Dependency, error) {
+	deps := make([]models.Dependency, 0)
+	if len(todoIDs) == 0 {
+		return deps, nil
+	}
+	query := `
+		SELECT todo_id, blocked_by, created_at
+		FROM todo_dependencies
+		WHERE todo_id = ANY($1::uuid[])
+		ORDER BY created_at, blocked_by`
+
+	if err := r.conn(ctx).SelectContext(ctx, &deps, query, uuidArray(todoIDs)); err != nil {
+		return nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+
+	return deps, nil
+}
+
+// ListTodoDependencies returns the todos a todo waits for and those
+// waiting for it.
+func (r *todoRepo) ListTodoDependencies(ctx context.Context, todoID uuid.UUID) (*models.TodoDependencies, error) {
+	deps := &models.TodoDependencies{BlockedBy: make([]*models.Todo, 0), Blocks: make([]*models.Todo, 0)}
+	blockedBy := `
+		SELECT` + todoColumns + `
+		FROM todo_dependencies d
+		JOIN todos t ON t.id = d.blocked_by
+		WHERE d.todo_id = $1
+		ORDER BY d.created_at, t.id`
+	blocks := `
+		SELECT` + todoColumns + `
+		FROM todo_dependencies d
+		JOIN todos t ON t.id = d.todo_id
+		WHERE d.blocked_by = $1
+		ORDER BY d.created_at, t.id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &deps.BlockedBy, blockedBy, todoID); err != nil {
+		return nil, fmt.Errorf("failed to list todo dependencies: %w", err)
+	}
+	if err := r.conn(ctx).SelectContext(ctx, &deps.Blocks, blocks, todoID); err != nil {
+		return nil, fmt.Errorf("failed to list todo dependencies: %w", err)
+	}
+
+	return deps, nil
+}
+
+// GetDependencyGraph returns the todos of a list with the dependencies that
+// involve them, including the todos of other lists on either end.
+func (r *todoRepo) GetDependencyGraph(ctx context.Context, listID uuid.UUID) (*models.DependencyGraph, error) {
+	graph := &models.DependencyGraph{Todos: make([]*models.Todo, 0), Dependencies: make([]models.Dependency, 0)}
+	deps := `
+		SELECT d.todo_id, d.blocked_by, d.created_at
+		FROM todo_dependencies d
+		JOIN todos a ON a.id = d.todo_id
+		JOIN todos b ON b.id = d.blocked_by
+		WHERE a.list_id = $1 OR b.list_id = $1
+		ORDER BY d.created_at, d.todo_id, d.blocked_by`
+	todos := `
+		SELECT` + todoColumns + `
+		FROM todos t
+		WHERE t.list_id = $1
+			OR t.id IN (
+				SELECT d.blocked_by FROM todo_dependencies d JOIN todos a ON a.id = d.todo_id WHERE a.list_id = $1
+				UNION
+				SELECT d.todo_id FROM todo_dependencies d JOIN todos b ON b.id = d.blocked_by WHERE b.list_id = $1
+			)
+		ORDER BY t.created_at, t.id`
+
+	if err := r.conn(ctx).SelectContext(ctx, &graph.Dependencies, deps, listID); err != nil {
+		return nil, fmt.Errorf("failed to get dependency graph: %w", err)
+	}
+	if err := r.conn(ctx).SelectContext(ctx, &graph.Todos, todos, listID); err != nil {
+		return nil, fmt.Errorf("failed to get dependency graph: %w", err)
+	}
+
+	return graph, nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (r *todoRepo) CreateEvent(ctx context.Context, e *models.Event) error {
+	query := `
+		INSERT INTO events (type, user_id, actor_id, todo_id, comment_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	if err := r.conn(ctx).QueryRowContext(
+		ctx, query, e.Type, e.UserID, e.ActorID, e.TodoID, e.CommentID,
+	).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimEvents marks up to limit pending events as processed and returns
+// them, oldest first. Concurrent consumers skip events claimed by others.
+func (r *todoRepo) ClaimEvents(ctx context.Context, limit int) ([]*models.Event, error) {
+	events := make([]*models.Event, 0)
+	query := `
+		UPDATE events
+		SET processed_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM events
+			WHERE processed_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, user_id, actor_id, todo_id, comment_id, created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &events, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery
+	slices.SortFunc(events, func(a, b *models.Event) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+func (r *todoRepo) CreateFeedToken(
+	ctx context.Context, userID, listID *uuid.UUID, tokenHash []byte,
+) (*models.FeedToken, error) {
+	token := &models.FeedToken{
+		ID:     uuid.New(),
+		UserID: userID,
+		ListID: listID,
+	}
+	query := `
+		INSERT INTO feed_tokens (id, token_hash, user_id, list_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	if err := r.conn(ctx).GetContext(
+		ctx, &token.CreatedAt, query, token.ID, tokenHash, token.UserID, token.ListID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to create feed token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *todoRepo) GetFeedTokenByHash(ctx context.Context, tokenHash []byte) (*models.FeedToken, error) {
+	token := &models.FeedToken{}
+	query := `
+		SELECT id, user_id, list_id, created_at, revoked_at
+		FROM feed_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL`
+
+	if err := r.conn(ctx).GetContext(ctx, token, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrFeedTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *todoRepo) ListFeedTokens(ctx context.Context, userID, listID *uuid.UUID) ([]*models.FeedToken, error) {
+	tokens := make([]*models.FeedToken, 0)
+	query := `
+		SELECT id, user_id, list_id, created_at, revoked_at
+		FROM feed_tokens
+		WHERE user_id IS NOT DISTINCT FROM $1 AND list_id IS NOT DISTINCT FROM $2
+		ORDER BY created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &tokens, query, userID, listID); err != nil {
+		return nil, fmt.Errorf("failed to list feed tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *todoRepo) RevokeFeedToken(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE feed_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke feed token: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrFeedTokenNotFound
+	}
+
+	return nil
+}
+
+// ListUserTodos returns the todos in the lists of a user that are not
+// archived, like the list feed does.
+func (r *todoRepo) ListUserTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	query := `
+		SELECT` + todoColumns + `
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE l.owner_id = $1 AND t.archived_at IS NULL AND ` + liveList
+
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list user todos: %w", err)
+	}
+
+	return todos, nil
+}
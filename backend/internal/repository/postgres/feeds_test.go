@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingConnector opens connections that record the queries they run
+// and return no rows.
+type recordingConnector struct {
+	queries []string
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn{c}, nil
+}
+func (c *recordingConnector) Driver() driver.Driver { return nil }
+
+type recordingConn struct{ c *recordingConnector }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	c.c.queries = append(c.c.queries, query)
+	return recordingStmt{}, nil
+}
+func (recordingConn) Close() error              { return nil }
+func (recordingConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type recordingStmt struct{}
+
+func (recordingStmt) Close() error                               { return nil }
+func (recordingStmt) NumInput() int                              { return -1 }
+func (recordingStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (recordingStmt) Query([]driver.Value) (driver.Rows, error)  { return emptyRows{}, nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func TestListUserTodosLeavesOutArchivedTodos(t *testing.T) {
+	connector := &recordingConnector{}
+	repo := &todoRepo{db: sqlx.NewDb(sql.OpenDB(connector), "postgres")}
+
+	_, err := repo.ListUserTodos(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.Len(t, connector.queries, 1)
+	assert.Contains(t, connector.queries[0], "t.archived_at IS NULL")
+}
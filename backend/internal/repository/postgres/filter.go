@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/filter"
+)
+
+var filterColumns = map[filter.Field]string{
+	filter.FieldDue:     todoDue,
+	filter.FieldCreated: "t.created_at",
+	filter.FieldUpdated: "t.updated_at",
+}
+
+var filterOps = map[filter.Op]string{
+	filter.OpLess:         "<",
+	filter.OpLessEqual:    "<=",
+	filter.OpGreater:      ">",
+	filter.OpGreaterEqual: ">=",
+	filter.OpEqual:        "=",
+	filter.OpNotEqual:     "<>",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// filterQuery compiles a filter query into a condition on todos aliased as
+// t in lists aliased as l. Every value becomes a parameter, numbered after
+// those already in args.
+type filterQuery struct {
+	args   []any
+	now    time.Time
+	userID uuid.UUID
+}
+
+func (q *filterQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+func (q *filterQuery) compile(n filter.Node) (string, error) {
+	switch n := n.(type) {
+	case filter.And:
+		return q.binary(n.Left, "AND", n.Right)
+	case filter.Or:
+		return q.binary(n.Left, "OR", n.Right)
+	case filter.Not:
+		cond, err := q.compile(n.Expr)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + cond, nil
+	case filter.Text:
+		p := q.arg("%" + likeEscaper.Replace(n.Value) + "%")
+		return "(t.title ILIKE " + p + " OR COALESCE(t.description, '') ILIKE " + p + ")", nil
+	case filter.Match:
+		return q.match(n)
+	case filter.Compare:
+		column, ok := filterColumns[n.Field]
+		op, known := filterOps[n.Op]
+		if !ok || !known {
+			return "", fmt.Errorf("cannot compile %s", n)
+		}
+		// missing dates never match, not even when negated
+		return "COALESCE(" + column + " " + op + " " + q.arg(n.Value.Resolve(q.now)) + ", FALSE)", nil
+	}
+	return "", fmt.Errorf("cannot compile %T", n)
+}
+
+func (q *filterQuery) binary(left filter.Node, op string, right filter.Node) (string, error) {
+	l, err := q.compile(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := q.compile(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+func (q *filterQuery) match(m filter.Match) (string, error) {
+	switch m.Field {
+	case filter.FieldTag:
+		return q.arg(m.Value) + " = ANY(t.tags)", nil
+	case filter.FieldStatus:
+		if m.Value == "done" {
+			return "t.status", nil
+		}
+		return "NOT t.status", nil
+	case filter.FieldList:
+		if id, err := uuid.Parse(m.Value); err == nil {
+			return "t.list_id = " + q.arg(id), nil
+		}
+		return "lower(l.name) = lower(" + q.arg(m.Value) + ")", nil
+	case filter.FieldAssignee:
+		if strings.EqualFold(m.Value, "me") {
+			return "EXISTS (SELECT 1 FROM todo_assignees a WHERE a.todo_id = t.id AND a.user_id = " +
+				q.arg(q.userID) + ")", nil
+		}
+		return "EXISTS (SELECT 1 FROM todo_assignees a JOIN users u ON u.id = a.user_id " +
+			"WHERE a.todo_id = t.id AND lower(u.username) = lower(" + q.arg(m.Value) + "))", nil
+	case filter.FieldHas:
+		switch m.Value {
+		case "due":
+			return "(t.due_date IS NOT NULL OR t.due_on IS NOT NULL)", nil
+		case "tags":
+			return "cardinality(t.tags) > 0", nil
+		case "assignee":
+			return "EXISTS (SELECT 1 FROM todo_assignees a WHERE a.todo_id = t.id)", nil
+		}
+	}
+	return "", fmt.Errorf("cannot compile %s", m)
+}
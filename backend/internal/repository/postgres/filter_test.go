@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/filter"
+)
+
+func TestFilterQueryCompile(t *testing.T) {
+	userID := uuid.New()
+	listID := uuid.New()
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		query    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			"due < now+7d and tag:work and status:open",
+			"((COALESCE(" + todoDue + " < $2, FALSE) AND $3 = ANY(t.tags)) AND NOT t.status)",
+			[]any{now.AddDate(0, 0, 7), "work"},
+		},
+		{
+			"not (has:due or status:done)",
+			"NOT ((t.due_date IS NOT NULL OR t.due_on IS NOT NULL) OR t.status)",
+			nil,
+		},
+		{
+			`"50% off" list:` + listID.String(),
+			"((t.title ILIKE $2 OR COALESCE(t.description, '') ILIKE $2) AND t.list_id = $3)",
+			[]any{`%50\% off%`, listID},
+		},
+		{
+			"list:Home assignee:me assignee:Ada",
+			"((lower(l.name) = lower($2) AND " +
+				"EXISTS (SELECT 1 FROM todo_assignees a WHERE a.todo_id = t.id AND a.user_id = $3)) AND " +
+				"EXISTS (SELECT 1 FROM todo_assignees a JOIN users u ON u.id = a.user_id " +
+				"WHERE a.todo_id = t.id AND lower(u.username) = lower($4)))",
+			[]any{"Home", userID, "Ada"},
+		},
+		{
+			"updated != today",
+			"COALESCE(t.updated_at <> $2, FALSE)",
+			[]any{time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			n, err := filter.Parse(tt.query)
+			require.NoError(t, err)
+
+			q := &filterQuery{args: []any{userID}, now: now, userID: userID}
+			sql, err := q.compile(n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSQL, sql)
+			assert.Equal(t, append([]any{userID}, tt.wantArgs...), q.args)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// ListUserTodoChanges returns the change log of the lists owned by a user
+// that are not in a deleted workspace, in the order it was written.
+func (r *todoRepo) ListUserTodoChanges(ctx context.Context, userID uuid.UUID) ([]*models.TodoChange, error) {
+	changes := make([]*models.TodoChange, 0)
+	query := `
+		SELECT c.seq, c.list_id, c.todo_id, c.deleted, c.status, c.changed_at
+		FROM todo_changes c
+		JOIN todo_lists l ON l.id = c.list_id
+		WHERE l.owner_id = $1 AND ` + liveList + `
+		ORDER BY c.seq`
+
+	if err := r.conn(ctx).SelectContext(ctx, &changes, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list todo changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// InsertTodoChanges appends entries to the change log, keeping their order
+// and times. They get new sequence numbers.
+func (r *todoRepo) InsertTodoChanges(ctx context.Context, changes []*models.TodoChange) error {
+	query := `
+		INSERT INTO todo_changes (list_id, todo_id, deleted, status, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING seq`
+
+	for _, c := range changes {
+		err := r.conn(ctx).GetContext(ctx, &c.Seq, query, c.ListID, c.TodoID, c.Deleted, c.Status, c.ChangedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert todo change: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ExistingListIDs returns those of ids that are taken by a list.
+func (r *todoRepo) ExistingListIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	return r.existingIDs(ctx, "todo_lists", ids)
+}
+
+// ExistingTodoIDs returns those of ids that are taken by a todo.
+func (r *todoRepo) ExistingTodoIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	return r.existingIDs(ctx, "todos", ids)
+}
+
+// ExistingAttachmentIDs returns those of ids that are taken by an
+// attachment.
+func (r *todoRepo) ExistingAttachmentIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	return r.existingIDs(ctx, "attachments", ids)
+}
+
+func (r *todoRepo) existingIDs(ctx context.Context, table string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	existing := make([]uuid.UUID, 0)
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	query := `
+		SELECT id
+		FROM ` + table + `
+		WHERE id = ANY($1::uuid[])`
+
+	if err := r.conn(ctx).SelectContext(ctx, &existing, query, uuidArray(ids)); err != nil {
+		return nil, fmt.Errorf("failed to look up %s ids: %w", table, err)
+	}
+
+	return existing, nil
+}
+
+func uuidArray(ids []uuid.UUID) pq.StringArray {
+	a := make(pq.StringArray, len(ids))
+	for i, id := range ids {
+		a[i] = id.String()
+	}
+	return a
+}
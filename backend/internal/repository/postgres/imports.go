@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// importJobColumns leaves out the payload, which is only needed by the
+// worker that claims the job.
+const importJobColumns = `
+	id, owner_id, source, list_name, file_hash, status, total, processed,
+	created_lists, created_todos, errors, error, created_at, updated_at, finished_at,
+	claimed_at, heartbeat_at, attempts`
+
+func (r *todoRepo) CreateImportJob(ctx context.Context, job *models.ImportJob) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	if job.Status == "" {
+		job.Status = models.ImportPending
+	}
+	query := `
+		INSERT INTO import_jobs (id, owner_id, source, list_name, file_hash, payload, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+
+	if err := r.conn(ctx).QueryRowContext(
+		ctx,
+		query,
+		job.ID,
+		job.OwnerID,
+		job.Source,
+		job.ListName,
+		job.FileHash,
+		job.Payload,
+		job.Status,
+	).Scan(&job.CreatedAt, &job.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	query := `
+		SELECT` + importJobColumns + `
+		FROM import_jobs
+		WHERE id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, job, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrImportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// FindImportJob returns the latest job that did not fail for the same file.
+func (r *todoRepo) FindImportJob(
+	ctx context.Context, ownerID *uuid.UUID, source string, fileHash []byte,
+) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	query := `
+		SELECT` + importJobColumns + `
+		FROM import_jobs
+		WHERE owner_id IS NOT DISTINCT FROM $1 AND source = $2 AND file_hash = $3 AND status <> 'failed'
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	if err := r.conn(ctx).GetContext(ctx, job, query, ownerID, source, fileHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrImportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to find import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimImportJob marks the oldest pending job as running and returns it with
+// its payload. Running jobs whose heartbeat is older than lease are claimed
+// again, as their worker is gone and the import it ran was rolled back; their
+// progress starts over. Concurrent workers skip jobs claimed by others.
+func (r *todoRepo) ClaimImportJob(ctx context.Context, lease time.Duration) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	query := `
+		UPDATE import_jobs
+		SET status = 'running', claimed_at = NOW(), heartbeat_at = NOW(), attempts = attempts + 1,
+			total = 0, processed = 0, created_lists = 0, created_todos = 0, errors = '[]'
+		WHERE id = (
+			SELECT id
+			FROM import_jobs
+			WHERE status = 'pending'
+				OR (status = 'running' AND heartbeat_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + importJobColumns + `, payload`
+
+	if err := r.conn(ctx).GetContext(ctx, job, query, lease.Seconds()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrImportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to claim import job: %w", err)
+	}
+
+	return job, nil
+}
+
+// HeartbeatImportJob renews the claim of the worker running a job.
+func (r *todoRepo) HeartbeatImportJob(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE import_jobs
+		SET heartbeat_at = NOW()
+		WHERE id = $1 AND status = 'running'`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to renew import job claim: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateImportJob stores the progress of a job. The payload is dropped once
+// the job has finished.
+func (r *todoRepo) UpdateImportJob(ctx context.Context, job *models.ImportJob) error {
+	query := `
+		UPDATE import_jobs
+		SET status = $1, total = $2, processed = $3, created_lists = $4, created_todos = $5,
+			errors = $6, error = $7, finished_at = $8,
+			payload = CASE WHEN $8::timestamptz IS NULL THEN payload END
+		WHERE id = $9
+		RETURNING updated_at`
+
+	if err := r.conn(ctx).GetContext(
+		ctx,
+		&job.UpdatedAt,
+		query,
+		job.Status,
+		job.Total,
+		job.Processed,
+		job.CreatedLists,
+		job.CreatedTodos,
+		job.Errors,
+		job.Error,
+		job.FinishedAt,
+		job.ID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrImportJobNotFound
+		}
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	return nil
+}
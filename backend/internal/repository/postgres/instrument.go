@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/awnzl/to-do-app/internal/logging"
+)
+
+var tracer = otel.Tracer("github.com/awnzl/to-do-app/internal/repository/postgres")
+
+// startSpan starts a client span for work on the database. Spans are only
+// started within a trace that is already running, so that jobs polling the
+// database don't start a trace each time.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, semconv.DBSystemNamePostgreSQL)...),
+	)
+}
+
+// endSpan records err on span, unless it only says that no row was found,
+// and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// instrumentedQuerier runs every query in a span named after its
+// operation, with the query text but not its arguments, and logs the
+// queries that fail unexpectedly.
+type instrumentedQuerier struct {
+	q querier
+}
+
+func (t instrumentedQuerier) GetContext(
+	ctx context.Context, dest interface{}, query string, args ...interface{},
+) (err error) {
+	ctx, end := startQuery(ctx, query)
+	defer func() { end(err) }()
+	return t.q.GetContext(ctx, dest, query, args...)
+}
+
+func (t instrumentedQuerier) SelectContext(
+	ctx context.Context, dest interface{}, query string, args ...interface{},
+) (err error) {
+	ctx, end := startQuery(ctx, query)
+	defer func() { end(err) }()
+	return t.q.SelectContext(ctx, dest, query, args...)
+}
+
+func (t instrumentedQuerier) ExecContext(
+	ctx context.Context, query string, args ...interface{},
+) (_ sql.Result, err error) {
+	ctx, end := startQuery(ctx, query)
+	defer func() { end(err) }()
+	return t.q.ExecContext(ctx, query, args...)
+}
+
+func (t instrumentedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, end := startQuery(ctx, query)
+	row := t.q.QueryRowContext(ctx, query, args...)
+	end(row.Err())
+	return row
+}
+
+// startQuery starts the span of a query. The returned function records how
+// the query ended. Queries that find no row or violate a unique constraint
+// are not logged, since callers turn those into errors of their own.
+func startQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	text := strings.Join(strings.Fields(query), " ")
+	operation, _, _ := strings.Cut(text, " ")
+	operation = strings.ToUpper(operation)
+	ctx, span := startSpan(ctx, operation, semconv.DBOperationName(operation), semconv.DBQueryText(text))
+	return ctx, func(err error) {
+		endSpan(span, err)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) && !isUniqueViolation(err) && ctx.Err() == nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "query failed", "operation", operation, "query", text, "error", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// fakeQuerier fails every query with err.
+type fakeQuerier struct {
+	err error
+}
+
+func (q fakeQuerier) GetContext(context.Context, interface{}, string, ...interface{}) error {
+	return q.err
+}
+
+func (q fakeQuerier) SelectContext(context.Context, interface{}, string, ...interface{}) error {
+	return q.err
+}
+
+func (q fakeQuerier) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, q.err
+}
+
+func (q fakeQuerier) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestTracedQuerier(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	query := `
+		SELECT id
+		FROM todos
+		WHERE id = $1`
+	err := instrumentedQuerier{fakeQuerier{err: sql.ErrNoRows}}.GetContext(ctx, nil, query, 1)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = instrumentedQuerier{fakeQuerier{err: errors.New("boom")}}.ExecContext(ctx, "update todos set title = $1", "x")
+	assert.Error(t, err)
+	// queries outside a trace are not traced
+	_ = instrumentedQuerier{fakeQuerier{}}.SelectContext(context.Background(), nil, "SELECT 1")
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "SELECT", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), semconv.DBQueryText("SELECT id FROM todos WHERE id = $1"))
+	assert.Contains(t, spans[0].Attributes(), semconv.DBSystemNamePostgreSQL)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, "UPDATE", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	assert.Equal(t, "request", spans[2].Name())
+}
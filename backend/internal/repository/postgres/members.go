@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// listAccess holds for the lists, aliased as l, that the user $1 can
+// access: lists without an owner, their own lists, lists shared with them
+// and the lists of workspaces they belong to, unless the workspace was
+// deleted.
+const listAccess = `(
+	(
+		l.owner_id IS NULL
+		OR l.owner_id = $1
+		OR EXISTS (SELECT 1 FROM list_members m WHERE m.list_id = l.id AND m.user_id = $1)
+		OR EXISTS (SELECT 1 FROM workspace_members wm WHERE wm.workspace_id = l.workspace_id AND wm.user_id = $1)
+	)
+	AND ` + liveList + `
+)`
+
+// AddListMember shares a list with a user and reports whether it was not
+// shared with them before.
+func (r *todoRepo) AddListMember(ctx context.Context, listID, userID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO list_members (list_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, listID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add list member: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add list member: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func (r *todoRepo) RemoveListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM list_members
+		WHERE list_id = $1 AND user_id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, listID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove list member: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrListMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *todoRepo) ListListMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error) {
+	members := make([]*models.ListMember, 0)
+	query := `
+		SELECT m.list_id, m.user_id, u.username, m.added_at
+		FROM list_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.list_id = $1
+		ORDER BY m.added_at, u.username`
+
+	if err := r.conn(ctx).SelectContext(ctx, &members, query, listID); err != nil {
+		return nil, fmt.Errorf("failed to list list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// HasListAccess reports whether a user can access a list.
+func (r *todoRepo) HasListAccess(ctx context.Context, listID, userID uuid.UUID) (bool, error) {
+	var ok bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM todo_lists l
+			WHERE l.id = $2 AND ` + listAccess + `
+		)`
+
+	if err := r.conn(ctx).GetContext(ctx, &ok, query, userID, listID); err != nil {
+		return false, fmt.Errorf("failed to check list access: %w", err)
+	}
+
+	return ok, nil
+}
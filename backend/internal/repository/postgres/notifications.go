@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (r *todoRepo) ListDigestSubscribers(ctx context.Context) ([]*models.DigestSubscriber, error) {
+	var subscribers []*models.DigestSubscriber
+	query := `
+		SELECT u.id, u.username, u.email, u.time_zone, u.created_at, p.digest_hour, p.last_digest_on
+		FROM users u
+		JOIN notification_preferences p ON p.user_id = u.id
+		WHERE p.digest_enabled`
+
+	if err := r.conn(ctx).SelectContext(ctx, &subscribers, query); err != nil {
+		return nil, fmt.Errorf("failed to list digest subscribers: %w", err)
+	}
+
+	return subscribers, nil
+}
+
+// ListDueTodosForUser returns the open todos that are due before an instant
+// in the lists a user can access, leaving out lists without an owner unless
+// the todo is assigned to them. All-day todos count when their due date lies
+// before the day the instant falls on in their time zone.
+func (r *todoRepo) ListDueTodosForUser(ctx context.Context, userID uuid.UUID, before time.Time) ([]*models.Todo, error) {
+	var todos []*models.Todo
+	query := `
+		SELECT` + todoColumns + `
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE ` + listAccess + ` AND t.status = false
+			AND (
+				l.owner_id IS NOT NULL
+				OR EXISTS (SELECT 1 FROM todo_assignees a WHERE a.todo_id = t.id AND a.user_id = $1)
+			)
+			AND COALESCE(t.due_date < $2, t.due_on < ($2::timestamptz AT TIME ZONE ` + todoZone + `)::date)
+		ORDER BY ` + todoDue
+
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, userID, before); err != nil {
+		return nil, fmt.Errorf("failed to list due todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+func (r *todoRepo) MarkDigestSent(ctx context.Context, userID uuid.UUID, day time.Time) error {
+	query := `
+		UPDATE notification_preferences
+		SET last_digest_on = $1
+		WHERE user_id = $2`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, day.Format(time.DateOnly), userID); err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+
+	return nil
+}
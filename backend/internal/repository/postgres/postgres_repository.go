@@ -8,11 +8,44 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/awnzl/to-do-app/internal/models"
 	"github.com/awnzl/to-do-app/internal/repository"
 )
 
+// todoColumns selects every column of models.Todo from the todos table
+// aliased as t.
+const todoColumns = `
+	t.id, t.list_id, t.title, t.description, t.due_date, t.due_on, t.time_zone,
+	t.start_date, t.scheduled_for, t.column_id, t.rank, t.status, t.tags, t.priority,
+	t.recurrence, t.completed_at, t.archived_at, t.created_at, t.updated_at,
+	ARRAY(
+		SELECT a.user_id::text
+		FROM todo_assignees a
+		WHERE a.todo_id = t.id
+		ORDER BY a.assigned_at, a.user_id
+	) AS assignees`
+
+// listZone is the time zone of the owner of the list of the todo aliased
+// as t, or UTC for lists without an owner.
+const listZone = `COALESCE(
+		(SELECT zu.time_zone FROM todo_lists zl JOIN users zu ON zu.id = zl.owner_id WHERE zl.id = t.list_id),
+		'UTC'
+	)`
+
+// todoZone is the time zone the due date of the todo aliased as t is
+// evaluated in: its own, else its list owner's, else UTC.
+const todoZone = `COALESCE(NULLIF(t.time_zone, ''), ` + listZone + `)`
+
+// todoDue is the due instant of the todo aliased as t, the start of the day
+// for all-day todos.
+const todoDue = `COALESCE(t.due_date, t.due_on::timestamp AT TIME ZONE ` + todoZone + `)`
+
+// todoOverdue holds for open todos of t whose due time has passed or whose
+// all-day due date lies before the current day in their time zone.
+const todoOverdue = `(NOT t.status AND (t.due_date < NOW() OR t.due_on < (NOW() AT TIME ZONE ` + todoZone + `)::date))`
+
 type todoRepo struct {
 	db *sqlx.DB
 }
@@ -21,31 +54,59 @@ func NewTodoRepo(db *sqlx.DB) repository.Repository {
 	return &todoRepo{db: db}
 }
 
-func (r *todoRepo) CreateList(ctx context.Context, name string) (*models.TodoList, error) {
+func (r *todoRepo) CreateList(ctx context.Context, name string, ownerID *uuid.UUID) (*models.TodoList, error) {
 	list := &models.TodoList{
-		ID:   uuid.New(),
-		Name: name,
+		Name:    name,
+		OwnerID: ownerID,
+	}
+
+	if err := r.InsertList(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// InsertList stores a list, assigning a new ID when it has none. A zero
+// CreatedAt is set from the database.
+func (r *todoRepo) InsertList(ctx context.Context, list *models.TodoList) error {
+	if list.ID == uuid.Nil {
+		list.ID = uuid.New()
 	}
 	query := `
-		INSERT INTO todo_lists (id, name)
-		VALUES ($1, $2)
+		INSERT INTO todo_lists (
+			id, name, owner_id, workspace_id, folder_id, archived_at, auto_archive_days, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()))
 		RETURNING created_at`
 
-	if err := r.db.GetContext(ctx, &list.CreatedAt, query, list.ID, list.Name); err != nil {
-		return nil, fmt.Errorf("failed to create list: %w", err)
+	if err := r.conn(ctx).GetContext(
+		ctx,
+		&list.CreatedAt,
+		query,
+		list.ID,
+		list.Name,
+		list.OwnerID,
+		list.WorkspaceID,
+		list.FolderID,
+		list.ArchivedAt,
+		list.AutoArchiveDays,
+		timestamp(list.CreatedAt),
+	); err != nil {
+		return fmt.Errorf("failed to create list: %w", err)
 	}
 
-	return list, nil
+	return nil
 }
 
 func (r *todoRepo) GetList(ctx context.Context, id uuid.UUID) (*models.TodoList, error) {
 	list := &models.TodoList{}
 	query := `
-		SELECT id, name, created_at
-		FROM todo_lists
-		WHERE id = $1`
+		SELECT ` + listColumns + `
+		FROM todo_lists l
+		WHERE l.id = $1 AND ` + liveList
 
-	if err := r.db.GetContext(ctx, list, query, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, list, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, repository.ErrListNotFound
 		}
@@ -61,7 +122,7 @@ func (r *todoRepo) UpdateList(ctx context.Context, list *models.TodoList) error
 		SET name = $1
 		WHERE id = $2`
 
-	if _, err := r.db.ExecContext(ctx, query, list.Name, list.ID); err != nil {
+	if _, err := r.conn(ctx).ExecContext(ctx, query, list.Name, list.ID); err != nil {
 		return fmt.Errorf("failed to update list: %w", err)
 	}
 
@@ -73,42 +134,50 @@ func (r *todoRepo) DeleteList(ctx context.Context, id uuid.UUID) error {
 		DELETE FROM todo_lists
 		WHERE id = $1`
 
-	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
 		return fmt.Errorf("failed to delete list: %w", err)
 	}
 
 	return nil
 }
 
-func (r *todoRepo) ListLists(ctx context.Context) ([]*models.TodoList, error) {
+// ListLists returns the lists outside of deleted workspaces that match f.
+func (r *todoRepo) ListLists(ctx context.Context, f models.ListFilter) ([]*models.TodoList, error) {
 	lists := make([]*models.TodoList, 0)
 	query := `
-		SELECT id, name, created_at
-		FROM todo_lists`
-
-	if err := r.db.SelectContext(ctx, &lists, query); err != nil {
+		SELECT ` + listColumns + `
+		FROM todo_lists l
+		WHERE ` + liveList + `
+			AND ($1::uuid IS NULL OR l.workspace_id = $1)
+			AND ($2::uuid IS NULL OR l.folder_id = $2)
+			AND ($3 OR l.archived_at IS NULL)
+		ORDER BY l.created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &lists, query, f.WorkspaceID, f.FolderID, f.Archived); err != nil {
 		return nil, fmt.Errorf("failed to list lists: %w", err)
 	}
 
 	return lists, nil
 }
 
-func (r *todoRepo) CreateTodo(ctx context.Context, listID uuid.UUID, title, description string, dueDate *time.Time) (*models.Todo, error) {
-	query := `
-		INSERT INTO todos (id, list_id, title, description, due_date, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING created_at, updated_at`
-
-	todo := &models.Todo{
-		ID:          uuid.New(),
-		ListID:      listID,
-		Title:       title,
-		Description: description,
-		DueDate:     dueDate,
-		Status:      false,
+// InsertTodo stores a fully populated todo, assigning a new ID when it has
+// none. Zero timestamps are set from the database.
+func (r *todoRepo) InsertTodo(ctx context.Context, todo *models.Todo) error {
+	if todo.ID == uuid.Nil {
+		todo.ID = uuid.New()
 	}
-
-	if err := r.db.QueryRowContext(
+	query := `
+		INSERT INTO todos (
+			id, list_id, title, description, due_date, due_on, time_zone, start_date, scheduled_for,
+			status, tags, priority, recurrence, completed_at, column_id, rank, archived_at, created_at, updated_at
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+			COALESCE($18, NOW()), COALESCE($19, NOW())
+		)
+		RETURNING completed_at, created_at, updated_at`
+
+	if err := r.conn(ctx).QueryRowContext(
 		ctx,
 		query,
 		todo.ID,
@@ -116,22 +185,35 @@ func (r *todoRepo) CreateTodo(ctx context.Context, listID uuid.UUID, title, desc
 		todo.Title,
 		todo.Description,
 		todo.DueDate,
+		todo.DueOn,
+		todo.TimeZone,
+		todo.StartDate,
+		todo.ScheduledFor,
 		todo.Status,
-	).Scan(&todo.CreatedAt, &todo.UpdatedAt); err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+		tags(todo.Tags),
+		todo.Priority,
+		todo.Recurrence,
+		todo.CompletedAt,
+		todo.ColumnID,
+		todo.Rank,
+		todo.ArchivedAt,
+		timestamp(todo.CreatedAt),
+		timestamp(todo.UpdatedAt),
+	).Scan(&todo.CompletedAt, &todo.CreatedAt, &todo.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create todo: %w", err)
 	}
 
-	return todo, nil
+	return nil
 }
 
 func (r *todoRepo) GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
 	todo := &models.Todo{}
 	query := `
-		SELECT id, list_id, title, description, due_date, status, created_at, updated_at
-		FROM todos
-		WHERE id = $1`
+		SELECT` + todoColumns + `
+		FROM todos t
+		WHERE t.id = $1`
 
-	if err := r.db.GetContext(ctx, todo, query, id); err != nil {
+	if err := r.conn(ctx).GetContext(ctx, todo, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, repository.ErrTodoNotFound
 		}
@@ -144,16 +226,24 @@ func (r *todoRepo) GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo, err
 func (r *todoRepo) UpdateTodo(ctx context.Context, todo *models.Todo) error {
 	query := `
 		UPDATE todos
-		SET title = $1, description = $2, due_date = $3, status = $4
-		WHERE id = $5`
+		SET title = $1, description = $2, due_date = $3, due_on = $4, time_zone = $5, start_date = $6,
+			scheduled_for = $7, status = $8, tags = $9, priority = $10, recurrence = $11
+		WHERE id = $12`
 
-	if _, err := r.db.ExecContext(
+	if _, err := r.conn(ctx).ExecContext(
 		ctx,
 		query,
 		todo.Title,
 		todo.Description,
 		todo.DueDate,
+		todo.DueOn,
+		todo.TimeZone,
+		todo.StartDate,
+		todo.ScheduledFor,
 		todo.Status,
+		tags(todo.Tags),
+		todo.Priority,
+		todo.Recurrence,
 		todo.ID,
 	); err != nil {
 		return fmt.Errorf("failed to update todo: %w", err)
@@ -162,26 +252,43 @@ func (r *todoRepo) UpdateTodo(ctx context.Context, todo *models.Todo) error {
 	return nil
 }
 
+// MoveTodo moves a todo to another list, taking it off the board of the
+// list it leaves.
+func (r *todoRepo) MoveTodo(ctx context.Context, id, listID uuid.UUID) error {
+	query := `
+		UPDATE todos
+		SET list_id = $1, column_id = NULL
+		WHERE id = $2 AND list_id <> $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, listID, id); err != nil {
+		return fmt.Errorf("failed to move todo: %w", err)
+	}
+
+	return nil
+}
+
 func (r *todoRepo) DeleteTodo(ctx context.Context, id uuid.UUID) error {
 	query := `
 		DELETE FROM todos
 		WHERE id = $1`
 
-	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
 		return fmt.Errorf("failed to delete todo: %w", err)
 	}
 
 	return nil
 }
 
-func (r *todoRepo) ListTodos(ctx context.Context, listID uuid.UUID) ([]*models.Todo, error) {
+// ListTodos returns the todos of a list, leaving out archived todos unless
+// archived is set.
+func (r *todoRepo) ListTodos(ctx context.Context, listID uuid.UUID, archived bool) ([]*models.Todo, error) {
 	var todos []*models.Todo
 	query := `
-		SELECT id, list_id, title, description, due_date, status, created_at, updated_at
-		FROM todos
-		WHERE list_id = $1`
+		SELECT` + todoColumns + `
+		FROM todos t
+		WHERE t.list_id = $1 AND ($2 OR t.archived_at IS NULL)`
 
-	if err := r.db.SelectContext(ctx, &todos, query, listID); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, listID, archived); err != nil {
 		return nil, fmt.Errorf("failed to list todos: %w", err)
 	}
 
@@ -191,13 +298,29 @@ func (r *todoRepo) ListTodos(ctx context.Context, listID uuid.UUID) ([]*models.T
 func (r *todoRepo) ListOverdueTodos(ctx context.Context) ([]*models.Todo, error) {
 	var todos []*models.Todo
 	query := `
-		SELECT id, list_id, title, description, due_date, status, created_at, updated_at
-		FROM todos
-		WHERE due_date IS NOT NULL AND due_date < NOW() AND status = false`
+		SELECT` + todoColumns + `
+		FROM todos t
+		WHERE ` + todoOverdue
 
-	if err := r.db.SelectContext(ctx, &todos, query); err != nil {
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query); err != nil {
 		return nil, fmt.Errorf("failed to list overdue todos: %w", err)
 	}
 
-	return r.ListTodos(ctx, uuid.Nil)
+	return todos, nil
+}
+
+// tags keeps the NOT NULL tags column happy for todos without tags.
+func tags(t pq.StringArray) pq.StringArray {
+	if t == nil {
+		return pq.StringArray{}
+	}
+	return t
+}
+
+// timestamp passes zero times as NULL.
+func timestamp(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
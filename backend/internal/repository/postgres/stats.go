@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// todoDeadline is the instant the todo aliased as t becomes overdue: its due
+// time, or the end of its all-day due date.
+const todoDeadline = `COALESCE(t.due_date, (t.due_on + 1)::timestamp AT TIME ZONE ` + todoZone + `)`
+
+// ListStats returns the activity of each list matching q, and of the todos
+// in them assigned to each user, on every day of q's range. Lists and users
+// without todos open or completed in the range are left out, and list rows
+// come first.
+func (r *todoRepo) ListStats(ctx context.Context, q models.StatsQuery) ([]*models.StatsRow, error) {
+	rows := make([]*models.StatsRow, 0)
+	query := `
+		WITH days AS (
+			SELECT d::date AS day,
+				d AT TIME ZONE $5::text AS day_start,
+				(d + INTERVAL '1 day') AT TIME ZONE $5::text AS day_end
+			FROM generate_series($3::date::timestamp, $4::date::timestamp, INTERVAL '1 day') d
+		),
+		scoped AS (
+			SELECT t.id, t.list_id, l.name, t.created_at, t.completed_at, ` + todoDeadline + ` AS deadline
+			FROM todos t
+			JOIN todo_lists l ON l.id = t.list_id
+			WHERE ` + listAccess + `
+				AND ($2::uuid IS NULL OR l.id = $2)
+				AND t.created_at < (SELECT MAX(day_end) FROM days)
+				AND (t.completed_at IS NULL OR t.completed_at >= (SELECT MIN(day_start) FROM days))
+		),
+		groups AS (
+			SELECT s.list_id, NULL::uuid AS user_id, s.name, s.created_at, s.completed_at, s.deadline
+			FROM scoped s
+			UNION ALL
+			SELECT NULL, a.user_id, u.username, s.created_at, s.completed_at, s.deadline
+			FROM scoped s
+			JOIN todo_assignees a ON a.todo_id = s.id
+			JOIN users u ON u.id = a.user_id
+		)
+		SELECT g.list_id, g.user_id, g.name, d.day,
+			COUNT(*) FILTER (WHERE g.created_at >= d.day_start AND g.created_at < d.day_end) AS created,
+			COUNT(*) FILTER (WHERE g.completed_at >= d.day_start AND g.completed_at < d.day_end) AS completed,
+			COALESCE(
+				SUM(EXTRACT(EPOCH FROM g.completed_at - g.created_at))
+					FILTER (WHERE g.completed_at >= d.day_start AND g.completed_at < d.day_end),
+				0
+			)::float8 AS completion_seconds,
+			COUNT(*) FILTER (
+				WHERE g.deadline < LEAST(d.day_end, NOW())
+					AND g.created_at < d.day_end
+					AND (g.completed_at IS NULL OR g.completed_at >= d.day_end)
+			) AS overdue
+		FROM groups g
+		CROSS JOIN days d
+		GROUP BY g.list_id, g.user_id, g.name, d.day
+		ORDER BY g.user_id NULLS FIRST, g.name, g.list_id, d.day`
+
+	err := r.conn(ctx).SelectContext(ctx, &rows, query, q.UserID, q.ListID, q.From, q.To, q.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stats: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ListBurndown counts the open and done todos in a list at the end of every
+// day from from to to in timeZone. A todo's state at a time is that after
+// its last change before it, so todos count in the list they were moved to
+// from the day of the move, and deleted todos stop counting.
+func (r *todoRepo) ListBurndown(
+	ctx context.Context, listID uuid.UUID, from, to models.Date, timeZone string,
+) ([]*models.BurndownDay, error) {
+	days := make([]*models.BurndownDay, 0)
+	query := `
+		WITH days AS (
+			SELECT d::date AS day, (d + INTERVAL '1 day') AT TIME ZONE $4::text AS day_end
+			FROM generate_series($2::date::timestamp, $3::date::timestamp, INTERVAL '1 day') d
+		)
+		SELECT d.day,
+			COUNT(s.todo_id) FILTER (WHERE NOT s.status) AS open,
+			COUNT(s.todo_id) FILTER (WHERE s.status) AS done
+		FROM days d
+		LEFT JOIN LATERAL (
+			SELECT DISTINCT ON (c.todo_id) c.todo_id, c.list_id, c.deleted, c.status
+			FROM todo_changes c
+			WHERE c.todo_id IN (SELECT todo_id FROM todo_changes WHERE list_id = $1)
+				AND c.changed_at < d.day_end
+			ORDER BY c.todo_id, c.seq DESC
+		) s ON s.list_id = $1 AND NOT s.deleted
+		GROUP BY d.day
+		ORDER BY d.day`
+
+	if err := r.conn(ctx).SelectContext(ctx, &days, query, listID, from, to, timeZone); err != nil {
+		return nil, fmt.Errorf("failed to list burndown: %w", err)
+	}
+
+	return days, nil
+}
+
+// CountTodos counts the open and overdue todos in all lists that are not in
+// a deleted workspace.
+func (r *todoRepo) CountTodos(ctx context.Context) (*models.TodoCounts, error) {
+	var counts models.TodoCounts
+	query := `
+		SELECT COUNT(*) AS open, COUNT(*) FILTER (WHERE ` + todoOverdue + `) AS overdue
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE NOT t.status AND ` + liveList
+
+	if err := r.conn(ctx).GetContext(ctx, &counts, query); err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
+	}
+
+	return &counts, nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+const templateColumns = `id, owner_id, name, list_name, todos, created_at, updated_at`
+
+func (r *todoRepo) CreateListTemplate(ctx context.Context, t *models.ListTemplate) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO list_templates (id, owner_id, name, list_name, todos)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at`
+
+	if err := r.conn(ctx).QueryRowContext(
+		ctx, query, t.ID, t.OwnerID, t.Name, t.ListName, t.Todos,
+	).Scan(&t.CreatedAt, &t.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrTemplateNameTaken
+		}
+		return fmt.Errorf("failed to create list template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetListTemplate(ctx context.Context, id uuid.UUID) (*models.ListTemplate, error) {
+	t := &models.ListTemplate{}
+	query := `
+		SELECT ` + templateColumns + `
+		FROM list_templates
+		WHERE id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, t, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get list template: %w", err)
+	}
+
+	return t, nil
+}
+
+// ListListTemplates returns the templates without an owner and those of
+// the user, or all of them when userID is nil.
+func (r *todoRepo) ListListTemplates(ctx context.Context, userID *uuid.UUID) ([]*models.ListTemplate, error) {
+	templates := make([]*models.ListTemplate, 0)
+	query := `
+		SELECT ` + templateColumns + `
+		FROM list_templates
+		WHERE $1::uuid IS NULL OR owner_id IS NULL OR owner_id = $1
+		ORDER BY name`
+
+	if err := r.conn(ctx).SelectContext(ctx, &templates, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list list templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (r *todoRepo) UpdateListTemplate(ctx context.Context, t *models.ListTemplate) error {
+	query := `
+		UPDATE list_templates
+		SET name = $1, list_name = $2, todos = $3
+		WHERE id = $4
+		RETURNING updated_at`
+
+	if err := r.conn(ctx).GetContext(ctx, &t.UpdatedAt, query, t.Name, t.ListName, t.Todos, t.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrTemplateNotFound
+		}
+		if isUniqueViolation(err) {
+			return repository.ErrTemplateNameTaken
+		}
+		return fmt.Errorf("failed to update list template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) DeleteListTemplate(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM list_templates
+		WHERE id = $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete list template: %w", err)
+	}
+
+	return nil
+}
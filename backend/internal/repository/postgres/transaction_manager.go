@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -9,16 +10,38 @@ import (
 	"github.com/awnzl/to-do-app/internal/repository"
 )
 
+type txKey struct{}
+
+// querier is implemented by both *sqlx.DB and *sqlx.Tx.
+type querier interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // TxManager is a transaction manager for PostgreSQL
 type TxManager struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	observer repository.TxObserver
 }
 
-func NewTxManager(db *sqlx.DB) *TxManager {
-	return &TxManager{db: db}
+// NewTxManager returns a transaction manager that reports the outcome of
+// every transaction to observer, if not nil.
+func NewTxManager(db *sqlx.DB, observer repository.TxObserver) *TxManager {
+	return &TxManager{db: db, observer: observer}
 }
 
-func (tm *TxManager) WithTransaction(ctx context.Context, fn repository.TxFn) error {
+// WithTransaction runs fn in a transaction that repository calls made with
+// the passed context take part in. Nested calls join the outer transaction.
+func (tm *TxManager) WithTransaction(ctx context.Context, fn repository.TxFn) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx, tx)
+	}
+
+	ctx, span := startSpan(ctx, "transaction")
+	defer func() { endSpan(span, err) }()
+
 	tx, err := tm.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -27,11 +50,13 @@ func (tm *TxManager) WithTransaction(ctx context.Context, fn repository.TxFn) er
 	defer func() {
 		if p := recover(); p != nil {
 			_ = tx.Rollback()
+			tm.done(repository.TxRolledBack)
 			panic(p) // re-throw panic after rollback
 		}
 	}()
 
-	if err := fn(ctx, tx); err != nil {
+	if err := fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		tm.done(repository.TxRolledBack)
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("rollback failed: %v (original error: %w)", rbErr, err)
 		}
@@ -39,8 +64,26 @@ func (tm *TxManager) WithTransaction(ctx context.Context, fn repository.TxFn) er
 	}
 
 	if err := tx.Commit(); err != nil {
+		// a failed commit leaves the transaction rolled back
+		tm.done(repository.TxRolledBack)
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 
+	tm.done(repository.TxCommitted)
 	return nil
 }
+
+func (tm *TxManager) done(outcome repository.TxOutcome) {
+	if tm.observer != nil {
+		tm.observer.TxDone(outcome)
+	}
+}
+
+// conn returns the transaction running in ctx, or the database otherwise.
+// Queries run on it are traced and logged when they fail.
+func (r *todoRepo) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return instrumentedQuerier{tx}
+	}
+	return instrumentedQuerier{r.db}
+}
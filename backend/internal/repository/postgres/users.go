@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+func (r *todoRepo) CreateUser(ctx context.Context, username, email, timeZone string) (*models.User, error) {
+	user := &models.User{
+		ID:       uuid.New(),
+		Username: username,
+		Email:    email,
+		TimeZone: timeZone,
+	}
+	// every user starts with the default notification preferences
+	query := `
+		WITH u AS (
+			INSERT INTO users (id, username, email, time_zone)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		), p AS (
+			INSERT INTO notification_preferences (user_id)
+			SELECT id FROM u
+		)
+		SELECT created_at FROM u`
+
+	if err := r.conn(ctx).GetContext(
+		ctx, &user.CreatedAt, query, user.ID, user.Username, user.Email, user.TimeZone,
+	); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *todoRepo) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user := &models.User{}
+	query := `
+		SELECT id, username, email, time_zone, created_at
+		FROM users
+		WHERE id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, user, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *todoRepo) GetNotificationPreferences(
+	ctx context.Context, userID uuid.UUID,
+) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{}
+	query := `
+		SELECT user_id, digest_enabled, digest_hour, assignment_emails, mention_emails, last_digest_on
+		FROM notification_preferences
+		WHERE user_id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, prefs, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (r *todoRepo) UpdateNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	query := `
+		UPDATE notification_preferences
+		SET digest_enabled = $1, digest_hour = $2, assignment_emails = $3, mention_emails = $4
+		WHERE user_id = $5`
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		query,
+		prefs.DigestEnabled,
+		prefs.DigestHour,
+		prefs.AssignmentEmails,
+		prefs.MentionEmails,
+		prefs.UserID,
+	); err != nil {
+		return fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsersByUsernames returns the users with the given usernames, compared
+// case-insensitively. Unknown names are skipped.
+func (r *todoRepo) GetUsersByUsernames(ctx context.Context, usernames []string) ([]*models.User, error) {
+	users := make([]*models.User, 0)
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	lower := make(pq.StringArray, len(usernames))
+	for i, name := range usernames {
+		lower[i] = strings.ToLower(name)
+	}
+	query := `
+		SELECT id, username, email, time_zone, created_at
+		FROM users
+		WHERE lower(username) = ANY($1)
+		ORDER BY username`
+
+	if err := r.conn(ctx).SelectContext(ctx, &users, query, lower); err != nil {
+		return nil, fmt.Errorf("failed to get users by username: %w", err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/awnzl/to-do-app/internal/filter"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+func (r *todoRepo) CreateView(ctx context.Context, v *models.View) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO views (id, owner_id, name, query)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at`
+
+	if err := r.conn(ctx).QueryRowContext(
+		ctx, query, v.ID, v.OwnerID, v.Name, v.Query,
+	).Scan(&v.CreatedAt, &v.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrViewNameTaken
+		}
+		return fmt.Errorf("failed to create view: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetView(ctx context.Context, id uuid.UUID) (*models.View, error) {
+	v := &models.View{}
+	query := `
+		SELECT id, owner_id, name, query, created_at, updated_at
+		FROM views
+		WHERE id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, v, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrViewNotFound
+		}
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+
+	return v, nil
+}
+
+func (r *todoRepo) ListViews(ctx context.Context, ownerID uuid.UUID) ([]*models.View, error) {
+	views := make([]*models.View, 0)
+	query := `
+		SELECT id, owner_id, name, query, created_at, updated_at
+		FROM views
+		WHERE owner_id = $1
+		ORDER BY name`
+
+	if err := r.conn(ctx).SelectContext(ctx, &views, query, ownerID); err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+
+	return views, nil
+}
+
+func (r *todoRepo) UpdateView(ctx context.Context, v *models.View) error {
+	query := `
+		UPDATE views
+		SET name = $1, query = $2
+		WHERE id = $3
+		RETURNING updated_at`
+
+	if err := r.conn(ctx).GetContext(ctx, &v.UpdatedAt, query, v.Name, v.Query, v.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ErrViewNotFound
+		}
+		if isUniqueViolation(err) {
+			return repository.ErrViewNameTaken
+		}
+		return fmt.Errorf("failed to update view: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) DeleteView(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM views
+		WHERE id = $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+
+	return nil
+}
+
+// ListFilteredTodos returns the todos matching a filter query in the lists
+// a user can access, those due first at the top. Relative times in the
+// query are resolved against now.
+func (r *todoRepo) ListFilteredTodos(
+	ctx context.Context, userID uuid.UUID, f filter.Node, now time.Time, limit, offset int,
+) ([]*models.Todo, error) {
+	q := &filterQuery{args: []any{userID}, now: now, userID: userID}
+	cond, err := q.compile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]*models.Todo, 0)
+	query := `
+		SELECT` + todoColumns + `
+		FROM todos t
+		JOIN todo_lists l ON l.id = t.list_id
+		WHERE ` + listAccess + `
+			AND ` + cond + `
+		ORDER BY ` + todoDue + ` NULLS LAST, t.created_at, t.id
+		LIMIT ` + q.arg(limit) + ` OFFSET ` + q.arg(offset)
+
+	if err := r.conn(ctx).SelectContext(ctx, &todos, query, q.args...); err != nil {
+		return nil, fmt.Errorf("failed to list filtered todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
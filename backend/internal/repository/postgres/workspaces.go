@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// listColumns are the columns of a list, aliased as l.
+const listColumns = `
+	l.id, l.name, l.owner_id, l.workspace_id, l.folder_id, l.archived_at, l.auto_archive_days,
+	l.created_at`
+
+// liveList holds for the lists, aliased as l, that are not in a deleted
+// workspace.
+const liveList = `(
+	l.workspace_id IS NULL
+	OR EXISTS (SELECT 1 FROM workspaces lw WHERE lw.id = l.workspace_id AND lw.deleted_at IS NULL)
+)`
+
+const workspaceColumns = `id, name, owner_id, created_at, deleted_at`
+
+func (r *todoRepo) CreateWorkspace(ctx context.Context, w *models.Workspace) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO workspaces (id, name, owner_id)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+
+	if err := r.conn(ctx).GetContext(ctx, &w.CreatedAt, query, w.ID, w.Name, w.OwnerID); err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkspace returns a workspace that was not deleted.
+func (r *todoRepo) GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	w := &models.Workspace{}
+	query := `
+		SELECT ` + workspaceColumns + `
+		FROM workspaces
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := r.conn(ctx).GetContext(ctx, w, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return w, nil
+}
+
+// ListWorkspaces returns the workspaces that were not deleted: those
+// without an owner and those the user belongs to, or all of them when
+// userID is nil.
+func (r *todoRepo) ListWorkspaces(ctx context.Context, userID *uuid.UUID) ([]*models.Workspace, error) {
+	workspaces := make([]*models.Workspace, 0)
+	query := `
+		SELECT ` + workspaceColumns + `
+		FROM workspaces w
+		WHERE deleted_at IS NULL AND (
+			$1::uuid IS NULL
+			OR owner_id IS NULL
+			OR EXISTS (SELECT 1 FROM workspace_members m WHERE m.workspace_id = w.id AND m.user_id = $1)
+		)
+		ORDER BY created_at`
+
+	if err := r.conn(ctx).SelectContext(ctx, &workspaces, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+func (r *todoRepo) UpdateWorkspace(ctx context.Context, w *models.Workspace) error {
+	query := `
+		UPDATE workspaces
+		SET name = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, w.Name, w.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// DeleteWorkspace marks a workspace as deleted. Its folders, lists and
+// members are kept for RestoreWorkspace.
+func (r *todoRepo) DeleteWorkspace(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE workspaces
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// RestoreWorkspace undoes DeleteWorkspace.
+func (r *todoRepo) RestoreWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	w := &models.Workspace{}
+	query := `
+		UPDATE workspaces
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + workspaceColumns
+
+	if err := r.conn(ctx).GetContext(ctx, w, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to restore workspace: %w", err)
+	}
+
+	return w, nil
+}
+
+// AddWorkspaceMember adds a user to a workspace and reports whether they
+// were not a member before.
+func (r *todoRepo) AddWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (bool, error) {
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func (r *todoRepo) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	query := `
+		DELETE FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrWorkspaceMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *todoRepo) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMember, error) {
+	members := make([]*models.WorkspaceMember, 0)
+	query := `
+		SELECT m.workspace_id, m.user_id, u.username, m.added_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.added_at, u.username`
+
+	if err := r.conn(ctx).SelectContext(ctx, &members, query, workspaceID); err != nil {
+		return nil, fmt.Errorf("failed to list workspace members: %w", err)
+	}
+
+	return members, nil
+}
+
+func (r *todoRepo) CreateFolder(ctx context.Context, f *models.Folder) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	query := `
+		INSERT INTO folders (id, workspace_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING created_at`
+
+	if err := r.conn(ctx).GetContext(ctx, &f.CreatedAt, query, f.ID, f.WorkspaceID, f.Name); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrFolderNameTaken
+		}
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	return nil
+}
+
+func (r *todoRepo) GetFolder(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	f := &models.Folder{}
+	query := `
+		SELECT id, workspace_id, name, created_at
+		FROM folders
+		WHERE id = $1`
+
+	if err := r.conn(ctx).GetContext(ctx, f, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repository.ErrFolderNotFound
+		}
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+
+	return f, nil
+}
+
+func (r *todoRepo) ListFolders(ctx context.Context, workspaceID uuid.UUID) ([]*models.Folder, error) {
+	folders := make([]*models.Folder, 0)
+	query := `
+		SELECT id, workspace_id, name, created_at
+		FROM folders
+		WHERE workspace_id = $1
+		ORDER BY name`
+
+	if err := r.conn(ctx).SelectContext(ctx, &folders, query, workspaceID); err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+
+	return folders, nil
+}
+
+func (r *todoRepo) UpdateFolder(ctx context.Context, f *models.Folder) error {
+	query := `
+		UPDATE folders
+		SET name = $1
+		WHERE id = $2`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, f.Name, f.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return repository.ErrFolderNameTaken
+		}
+		return fmt.Errorf("failed to update folder: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+// DeleteFolder removes a folder; its lists stay at the top of the
+// workspace.
+func (r *todoRepo) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	query := `
+		DELETE FROM folders
+		WHERE id = $1`
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+
+	return nil
+}
+
+// SetListLocation moves a list into a workspace and folder. The location
+// is expected to be consistent, see models.ListLocation.
+func (r *todoRepo) SetListLocation(ctx context.Context, listID uuid.UUID, loc models.ListLocation) error {
+	query := `
+		UPDATE todo_lists
+		SET workspace_id = $1, folder_id = $2
+		WHERE id = $3`
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, loc.WorkspaceID, loc.FolderID, listID)
+	if err != nil {
+		return fmt.Errorf("failed to move list: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return repository.ErrListNotFound
+	}
+
+	return nil
+}
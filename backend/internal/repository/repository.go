@@ -6,24 +6,165 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/awnzl/to-do-app/internal/filter"
 	"github.com/awnzl/to-do-app/internal/models"
 )
 
 type Repository interface {
 	// Lists
-	CreateList(ctx context.Context, name string) (*models.TodoList, error)
+	CreateList(ctx context.Context, name string, ownerID *uuid.UUID) (*models.TodoList, error)
+	InsertList(ctx context.Context, list *models.TodoList) error
 	GetList(ctx context.Context, id uuid.UUID) (*models.TodoList, error)
 	UpdateList(ctx context.Context, list *models.TodoList) error
 	DeleteList(ctx context.Context, id uuid.UUID) error
-	ListLists(ctx context.Context) ([]*models.TodoList, error)
+	ListLists(ctx context.Context, f models.ListFilter) ([]*models.TodoList, error)
+	SetListArchived(ctx context.Context, id uuid.UUID, archived bool) error
+	SetListAutoArchive(ctx context.Context, id uuid.UUID, days *int) error
+	SetListLocation(ctx context.Context, listID uuid.UUID, loc models.ListLocation) error
+
+	// List templates
+	CreateListTemplate(ctx context.Context, t *models.ListTemplate) error
+	GetListTemplate(ctx context.Context, id uuid.UUID) (*models.ListTemplate, error)
+	ListListTemplates(ctx context.Context, userID *uuid.UUID) ([]*models.ListTemplate, error)
+	UpdateListTemplate(ctx context.Context, t *models.ListTemplate) error
+	DeleteListTemplate(ctx context.Context, id uuid.UUID) error
+
+	// Workspaces and folders
+	CreateWorkspace(ctx context.Context, w *models.Workspace) error
+	GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	ListWorkspaces(ctx context.Context, userID *uuid.UUID) ([]*models.Workspace, error)
+	UpdateWorkspace(ctx context.Context, w *models.Workspace) error
+	DeleteWorkspace(ctx context.Context, id uuid.UUID) error
+	RestoreWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	AddWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (bool, error)
+	RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMember, error)
+	CreateFolder(ctx context.Context, f *models.Folder) error
+	GetFolder(ctx context.Context, id uuid.UUID) (*models.Folder, error)
+	ListFolders(ctx context.Context, workspaceID uuid.UUID) ([]*models.Folder, error)
+	UpdateFolder(ctx context.Context, f *models.Folder) error
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
+
+	// List members
+	AddListMember(ctx context.Context, listID, userID uuid.UUID) (bool, error)
+	RemoveListMember(ctx context.Context, listID, userID uuid.UUID) error
+	ListListMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error)
+	HasListAccess(ctx context.Context, listID, userID uuid.UUID) (bool, error)
 
 	// Todos
-	CreateTodo(
-		ctx context.Context, listID uuid.UUID, title, description string, dueDate *time.Time,
-	) (*models.Todo, error)
+	InsertTodo(ctx context.Context, todo *models.Todo) error
 	GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error)
 	UpdateTodo(ctx context.Context, todo *models.Todo) error
+	MoveTodo(ctx context.Context, id, listID uuid.UUID) error
 	DeleteTodo(ctx context.Context, id uuid.UUID) error
-	ListTodos(ctx context.Context, listID uuid.UUID) ([]*models.Todo, error)
+	ListTodos(ctx context.Context, listID uuid.UUID, archived bool) ([]*models.Todo, error)
+	ArchiveCompletedTodos(ctx context.Context) (int64, error)
 	ListOverdueTodos(ctx context.Context) ([]*models.Todo, error)
+
+	// Assignees
+	AddTodoAssignee(ctx context.Context, todoID, userID uuid.UUID, assignedBy *uuid.UUID) (bool, error)
+	RemoveTodoAssignee(ctx context.Context, todoID, userID uuid.UUID) (bool, error)
+	RemoveListAssignees(ctx context.Context, listID, userID uuid.UUID) ([]uuid.UUID, error)
+	ListAssignedTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error)
+
+	// Users
+	CreateUser(ctx context.Context, username, email, timeZone string) (*models.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]*models.User, error)
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+
+	// Notifications
+	ListDigestSubscribers(ctx context.Context) ([]*models.DigestSubscriber, error)
+	ListDueTodosForUser(ctx context.Context, userID uuid.UUID, before time.Time) ([]*models.Todo, error)
+	MarkDigestSent(ctx context.Context, userID uuid.UUID, day time.Time) error
+
+	// Calendar feeds
+	CreateFeedToken(ctx context.Context, userID, listID *uuid.UUID, tokenHash []byte) (*models.FeedToken, error)
+	GetFeedTokenByHash(ctx context.Context, tokenHash []byte) (*models.FeedToken, error)
+	ListFeedTokens(ctx context.Context, userID, listID *uuid.UUID) ([]*models.FeedToken, error)
+	RevokeFeedToken(ctx context.Context, id uuid.UUID) error
+	ListUserTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error)
+
+	// CalDAV
+	ListOwnedLists(ctx context.Context, ownerID uuid.UUID) ([]*models.TodoList, error)
+	ListCalendarObjects(ctx context.Context, listID uuid.UUID) ([]*models.CalendarObject, error)
+	GetCalendarObject(ctx context.Context, listID uuid.UUID, name string) (*models.CalendarObject, error)
+	SaveCalendarObject(ctx context.Context, listID, todoID uuid.UUID, name, uid string) error
+	GetCalendarSyncToken(ctx context.Context, listID uuid.UUID) (int64, error)
+	ListCalendarChanges(ctx context.Context, listID uuid.UUID, since int64) ([]*models.CalendarChange, error)
+
+	// Attachments
+	CreateAttachment(ctx context.Context, a *models.Attachment) error
+	GetAttachment(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, error)
+	ListAttachments(ctx context.Context, todoID uuid.UUID) ([]*models.Attachment, error)
+	ListUserAttachments(ctx context.Context, userID uuid.UUID) ([]*models.Attachment, error)
+	DeleteAttachment(ctx context.Context, todoID, id uuid.UUID) error
+	ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error)
+	DeleteBlobDeletion(ctx context.Context, id int64) error
+
+	// Comments
+	CreateComment(ctx context.Context, c *models.Comment) error
+	GetComment(ctx context.Context, todoID, id uuid.UUID) (*models.Comment, error)
+	ListComments(ctx context.Context, todoID uuid.UUID) ([]*models.Comment, error)
+	UpdateCommentBody(ctx context.Context, c *models.Comment, body string) error
+	DeleteComment(ctx context.Context, todoID, id uuid.UUID) error
+	ListCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]*models.CommentRevision, error)
+	SetCommentMentions(ctx context.Context, commentID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+
+	// Views
+	CreateView(ctx context.Context, v *models.View) error
+	GetView(ctx context.Context, id uuid.UUID) (*models.View, error)
+	ListViews(ctx context.Context, ownerID uuid.UUID) ([]*models.View, error)
+	UpdateView(ctx context.Context, v *models.View) error
+	DeleteView(ctx context.Context, id uuid.UUID) error
+	ListFilteredTodos(
+		ctx context.Context, userID uuid.UUID, f filter.Node, now time.Time, limit, offset int,
+	) ([]*models.Todo, error)
+
+	// Dependencies
+	LockTodoDependencies(ctx context.Context) error
+	AddTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) (bool, error)
+	RemoveTodoDependency(ctx context.Context, todoID, blockedBy uuid.UUID) (bool, error)
+	ListBlockers(ctx context.Context, todoIDs []uuid.UUID) ([]models.Dependency, error)
+	ListTodoDependencies(ctx context.Context, todoID uuid.UUID) (*models.TodoDependencies, error)
+	GetDependencyGraph(ctx context.Context, listID uuid.UUID) (*models.DependencyGraph, error)
+
+	// Board
+	CreateBoardColumn(ctx context.Context, c *models.BoardColumn) error
+	GetBoardColumn(ctx context.Context, id uuid.UUID) (*models.BoardColumn, error)
+	LockBoardColumn(ctx context.Context, id uuid.UUID) (*models.BoardColumn, error)
+	ListBoardColumns(ctx context.Context, listID uuid.UUID) ([]*models.BoardColumn, error)
+	UpdateBoardColumn(ctx context.Context, c *models.BoardColumn) error
+	SetBoardColumnPositions(ctx context.Context, listID uuid.UUID, ids []uuid.UUID) error
+	DeleteBoardColumn(ctx context.Context, id uuid.UUID) error
+	ListColumnTodoIDs(ctx context.Context, listID uuid.UUID, columnID *uuid.UUID) ([]uuid.UUID, error)
+	PlaceTodos(ctx context.Context, columnID *uuid.UUID, ids []uuid.UUID) error
+
+	// Agenda
+	ListAgendaTodos(ctx context.Context, userID uuid.UUID, today models.Date) ([]*models.Todo, error)
+
+	// Stats
+	ListStats(ctx context.Context, q models.StatsQuery) ([]*models.StatsRow, error)
+	ListBurndown(ctx context.Context, listID uuid.UUID, from, to models.Date, timeZone string) ([]*models.BurndownDay, error)
+	CountTodos(ctx context.Context) (*models.TodoCounts, error)
+
+	// Events
+	CreateEvent(ctx context.Context, e *models.Event) error
+	ClaimEvents(ctx context.Context, limit int) ([]*models.Event, error)
+
+	// History and backups
+	ListUserTodoChanges(ctx context.Context, userID uuid.UUID) ([]*models.TodoChange, error)
+	InsertTodoChanges(ctx context.Context, changes []*models.TodoChange) error
+	ExistingListIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	ExistingTodoIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	ExistingAttachmentIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+
+	// Import jobs
+	CreateImportJob(ctx context.Context, job *models.ImportJob) error
+	GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	FindImportJob(ctx context.Context, ownerID *uuid.UUID, source string, fileHash []byte) (*models.ImportJob, error)
+	ClaimImportJob(ctx context.Context, lease time.Duration) (*models.ImportJob, error)
+	HeartbeatImportJob(ctx context.Context, id uuid.UUID) error
+	UpdateImportJob(ctx context.Context, job *models.ImportJob) error
 }
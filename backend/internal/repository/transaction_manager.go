@@ -14,3 +14,16 @@ type TransactionManager interface {
 	// WithTransaction executes the given function within a transaction
 	WithTransaction(ctx context.Context, fn TxFn) error
 }
+
+// TxOutcome is how a transaction ended.
+type TxOutcome string
+
+const (
+	TxCommitted  TxOutcome = "commit"
+	TxRolledBack TxOutcome = "rollback"
+)
+
+// TxObserver is told how every transaction ended, e.g. to count them.
+type TxObserver interface {
+	TxDone(outcome TxOutcome)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// Agenda groups the open todos in the lists the calling user can access
+// into overdue, today, tomorrow, the rest of the week and later, in the
+// user's time zone. Todos that start after today are left out.
+func (s *todoService) Agenda(ctx context.Context) (*models.Agenda, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	// read operations don't need transactions
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(user.Location())
+	todos, err := s.repo.ListAgendaTodos(ctx, userID, models.DateOf(now))
+	if err != nil {
+		return nil, err
+	}
+	return buildAgenda(todos, now), nil
+}
+
+// buildAgenda groups todos by the day they need attention in now's
+// location. Weeks end on Sunday. Whether a todo is overdue is decided in its
+// own time zone, else its list owner's, as everywhere else.
+func buildAgenda(todos []*models.Todo, now time.Time) *models.Agenda {
+	loc := now.Location()
+	today := models.DateOf(now)
+	tomorrow := today.AddDays(1)
+	weekEnd := today.AddDays((7 - int(now.Weekday())) % 7)
+
+	days := make(map[*models.Todo]models.Date, len(todos))
+	for _, todo := range todos {
+		days[todo] = agendaDay(todo, loc)
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return days[todos[i]].Before(days[todos[j]])
+	})
+
+	agenda := &models.Agenda{
+		Day:      today,
+		TimeZone: loc.String(),
+		Overdue:  []*models.Todo{},
+		Today:    []*models.Todo{},
+		Tomorrow: []*models.Todo{},
+		ThisWeek: []*models.Todo{},
+		Later:    []*models.Todo{},
+	}
+	for _, todo := range todos {
+		day := days[todo]
+		switch deadline, due := todo.Deadline(todo.ListLocation(loc)); {
+		case due && deadline.Before(now):
+			agenda.Overdue = append(agenda.Overdue, todo)
+		case !today.Before(day):
+			// scheduled days that passed carry over to today
+			agenda.Today = append(agenda.Today, todo)
+		case day == tomorrow:
+			agenda.Tomorrow = append(agenda.Tomorrow, todo)
+		case !weekEnd.Before(day):
+			agenda.ThisWeek = append(agenda.ThisWeek, todo)
+		default:
+			agenda.Later = append(agenda.Later, todo)
+		}
+	}
+	return agenda
+}
+
+// agendaDay is the earlier of the day a todo is due and the day it is
+// scheduled for.
+func agendaDay(todo *models.Todo, loc *time.Location) models.Date {
+	var day *models.Date
+	switch {
+	case todo.DueOn != nil:
+		day = todo.DueOn
+	case todo.DueDate != nil:
+		d := models.DateOf(todo.DueDate.In(loc))
+		day = &d
+	}
+	if s := todo.ScheduledFor; s != nil && (day == nil || s.Before(*day)) {
+		day = s
+	}
+	if day == nil {
+		return models.Date{}
+	}
+	return *day
+}
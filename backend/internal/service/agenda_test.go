@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestBuildAgenda(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Wednesday evening
+	now := time.Date(2025, 3, 12, 20, 0, 0, 0, berlin)
+	day := func(d int) *models.Date {
+		return &models.Date{Year: 2025, Month: time.March, Day: d}
+	}
+	at := func(d, hour int) *time.Time {
+		t := time.Date(2025, 3, d, hour, 0, 0, 0, berlin)
+		return &t
+	}
+
+	missed := &models.Todo{Title: "missed", DueDate: at(12, 18)}
+	yesterday := &models.Todo{Title: "yesterday", DueOn: day(11)}
+	tonight := &models.Todo{Title: "tonight", DueDate: at(12, 22)}
+	carried := &models.Todo{Title: "carried over", ScheduledFor: day(10), DueOn: day(20)}
+	thursday := &models.Todo{Title: "thursday", DueOn: day(13)}
+	planned := &models.Todo{Title: "planned", ScheduledFor: day(13), DueOn: day(28)}
+	sunday := &models.Todo{Title: "sunday", DueDate: at(16, 9)}
+	monday := &models.Todo{Title: "monday", DueOn: day(17)}
+
+	agenda := buildAgenda([]*models.Todo{monday, sunday, planned, thursday, carried, tonight, yesterday, missed}, now)
+
+	assert.Equal(t, *day(12), agenda.Day)
+	assert.Equal(t, "Europe/Berlin", agenda.TimeZone)
+	assert.Equal(t, []*models.Todo{yesterday, missed}, agenda.Overdue)
+	assert.Equal(t, []*models.Todo{carried, tonight}, agenda.Today)
+	assert.Equal(t, []*models.Todo{planned, thursday}, agenda.Tomorrow)
+	assert.Equal(t, []*models.Todo{sunday}, agenda.ThisWeek)
+	assert.Equal(t, []*models.Todo{monday}, agenda.Later)
+}
+
+func TestBuildAgendaOnSunday(t *testing.T) {
+	now := time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)
+	monday := &models.Todo{Title: "monday", DueOn: &models.Date{Year: 2025, Month: time.March, Day: 17}}
+	tuesday := &models.Todo{Title: "tuesday", DueOn: &models.Date{Year: 2025, Month: time.March, Day: 18}}
+
+	agenda := buildAgenda([]*models.Todo{monday, tuesday}, now)
+
+	assert.Equal(t, []*models.Todo{monday}, agenda.Tomorrow)
+	assert.Empty(t, agenda.ThisWeek)
+	assert.Equal(t, []*models.Todo{tuesday}, agenda.Later)
+}
+
+func TestBuildAgendaReadsAllDayTodosInListOwnerZone(t *testing.T) {
+	// a viewer in Berlin sees a list owned by someone in New York, where it
+	// is still Tuesday
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 12, 2, 0, 0, 0, berlin)
+	tuesday := &models.Date{Year: 2025, Month: time.March, Day: 11}
+
+	shared := &models.Todo{Title: "shared", DueOn: tuesday, ListTimeZone: "America/New_York"}
+	own := &models.Todo{Title: "own", DueOn: tuesday, ListTimeZone: "Europe/Berlin"}
+
+	agenda := buildAgenda([]*models.Todo{shared, own}, now)
+
+	assert.Equal(t, []*models.Todo{own}, agenda.Overdue)
+	assert.Equal(t, []*models.Todo{shared}, agenda.Today)
+}
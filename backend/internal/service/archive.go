@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// SetListArchived archives a list, hiding it from ListLists unless archived
+// lists are asked for, or brings it back.
+func (s *todoService) SetListArchived(ctx context.Context, id uuid.UUID, archived bool) (*models.TodoList, error) {
+	var list *models.TodoList
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.repo.SetListArchived(ctx, id, archived); err != nil {
+			return err
+		}
+		var err error
+		list, err = s.repo.GetList(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SetListAutoArchive has todos of a list archived once they have been
+// completed for the given number of days, or never when days is nil.
+func (s *todoService) SetListAutoArchive(ctx context.Context, id uuid.UUID, days *int) (*models.TodoList, error) {
+	var list *models.TodoList
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.repo.SetListAutoArchive(ctx, id, days); err != nil {
+			return err
+		}
+		var err error
+		list, err = s.repo.GetList(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ArchiveCompletedTodos applies the auto-archive policies of all lists and
+// returns the number of todos it archived.
+func (s *todoService) ArchiveCompletedTodos(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		n, err = s.repo.ArchiveCompletedTodos(ctx)
+		return err
+	})
+	return n, err
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// AddListMember shares a list with a user, giving them access to its todos.
+// Only the owner of the list or of its workspace can share it.
+func (s *todoService) AddListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.checkCanShareList(ctx, listID); err != nil {
+			return err
+		}
+		if _, err := s.repo.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		_, err := s.repo.AddListMember(ctx, listID, userID)
+		return err
+	})
+}
+
+// RemoveListMember stops sharing a list with a user. The user is unassigned
+// from the todos of the list they can no longer access. Only the owner of
+// the list or of its workspace can do so.
+func (s *todoService) RemoveListMember(ctx context.Context, listID, userID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.checkCanShareList(ctx, listID); err != nil {
+			return err
+		}
+		if err := s.repo.RemoveListMember(ctx, listID, userID); err != nil {
+			return err
+		}
+		return s.unassignWithoutAccess(ctx, listID, userID)
+	})
+}
+
+// checkCanShareList returns ErrNoUser for requests without a user and
+// ErrNoListAccess unless the caller owns the list or the workspace it is
+// in. Lists without an owner, which every user can access, can be shared by
+// every user.
+func (s *todoService) checkCanShareList(ctx context.Context, listID uuid.UUID) error {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return ErrNoUser
+	}
+	list, err := s.repo.GetList(ctx, listID)
+	if err != nil {
+		return err
+	}
+	if list.OwnerID == nil || *list.OwnerID == userID {
+		return nil
+	}
+	if list.WorkspaceID != nil {
+		w, err := s.repo.GetWorkspace(ctx, *list.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		if w.OwnerID != nil && *w.OwnerID == userID {
+			return nil
+		}
+	}
+	return ErrNoListAccess
+}
+
+// unassignWithoutAccess unassigns a user from the todos of a list unless
+// they can still access it.
+func (s *todoService) unassignWithoutAccess(ctx context.Context, listID, userID uuid.UUID) error {
+	access, err := s.repo.HasListAccess(ctx, listID, userID)
+	if err != nil || access {
+		return err
+	}
+
+	todoIDs, err := s.repo.RemoveListAssignees(ctx, listID, userID)
+	if err != nil {
+		return err
+	}
+	for _, todoID := range todoIDs {
+		if err := s.recordAssignment(ctx, models.EventUnassigned, todoID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *todoService) ListListMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListListMembers(ctx, listID)
+}
+
+// AssignTodo assigns a todo to a user who can access its list. Assigning
+// it again changes nothing. Requests without a user fail with ErrNoUser.
+func (s *todoService) AssignTodo(ctx context.Context, todoID, userID uuid.UUID) error {
+	if _, ok := identity.UserID(ctx); !ok {
+		return ErrNoUser
+	}
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		todo, err := s.repo.GetTodo(ctx, todoID)
+		if err != nil {
+			return fmt.Errorf("getting todo '%s': %w", todoID, err)
+		}
+		if _, err := s.repo.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		access, err := s.repo.HasListAccess(ctx, todo.ListID, userID)
+		if err != nil {
+			return err
+		}
+		if !access {
+			return ErrNoListAccess
+		}
+
+		added, err := s.repo.AddTodoAssignee(ctx, todoID, userID, actor(ctx))
+		if err != nil || !added {
+			return err
+		}
+		return s.recordAssignment(ctx, models.EventAssigned, todoID, userID)
+	})
+}
+
+// UnassignTodo removes a user from the assignees of a todo. Unassigning a
+// user the todo is not assigned to changes nothing. Requests without a user
+// fail with ErrNoUser.
+func (s *todoService) UnassignTodo(ctx context.Context, todoID, userID uuid.UUID) error {
+	if _, ok := identity.UserID(ctx); !ok {
+		return ErrNoUser
+	}
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetTodo(ctx, todoID); err != nil {
+			return fmt.Errorf("getting todo '%s': %w", todoID, err)
+		}
+		removed, err := s.repo.RemoveTodoAssignee(ctx, todoID, userID)
+		if err != nil || !removed {
+			return err
+		}
+		return s.recordAssignment(ctx, models.EventUnassigned, todoID, userID)
+	})
+}
+
+// ListAssignedTodos returns the todos assigned to a user across all lists
+// they can access.
+func (s *todoService) ListAssignedTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAssignedTodos(ctx, userID)
+}
+
+// recordAssignment writes an assignment change to the event outbox, where
+// the notifier picks it up.
+func (s *todoService) recordAssignment(ctx context.Context, typ models.EventType, todoID, userID uuid.UUID) error {
+	return s.repo.CreateEvent(ctx, &models.Event{
+		Type:    typ,
+		UserID:  userID,
+		ActorID: actor(ctx),
+		TodoID:  todoID,
+	})
+}
+
+// actor returns the calling user, if the request has one.
+func actor(ctx context.Context) *uuid.UUID {
+	if userID, ok := identity.UserID(ctx); ok {
+		return &userID
+	}
+	return nil
+}
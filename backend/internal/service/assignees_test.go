@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// memberRepo holds one list in a workspace and records the members added
+// and removed.
+type memberRepo struct {
+	repository.Repository
+	list      *models.TodoList
+	workspace *models.Workspace
+	members   map[uuid.UUID]bool
+}
+
+func (r *memberRepo) GetList(context.Context, uuid.UUID) (*models.TodoList, error) {
+	return r.list, nil
+}
+
+func (r *memberRepo) GetWorkspace(context.Context, uuid.UUID) (*models.Workspace, error) {
+	return r.workspace, nil
+}
+
+func (r *memberRepo) GetUser(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return &models.User{ID: id}, nil
+}
+
+func (r *memberRepo) AddListMember(_ context.Context, _, userID uuid.UUID) (bool, error) {
+	r.members[userID] = true
+	return true, nil
+}
+
+func (r *memberRepo) RemoveListMember(_ context.Context, _, userID uuid.UUID) error {
+	delete(r.members, userID)
+	return nil
+}
+
+func (r *memberRepo) HasListAccess(_ context.Context, _, userID uuid.UUID) (bool, error) {
+	return r.members[userID], nil
+}
+
+func (r *memberRepo) RemoveListAssignees(context.Context, uuid.UUID, uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func TestListMembersAreManagedByOwners(t *testing.T) {
+	listOwner, workspaceOwner, member, stranger := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	workspaceID := uuid.New()
+	repo := &memberRepo{
+		list:      &models.TodoList{ID: uuid.New(), OwnerID: &listOwner, WorkspaceID: &workspaceID},
+		workspace: &models.Workspace{ID: workspaceID, OwnerID: &workspaceOwner},
+		members:   map[uuid.UUID]bool{},
+	}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+	as := func(userID uuid.UUID) context.Context {
+		return identity.WithUserID(context.Background(), userID)
+	}
+
+	assert.ErrorIs(t, svc.AddListMember(as(stranger), repo.list.ID, stranger), ErrNoListAccess)
+	assert.NoError(t, svc.AddListMember(as(listOwner), repo.list.ID, member))
+	assert.ErrorIs(t, svc.AddListMember(as(member), repo.list.ID, stranger), ErrNoListAccess)
+	assert.ErrorIs(t, svc.RemoveListMember(as(stranger), repo.list.ID, member), ErrNoListAccess)
+	assert.True(t, repo.members[member])
+
+	assert.NoError(t, svc.RemoveListMember(as(workspaceOwner), repo.list.ID, member))
+	assert.False(t, repo.members[member])
+}
+
+func TestListMembersNeedAUser(t *testing.T) {
+	repo := &memberRepo{list: &models.TodoList{ID: uuid.New()}, members: map[uuid.UUID]bool{}}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+	someone := uuid.New()
+
+	// not even lists without an owner can be shared anonymously
+	assert.ErrorIs(t, svc.AddListMember(context.Background(), repo.list.ID, someone), ErrNoUser)
+	assert.ErrorIs(t, svc.RemoveListMember(context.Background(), repo.list.ID, someone), ErrNoUser)
+	assert.ErrorIs(t, svc.AssignTodo(context.Background(), uuid.New(), someone), ErrNoUser)
+	assert.ErrorIs(t, svc.UnassignTodo(context.Background(), uuid.New(), someone), ErrNoUser)
+	assert.Empty(t, repo.members)
+
+	assert.NoError(t, svc.AddListMember(identity.WithUserID(context.Background(), someone), repo.list.ID, someone))
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/logging"
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// MaxAttachmentSize is the largest attachment accepted, in bytes.
+const MaxAttachmentSize = 25 << 20
+
+// blobCleanupBatch is the number of blobs removed per CleanupBlobs call.
+const blobCleanupBatch = 100
+
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":                true,
+	"image/jpeg":               true,
+	"image/gif":                true,
+	"image/webp":               true,
+	"application/pdf":          true,
+	"application/json":         true,
+	"application/zip":          true,
+	"text/plain":               true,
+	"text/markdown":            true,
+	"text/csv":                 true,
+	"application/msword":       true,
+	"application/vnd.ms-excel": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         true,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": true,
+}
+
+// AddAttachment stores the content read from r as an attachment of a
+// todo. The media type is sniffed from the content; the declared
+// contentType only decides between types that sniffing cannot tell apart,
+// such as the different zip based office formats. When checksum, a hex
+// SHA-256, is given the content must match it.
+func (s *todoService) AddAttachment(
+	ctx context.Context, todoID uuid.UUID, filename, contentType string, size int64, checksum string, r io.Reader,
+) (*models.Attachment, error) {
+	if _, err := s.repo.GetTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+	if size > MaxAttachmentSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	br := bufio.NewReaderSize(r, 512)
+	head, _ := br.Peek(512)
+	mediaType := attachmentMediaType(contentType, http.DetectContentType(head))
+	if !allowedAttachmentTypes[mediaType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+
+	a := &models.Attachment{
+		ID:          uuid.New(),
+		TodoID:      todoID,
+		Filename:    attachmentFilename(filename),
+		ContentType: mediaType,
+		Size:        size,
+	}
+	a.StorageKey = fmt.Sprintf("attachments/%s/%s", todoID, a.ID)
+
+	h := sha256.New()
+	if err := s.blobs.Put(ctx, a.StorageKey, io.TeeReader(br, h), size, mediaType); err != nil {
+		return nil, fmt.Errorf("storing attachment: %w", err)
+	}
+	a.SHA256 = hex.EncodeToString(h.Sum(nil))
+	if checksum != "" && !strings.EqualFold(checksum, a.SHA256) {
+		s.deleteBlob(ctx, a.StorageKey)
+		return nil, ErrChecksumMismatch
+	}
+
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.CreateAttachment(ctx, a)
+	})
+	if err != nil {
+		s.deleteBlob(ctx, a.StorageKey)
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *todoService) ListAttachments(ctx context.Context, todoID uuid.UUID) ([]*models.Attachment, error) {
+	// read operations don't need transactions
+	return s.repo.ListAttachments(ctx, todoID)
+}
+
+// OpenAttachment returns an attachment together with its content, which
+// the caller must close.
+func (s *todoService) OpenAttachment(
+	ctx context.Context, todoID, id uuid.UUID,
+) (*models.Attachment, io.ReadCloser, error) {
+	a, err := s.repo.GetAttachment(ctx, todoID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobs.Get(ctx, a.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening attachment '%s': %w", id, err)
+	}
+	return a, content, nil
+}
+
+// DeleteAttachment removes an attachment. Its blob is removed later by
+// CleanupBlobs.
+func (s *todoService) DeleteAttachment(ctx context.Context, todoID, id uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.DeleteAttachment(ctx, todoID, id)
+	})
+}
+
+// CleanupBlobs removes the blobs of deleted attachments, including those
+// of deleted todos and lists, and reports how many it removed. Blobs that
+// cannot be removed stay queued.
+func (s *todoService) CleanupBlobs(ctx context.Context) (int, error) {
+	deletions, err := s.repo.ListBlobDeletions(ctx, blobCleanupBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	removed := 0
+	for _, d := range deletions {
+		if err := s.blobs.Delete(ctx, d.StorageKey); err != nil {
+			errs = append(errs, fmt.Errorf("deleting blob '%s': %w", d.StorageKey, err))
+			continue
+		}
+		if err := s.repo.DeleteBlobDeletion(ctx, d.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+// deleteBlob removes a blob that never got an attachment row.
+func (s *todoService) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "delete orphaned blob", "key", key, "error", err)
+	}
+}
+
+// attachmentMediaType prefers the sniffed type unless sniffing only found
+// a generic one.
+func attachmentMediaType(declared, sniffed string) string {
+	sniffed, _, _ = mime.ParseMediaType(sniffed)
+	declared, _, _ = mime.ParseMediaType(declared)
+	switch sniffed {
+	case "application/octet-stream", "text/plain", "application/zip":
+		if declared != "" && declared != "application/octet-stream" {
+			return declared
+		}
+	}
+	return sniffed
+}
+
+func attachmentFilename(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	for utf8.RuneCountInString(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentMediaType(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	docx := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+	tests := []struct {
+		name     string
+		declared string
+		content  string
+		want     string
+	}{
+		{"sniffed wins", "application/pdf", png, "image/png"},
+		{"declared refines zip", docx, "PK\x03\x04rest", docx},
+		{"declared refines text", "text/markdown; charset=utf-8", "# Notes", "text/markdown"},
+		{"generic declaration", "application/octet-stream", "# Notes", "text/plain"},
+		{"nothing declared", "", "\x00\x01\x02", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attachmentMediaType(tt.declared, http.DetectContentType([]byte(tt.content)))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAttachmentFilename(t *testing.T) {
+	assert.Equal(t, "report.pdf", attachmentFilename("../../etc/report.pdf"))
+	assert.Equal(t, "shot.png", attachmentFilename(`C:\Users\me\shot.png`))
+	assert.Equal(t, "attachment", attachmentFilename("  "))
+	assert.Equal(t, 255, len([]rune(attachmentFilename(strings.Repeat("ä", 300)))))
+}
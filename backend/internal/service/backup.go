@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/backup"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// BackupUser collects everything stored for a user: the lists they own
+// with their todos, change history and attachment metadata.
+func (s *todoService) BackupUser(ctx context.Context, userID uuid.UUID) (*models.AccountData, error) {
+	data := &models.AccountData{}
+	// read in one transaction for a consistent snapshot
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if data.User, err = s.repo.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		if data.Lists, err = s.repo.ListOwnedLists(ctx, userID); err != nil {
+			return err
+		}
+		if data.Todos, err = s.repo.ListUserTodos(ctx, userID); err != nil {
+			return err
+		}
+		if data.History, err = s.repo.ListUserTodoChanges(ctx, userID); err != nil {
+			return err
+		}
+		data.Attachments, err = s.repo.ListUserAttachments(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	dropUnlisted(data)
+	return data, nil
+}
+
+// dropUnlisted leaves out the todos, history and attachments of lists that
+// are not part of data, as an archive with them cannot be restored.
+func dropUnlisted(data *models.AccountData) {
+	lists := make(map[uuid.UUID]bool, len(data.Lists))
+	for _, l := range data.Lists {
+		lists[l.ID] = true
+	}
+	data.Todos = slices.DeleteFunc(data.Todos, func(t *models.Todo) bool { return !lists[t.ListID] })
+	data.History = slices.DeleteFunc(data.History, func(c *models.TodoChange) bool { return !lists[c.ListID] })
+
+	todos := make(map[uuid.UUID]bool, len(data.Todos))
+	for _, t := range data.Todos {
+		todos[t.ID] = true
+	}
+	data.Attachments = slices.DeleteFunc(data.Attachments, func(a *models.Attachment) bool { return !todos[a.TodoID] })
+}
+
+// RestoreUser recreates the lists, todos, history and attachments of a
+// backup for a user in a single transaction. With remapIDs every record
+// gets a new ID; otherwise the IDs of the backup are kept and the restore
+// fails with ErrIDConflict if any of them is taken. Records the API would
+// not accept fail the restore with backup.ErrInvalidArchive. Attachments
+// keep pointing at the blobs of the backup.
+func (s *todoService) RestoreUser(
+	ctx context.Context, userID uuid.UUID, data *models.AccountData, remapIDs bool,
+) (*models.RestoreResult, error) {
+	if err := validateBackup(data); err != nil {
+		return nil, err
+	}
+	result := &models.RestoreResult{RemappedIDs: remapIDs}
+
+	ids := make(map[uuid.UUID]uuid.UUID)
+	id := func(old uuid.UUID) uuid.UUID {
+		if !remapIDs {
+			return old
+		}
+		if id, ok := ids[old]; ok {
+			return id
+		}
+		ids[old] = uuid.New()
+		return ids[old]
+	}
+
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		if !remapIDs {
+			if err := s.checkRestoreIDs(ctx, data); err != nil {
+				return err
+			}
+		}
+
+		// history goes first so the entries logged for the restored todos
+		// come after it
+		history := make([]*models.TodoChange, 0, len(data.History))
+		for _, c := range data.History {
+			history = append(history, &models.TodoChange{
+				ListID:    id(c.ListID),
+				TodoID:    id(c.TodoID),
+				Deleted:   c.Deleted,
+				Status:    c.Status,
+				ChangedAt: c.ChangedAt,
+			})
+		}
+		if err := s.repo.InsertTodoChanges(ctx, history); err != nil {
+			return err
+		}
+		result.History = len(history)
+
+		for _, l := range data.Lists {
+			list := *l
+			list.ID = id(l.ID)
+			list.OwnerID = &userID
+			loc, err := s.restoreLocation(ctx, models.ListLocation{WorkspaceID: l.WorkspaceID, FolderID: l.FolderID})
+			if err != nil {
+				return fmt.Errorf("restoring list '%s': %w", l.ID, err)
+			}
+			list.WorkspaceID, list.FolderID = loc.WorkspaceID, loc.FolderID
+			if err := s.repo.InsertList(ctx, &list); err != nil {
+				return fmt.Errorf("restoring list '%s': %w", l.ID, err)
+			}
+			result.Lists++
+		}
+		for _, t := range data.Todos {
+			todo := *t
+			todo.ID = id(t.ID)
+			todo.ListID = id(t.ListID)
+			// board columns are not part of the archive
+			todo.ColumnID, todo.Rank = nil, 0
+			if err := s.repo.InsertTodo(ctx, &todo); err != nil {
+				return fmt.Errorf("restoring todo '%s': %w", t.ID, err)
+			}
+			result.Todos++
+		}
+		for _, a := range data.Attachments {
+			attachment := *a
+			attachment.ID = id(a.ID)
+			attachment.TodoID = id(a.TodoID)
+			if err := s.repo.CreateAttachment(ctx, &attachment); err != nil {
+				return fmt.Errorf("restoring attachment '%s': %w", a.ID, err)
+			}
+			result.Attachments++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateBackup checks the records of a backup as the API checks what
+// clients send. The checksums of an archive do not prove where it came
+// from, and a todo with an unknown time zone would break every query that
+// evaluates its due date.
+func validateBackup(data *models.AccountData) error {
+	for _, l := range data.Lists {
+		switch {
+		case strings.TrimSpace(l.Name) == "":
+			return fmt.Errorf("%w: list '%s' has no name", backup.ErrInvalidArchive, l.ID)
+		case l.AutoArchiveDays != nil && *l.AutoArchiveDays < 1:
+			return fmt.Errorf("%w: list '%s' auto-archives after %d days", backup.ErrInvalidArchive, l.ID, *l.AutoArchiveDays)
+		}
+	}
+	for _, t := range data.Todos {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("%w: todo '%s': %w", backup.ErrInvalidArchive, t.ID, err)
+		}
+	}
+	for _, a := range data.Attachments {
+		if a.Filename == "" || a.Size < 0 {
+			return fmt.Errorf("%w: attachment '%s' has no file name or a negative size", backup.ErrInvalidArchive, a.ID)
+		}
+	}
+	return nil
+}
+
+// restoreLocation keeps a restored list in its workspace and folder as long
+// as they still exist. A list whose folder is gone stays in the workspace,
+// and one whose workspace is gone is restored outside of any.
+func (s *todoService) restoreLocation(ctx context.Context, loc models.ListLocation) (models.ListLocation, error) {
+	resolved, err := s.resolveLocation(ctx, loc)
+	if errors.Is(err, repository.ErrFolderNotFound) {
+		loc.FolderID = nil
+		resolved, err = s.resolveLocation(ctx, loc)
+	}
+	if errors.Is(err, repository.ErrWorkspaceNotFound) {
+		return models.ListLocation{}, nil
+	}
+	return resolved, err
+}
+
+func (s *todoService) checkRestoreIDs(ctx context.Context, data *models.AccountData) error {
+	listIDs := make([]uuid.UUID, 0, len(data.Lists))
+	for _, l := range data.Lists {
+		listIDs = append(listIDs, l.ID)
+	}
+	todoIDs := make([]uuid.UUID, 0, len(data.Todos))
+	for _, t := range data.Todos {
+		todoIDs = append(todoIDs, t.ID)
+	}
+	attachmentIDs := make([]uuid.UUID, 0, len(data.Attachments))
+	for _, a := range data.Attachments {
+		attachmentIDs = append(attachmentIDs, a.ID)
+	}
+
+	lists, err := s.repo.ExistingListIDs(ctx, listIDs)
+	if err != nil {
+		return err
+	}
+	todos, err := s.repo.ExistingTodoIDs(ctx, todoIDs)
+	if err != nil {
+		return err
+	}
+	attachments, err := s.repo.ExistingAttachmentIDs(ctx, attachmentIDs)
+	if err != nil {
+		return err
+	}
+	if len(lists) > 0 || len(todos) > 0 || len(attachments) > 0 {
+		return fmt.Errorf(
+			"%w: %d lists, %d todos and %d attachments of the backup already exist",
+			repository.ErrIDConflict, len(lists), len(todos), len(attachments),
+		)
+	}
+	return nil
+}
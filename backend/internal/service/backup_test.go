@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/backup"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// backupRepo returns the records of data for a user and keeps those
+// restored. Of workspaces and folders, only those in workspaces exist.
+type backupRepo struct {
+	repository.Repository
+	data       *models.AccountData
+	restored   models.AccountData
+	workspaces map[uuid.UUID]bool
+}
+
+func (r *backupRepo) GetWorkspace(_ context.Context, id uuid.UUID) (*models.Workspace, error) {
+	if !r.workspaces[id] {
+		return nil, repository.ErrWorkspaceNotFound
+	}
+	return &models.Workspace{ID: id}, nil
+}
+
+func (r *backupRepo) GetFolder(context.Context, uuid.UUID) (*models.Folder, error) {
+	return nil, repository.ErrFolderNotFound
+}
+
+func (r *backupRepo) GetUser(context.Context, uuid.UUID) (*models.User, error) {
+	return r.data.User, nil
+}
+
+func (r *backupRepo) ListOwnedLists(context.Context, uuid.UUID) ([]*models.TodoList, error) {
+	return r.data.Lists, nil
+}
+
+func (r *backupRepo) ListUserTodos(context.Context, uuid.UUID) ([]*models.Todo, error) {
+	return r.data.Todos, nil
+}
+
+func (r *backupRepo) ListUserTodoChanges(context.Context, uuid.UUID) ([]*models.TodoChange, error) {
+	return r.data.History, nil
+}
+
+func (r *backupRepo) ListUserAttachments(context.Context, uuid.UUID) ([]*models.Attachment, error) {
+	return r.data.Attachments, nil
+}
+
+func (r *backupRepo) InsertTodoChanges(_ context.Context, changes []*models.TodoChange) error {
+	r.restored.History = append(r.restored.History, changes...)
+	return nil
+}
+
+func (r *backupRepo) InsertList(_ context.Context, list *models.TodoList) error {
+	r.restored.Lists = append(r.restored.Lists, list)
+	return nil
+}
+
+func (r *backupRepo) InsertTodo(_ context.Context, todo *models.Todo) error {
+	r.restored.Todos = append(r.restored.Todos, todo)
+	return nil
+}
+
+func (r *backupRepo) CreateAttachment(_ context.Context, a *models.Attachment) error {
+	r.restored.Attachments = append(r.restored.Attachments, a)
+	return nil
+}
+
+func TestBackupRestoreWithDeletedWorkspace(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Username: "ada", TimeZone: "UTC"}
+	workspaceID := uuid.New()
+	home := &models.TodoList{ID: uuid.New(), Name: "Home", OwnerID: &user.ID, CreatedAt: now}
+	// the list in a deleted workspace is not listed, its todo still is
+	deleted := &models.TodoList{ID: uuid.New(), Name: "Old", OwnerID: &user.ID, WorkspaceID: &workspaceID}
+	kept := &models.Todo{ID: uuid.New(), ListID: home.ID, Title: "Water plants", CreatedAt: now}
+	orphan := &models.Todo{ID: uuid.New(), ListID: deleted.ID, Title: "Gone", CreatedAt: now}
+	repo := &backupRepo{data: &models.AccountData{
+		User:  user,
+		Lists: []*models.TodoList{home},
+		Todos: []*models.Todo{kept, orphan},
+		History: []*models.TodoChange{
+			{Seq: 1, ListID: home.ID, TodoID: kept.ID, ChangedAt: now},
+			{Seq: 2, ListID: deleted.ID, TodoID: orphan.ID, ChangedAt: now},
+		},
+		Attachments: []*models.Attachment{
+			{ID: uuid.New(), TodoID: kept.ID, Filename: "plan.pdf", CreatedAt: now},
+			{ID: uuid.New(), TodoID: orphan.ID, Filename: "old.pdf", CreatedAt: now},
+		},
+	}}
+	svc := NewTodoService(repo, fakeTxManager{}, nil)
+
+	data, err := svc.BackupUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, backup.Write(&buf, data, now))
+	read, _, err := backup.Read(&buf)
+	require.NoError(t, err)
+
+	result, err := svc.RestoreUser(context.Background(), user.ID, read, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Lists)
+	assert.Equal(t, 1, result.Todos)
+	assert.Equal(t, 1, result.History)
+	assert.Equal(t, 1, result.Attachments)
+	require.Len(t, repo.restored.Todos, 1)
+	assert.Equal(t, "Water plants", repo.restored.Todos[0].Title)
+	assert.Equal(t, repo.restored.Lists[0].ID, repo.restored.Todos[0].ListID)
+}
+
+func TestRestoreKeepsArchiveAndPlacement(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Username: "ada", TimeZone: "UTC"}
+	live, gone, columnID := uuid.New(), uuid.New(), uuid.New()
+	folderID := uuid.New()
+	days := 7
+	team := &models.TodoList{
+		ID: uuid.New(), Name: "Team", WorkspaceID: &live, FolderID: &folderID, AutoArchiveDays: &days,
+	}
+	old := &models.TodoList{ID: uuid.New(), Name: "Old", WorkspaceID: &gone, ArchivedAt: &now}
+	done := &models.Todo{ID: uuid.New(), ListID: team.ID, Title: "Ship", ColumnID: &columnID, Rank: 3, ArchivedAt: &now}
+	repo := &backupRepo{data: &models.AccountData{User: user}, workspaces: map[uuid.UUID]bool{live: true}}
+	svc := NewTodoService(repo, fakeTxManager{}, nil)
+
+	_, err := svc.RestoreUser(context.Background(), user.ID, &models.AccountData{
+		User:  user,
+		Lists: []*models.TodoList{team, old},
+		Todos: []*models.Todo{done},
+	}, true)
+	require.NoError(t, err)
+
+	require.Len(t, repo.restored.Lists, 2)
+	// the folder is gone, the workspace is not
+	assert.Equal(t, &live, repo.restored.Lists[0].WorkspaceID)
+	assert.Nil(t, repo.restored.Lists[0].FolderID)
+	assert.Equal(t, &days, repo.restored.Lists[0].AutoArchiveDays)
+	assert.Nil(t, repo.restored.Lists[1].WorkspaceID)
+	assert.Equal(t, &now, repo.restored.Lists[1].ArchivedAt)
+
+	require.Len(t, repo.restored.Todos, 1)
+	assert.Equal(t, &now, repo.restored.Todos[0].ArchivedAt)
+	assert.Nil(t, repo.restored.Todos[0].ColumnID)
+	assert.Zero(t, repo.restored.Todos[0].Rank)
+}
+
+func TestRestoreRejectsInvalidRecords(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Username: "ada", TimeZone: "UTC"}
+	list := &models.TodoList{ID: uuid.New(), Name: "Home"}
+	repo := &backupRepo{data: &models.AccountData{User: user}}
+	svc := NewTodoService(repo, fakeTxManager{}, nil)
+
+	for name, todo := range map[string]*models.Todo{
+		"time zone":  {Title: "Water plants", TimeZone: "Mars/Olympus"},
+		"title":      {Title: ""},
+		"priority":   {Title: "Water plants", Priority: models.PriorityHigh + 1},
+		"recurrence": {Title: "Water plants", Recurrence: "FREQ=SOMETIMES"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			todo.ID, todo.ListID = uuid.New(), list.ID
+			_, err := svc.RestoreUser(context.Background(), user.ID, &models.AccountData{
+				User:  user,
+				Lists: []*models.TodoList{list},
+				Todos: []*models.Todo{todo},
+			}, true)
+			assert.ErrorIs(t, err, backup.ErrInvalidArchive)
+		})
+	}
+	assert.Empty(t, repo.restored.Lists)
+	assert.Empty(t, repo.restored.Todos)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// CreateBoardColumn adds a column at the end of a list's board. A nil
+// wipLimit leaves the column unlimited.
+func (s *todoService) CreateBoardColumn(
+	ctx context.Context, listID uuid.UUID, name string, wipLimit *int,
+) (*models.BoardColumn, error) {
+	c := &models.BoardColumn{ListID: listID, Name: name, WIPLimit: wipLimit}
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetList(ctx, listID); err != nil {
+			return err
+		}
+		return s.repo.CreateBoardColumn(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *todoService) ListBoardColumns(ctx context.Context, listID uuid.UUID) ([]*models.BoardColumn, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListBoardColumns(ctx, listID)
+}
+
+// UpdateBoardColumn renames a column and sets its WIP limit. With a
+// position, the column is also moved to that index among the list's
+// columns. Lowering the limit below the number of todos in the column only
+// keeps more todos from moving in.
+func (s *todoService) UpdateBoardColumn(
+	ctx context.Context, listID, columnID uuid.UUID, name string, wipLimit, position *int,
+) (*models.BoardColumn, error) {
+	var c *models.BoardColumn
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if c, err = s.listColumn(ctx, listID, columnID); err != nil {
+			return err
+		}
+		c.Name, c.WIPLimit = name, wipLimit
+		if err := s.repo.UpdateBoardColumn(ctx, c); err != nil {
+			return err
+		}
+		if position == nil {
+			return nil
+		}
+
+		columns, err := s.repo.ListBoardColumns(ctx, listID)
+		if err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(columns))
+		for i, col := range columns {
+			ids[i] = col.ID
+		}
+		ids = insertAt(ids, columnID, position)
+		if err := s.repo.SetBoardColumnPositions(ctx, listID, ids); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if id == columnID {
+				c.Position = i
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteBoardColumn removes a column from a list's board. Its todos are
+// kept but taken off the board.
+func (s *todoService) DeleteBoardColumn(ctx context.Context, listID, columnID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.listColumn(ctx, listID, columnID); err != nil {
+			return err
+		}
+		return s.repo.DeleteBoardColumn(ctx, columnID)
+	})
+}
+
+// GetBoard returns the columns of a list with their todos in rank order.
+func (s *todoService) GetBoard(ctx context.Context, listID uuid.UUID) (*models.Board, error) {
+	// read operations don't need transactions
+	columns, err := s.ListBoardColumns(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	todos, err := s.repo.ListTodos(ctx, listID, false)
+	if err != nil {
+		return nil, err
+	}
+	return buildBoard(listID, columns, todos), nil
+}
+
+// MoveTodoToColumn puts a todo into a column of its list at a position, the
+// end when position is nil, or takes it off the board when columnID is nil.
+// Moving into a column that is at its WIP limit fails with
+// ErrWIPLimitReached; moves within a column are always allowed.
+func (s *todoService) MoveTodoToColumn(ctx context.Context, todoID uuid.UUID, columnID *uuid.UUID, position *int) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		todo, err := s.repo.GetTodo(ctx, todoID)
+		if err != nil {
+			return err
+		}
+
+		var column *models.BoardColumn
+		if columnID != nil {
+			if column, err = s.repo.LockBoardColumn(ctx, *columnID); err != nil {
+				return err
+			}
+			if column.ListID != todo.ListID {
+				return ErrColumnNotInList
+			}
+		}
+
+		ids, err := s.repo.ListColumnTodoIDs(ctx, todo.ListID, columnID)
+		if err != nil {
+			return err
+		}
+		ids = insertAt(ids, todoID, position)
+		if column != nil && column.WIPLimit != nil && !sameID(todo.ColumnID, columnID) &&
+			len(ids) > *column.WIPLimit {
+			return ErrWIPLimitReached
+		}
+		return s.repo.PlaceTodos(ctx, columnID, ids)
+	})
+}
+
+// listColumn returns a column of a list, ErrColumnNotFound when it belongs
+// to another one.
+func (s *todoService) listColumn(ctx context.Context, listID, columnID uuid.UUID) (*models.BoardColumn, error) {
+	c, err := s.repo.GetBoardColumn(ctx, columnID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ListID != listID {
+		return nil, repository.ErrColumnNotFound
+	}
+	return c, nil
+}
+
+// sameID reports whether two optional IDs are both nil or equal.
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// insertAt moves id to index position of ids, clamped to the ends, or to
+// the end when position is nil. ids need not contain id yet.
+func insertAt(ids []uuid.UUID, id uuid.UUID, position *int) []uuid.UUID {
+	rest := make([]uuid.UUID, 0, len(ids)+1)
+	for _, other := range ids {
+		if other != id {
+			rest = append(rest, other)
+		}
+	}
+
+	i := len(rest)
+	if position != nil && *position < i {
+		i = max(*position, 0)
+	}
+	rest = append(rest, uuid.Nil)
+	copy(rest[i+1:], rest[i:])
+	rest[i] = id
+	return rest
+}
+
+// buildBoard sorts the todos of a list into its columns by rank. Todos in
+// no column, or in one that is not given, are unplaced.
+func buildBoard(listID uuid.UUID, columns []*models.BoardColumn, todos []*models.Todo) *models.Board {
+	board := &models.Board{
+		ListID:   listID,
+		Columns:  make([]*models.BoardColumnTodos, len(columns)),
+		Unplaced: []*models.Todo{},
+	}
+	byID := make(map[uuid.UUID]*models.BoardColumnTodos, len(columns))
+	for i, c := range columns {
+		board.Columns[i] = &models.BoardColumnTodos{BoardColumn: c, Todos: []*models.Todo{}}
+		byID[c.ID] = board.Columns[i]
+	}
+
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Rank != todos[j].Rank {
+			return todos[i].Rank < todos[j].Rank
+		}
+		return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+	})
+	for _, todo := range todos {
+		if todo.ColumnID != nil {
+			if c, ok := byID[*todo.ColumnID]; ok {
+				c.Todos = append(c.Todos, todo)
+				continue
+			}
+		}
+		board.Unplaced = append(board.Unplaced, todo)
+	}
+	return board
+}
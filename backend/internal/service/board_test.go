@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestInsertAt(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	pos := func(i int) *int { return &i }
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		position *int
+		want     []uuid.UUID
+	}{
+		{"new at end", d, nil, []uuid.UUID{a, b, c, d}},
+		{"new at front", d, pos(0), []uuid.UUID{d, a, b, c}},
+		{"new in middle", d, pos(2), []uuid.UUID{a, b, d, c}},
+		{"past the end", d, pos(10), []uuid.UUID{a, b, c, d}},
+		{"negative", d, pos(-1), []uuid.UUID{d, a, b, c}},
+		{"existing down", a, pos(2), []uuid.UUID{b, c, a}},
+		{"existing up", c, pos(0), []uuid.UUID{c, a, b}},
+		{"existing to end", a, nil, []uuid.UUID{b, c, a}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := []uuid.UUID{a, b, c}
+			assert.Equal(t, tt.want, insertAt(ids, tt.id, tt.position))
+			assert.Equal(t, []uuid.UUID{a, b, c}, ids)
+		})
+	}
+}
+
+func TestBuildBoard(t *testing.T) {
+	listID := uuid.New()
+	todoCol := &models.BoardColumn{ID: uuid.New(), ListID: listID, Name: "To do"}
+	doneCol := &models.BoardColumn{ID: uuid.New(), ListID: listID, Name: "Done", Position: 1}
+	gone := uuid.New()
+
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	todo := func(title string, column *uuid.UUID, rank int) *models.Todo {
+		created = created.Add(time.Minute)
+		return &models.Todo{ID: uuid.New(), ListID: listID, Title: title, ColumnID: column, Rank: rank, CreatedAt: created}
+	}
+	second := todo("second", &todoCol.ID, 1)
+	first := todo("first", &todoCol.ID, 0)
+	loose := todo("loose", nil, 0)
+	orphan := todo("orphan", &gone, 0)
+	shipped := todo("shipped", &doneCol.ID, 0)
+	tied := todo("tied", &doneCol.ID, 0)
+
+	board := buildBoard(listID, []*models.BoardColumn{todoCol, doneCol},
+		[]*models.Todo{second, first, loose, orphan, shipped, tied})
+
+	assert.Equal(t, listID, board.ListID)
+	require.Len(t, board.Columns, 2)
+	assert.Equal(t, todoCol, board.Columns[0].BoardColumn)
+	assert.Equal(t, []*models.Todo{first, second}, board.Columns[0].Todos)
+	assert.Equal(t, []*models.Todo{shipped, tied}, board.Columns[1].Todos)
+	assert.Equal(t, []*models.Todo{loose, orphan}, board.Unplaced)
+
+	empty := buildBoard(listID, []*models.BoardColumn{todoCol}, nil)
+	assert.NotNil(t, empty.Columns[0].Todos)
+	assert.NotNil(t, empty.Unplaced)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (s *todoService) ListOwnedLists(ctx context.Context, ownerID uuid.UUID) ([]*models.TodoList, error) {
+	// read operations don't need transactions
+	return s.repo.ListOwnedLists(ctx, ownerID)
+}
+
+func (s *todoService) ListCalendarObjects(ctx context.Context, listID uuid.UUID) ([]*models.CalendarObject, error) {
+	// read operations don't need transactions
+	return s.repo.ListCalendarObjects(ctx, listID)
+}
+
+func (s *todoService) GetCalendarObject(
+	ctx context.Context, listID uuid.UUID, name string,
+) (*models.CalendarObject, error) {
+	// read operations don't need transactions
+	return s.repo.GetCalendarObject(ctx, listID, name)
+}
+
+// CreateCalendarObject creates the todo a CalDAV client put at a new
+// resource name in a list, and remembers the name and UID the client knows
+// it by. Both happen in one transaction, so that a failure leaves nothing
+// behind for a retry to duplicate.
+func (s *todoService) CreateCalendarObject(
+	ctx context.Context, listID uuid.UUID, name, uid string, todo *models.Todo,
+) error {
+	todo.ListID = listID
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		schedule := models.Schedule{DueDate: todo.DueDate, DueOn: todo.DueOn}
+		if err := s.SetTodoSchedule(ctx, todo, schedule); err != nil {
+			return err
+		}
+		if err := s.repo.InsertTodo(ctx, todo); err != nil {
+			return err
+		}
+		return s.repo.SaveCalendarObject(ctx, listID, todo.ID, name, uid)
+	})
+}
+
+func (s *todoService) GetCalendarSyncToken(ctx context.Context, listID uuid.UUID) (int64, error) {
+	// read operations don't need transactions
+	return s.repo.GetCalendarSyncToken(ctx, listID)
+}
+
+func (s *todoService) ListCalendarChanges(
+	ctx context.Context, listID uuid.UUID, since int64,
+) ([]*models.CalendarChange, error) {
+	// read operations don't need transactions
+	return s.repo.ListCalendarChanges(ctx, listID, since)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// CloneList copies a list with its todos, its board columns and the
+// dependencies between its todos into a new list next to it, owned by the
+// calling user. The copy is named opts.Name, or after the list with
+// "(copy)" appended.
+func (s *todoService) CloneList(
+	ctx context.Context, listID uuid.UUID, opts models.CloneOptions,
+) (*models.ListWithTodos, error) {
+	var clone *models.ListWithTodos
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		src, err := s.repo.GetList(ctx, listID)
+		if err != nil {
+			return err
+		}
+		todos, err := s.repo.ListTodos(ctx, listID, true)
+		if err != nil {
+			return err
+		}
+		columns, err := s.repo.ListBoardColumns(ctx, listID)
+		if err != nil {
+			return err
+		}
+		graph, err := s.repo.GetDependencyGraph(ctx, listID)
+		if err != nil {
+			return err
+		}
+		loc, err := s.ownerLocation(ctx, src)
+		if err != nil {
+			return err
+		}
+
+		name := opts.Name
+		if name == "" {
+			name = src.Name + " (copy)"
+		}
+		list, err := s.CreateList(ctx, name, models.ListLocation{WorkspaceID: src.WorkspaceID, FolderID: src.FolderID})
+		if err != nil {
+			return err
+		}
+
+		todoIDs := make(map[uuid.UUID]uuid.UUID, len(todos))
+		for _, todo := range todos {
+			c := cloneTodo(todo, list.ID, opts, loc)
+			if err := s.repo.InsertTodo(ctx, c); err != nil {
+				return err
+			}
+			todoIDs[todo.ID] = c.ID
+		}
+
+		for _, col := range columns {
+			c := &models.BoardColumn{ListID: list.ID, Name: col.Name, WIPLimit: col.WIPLimit}
+			if err := s.repo.CreateBoardColumn(ctx, c); err != nil {
+				return err
+			}
+			placed, err := s.repo.ListColumnTodoIDs(ctx, listID, &col.ID)
+			if err != nil {
+				return err
+			}
+			for i, id := range placed {
+				placed[i] = todoIDs[id]
+			}
+			if err := s.repo.PlaceTodos(ctx, &c.ID, placed); err != nil {
+				return err
+			}
+		}
+
+		for _, d := range graph.Dependencies {
+			todoID, ok := todoIDs[d.TodoID]
+			blockedBy, inList := todoIDs[d.BlockedBy]
+			if !ok || !inList {
+				continue
+			}
+			if _, err := s.repo.AddTodoDependency(ctx, todoID, blockedBy); err != nil {
+				return err
+			}
+		}
+
+		cloned, err := s.repo.ListTodos(ctx, list.ID, true)
+		if err != nil {
+			return err
+		}
+		clone = &models.ListWithTodos{TodoList: *list, Todos: cloned}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// cloneTodo returns a copy of a todo for another list, without ID,
+// assignees or board placement. Due instants are moved in the todo's own
+// time zone, or loc, so that they keep their time of day across daylight
+// saving changes.
+func cloneTodo(todo *models.Todo, listID uuid.UUID, opts models.CloneOptions, loc *time.Location) *models.Todo {
+	c := *todo
+	c.ID = uuid.Nil
+	c.ListID = listID
+	c.ColumnID, c.Rank = nil, 0
+	c.Tags = append(pq.StringArray(nil), todo.Tags...)
+	c.Assignees = nil
+	c.ArchivedAt = nil
+	c.CreatedAt, c.UpdatedAt = time.Time{}, time.Time{}
+	if opts.ResetCompletion {
+		c.Status, c.CompletedAt = false, nil
+	}
+
+	if days := opts.OffsetDays; days != 0 {
+		if todo.DueDate != nil {
+			due := todo.DueDate.In(todo.Location(loc)).AddDate(0, 0, days)
+			c.DueDate = &due
+		}
+		c.DueOn = shiftDate(todo.DueOn, days)
+		c.StartDate = shiftDate(todo.StartDate, days)
+		c.ScheduledFor = shiftDate(todo.ScheduledFor, days)
+	}
+	return &c
+}
+
+func shiftDate(d *models.Date, days int) *models.Date {
+	if d == nil {
+		return nil
+	}
+	shifted := d.AddDays(days)
+	return &shifted
+}
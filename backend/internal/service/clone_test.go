@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestCloneTodo(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// 09:00 in Berlin, the weekend before clocks go forward
+	due := time.Date(2025, time.March, 28, 8, 0, 0, 0, time.UTC)
+	on := models.Date{Year: 2025, Month: time.March, Day: 28}
+	column := uuid.New()
+	todo := &models.Todo{
+		ID:           uuid.New(),
+		ListID:       uuid.New(),
+		Title:        "Send invoices",
+		DueDate:      &due,
+		StartDate:    &on,
+		ScheduledFor: &on,
+		ColumnID:     &column,
+		Rank:         3,
+		Status:       true,
+		CompletedAt:  &due,
+		ArchivedAt:   &due,
+		Tags:         pq.StringArray{"billing"},
+		Assignees:    pq.StringArray{"ada"},
+		CreatedAt:    due,
+	}
+	listID := uuid.New()
+
+	c := cloneTodo(todo, listID, models.CloneOptions{OffsetDays: 7, ResetCompletion: true}, berlin)
+	assert.Equal(t, uuid.Nil, c.ID)
+	assert.Equal(t, listID, c.ListID)
+	assert.Equal(t, "Send invoices", c.Title)
+	assert.False(t, c.Status)
+	assert.Nil(t, c.CompletedAt)
+	assert.Nil(t, c.ArchivedAt)
+	assert.Nil(t, c.ColumnID)
+	assert.Zero(t, c.Rank)
+	assert.Nil(t, c.Assignees)
+	assert.True(t, c.CreatedAt.IsZero())
+	assert.Equal(t, "2025-04-04T07:00:00Z", c.DueDate.UTC().Format(time.RFC3339))
+	assert.Equal(t, "2025-04-04", c.StartDate.String())
+	assert.Equal(t, "2025-04-04", c.ScheduledFor.String())
+
+	c.Tags[0] = "changed"
+	assert.Equal(t, pq.StringArray{"billing"}, todo.Tags)
+	assert.Equal(t, "2025-03-28", todo.StartDate.String())
+
+	c = cloneTodo(todo, listID, models.CloneOptions{}, berlin)
+	assert.True(t, c.Status)
+	assert.Equal(t, todo.CompletedAt, c.CompletedAt)
+	assert.Nil(t, c.ArchivedAt)
+	assert.Equal(t, todo.DueDate, c.DueDate)
+
+	allDay := &models.Todo{Title: "Water plants", DueOn: &on}
+	c = cloneTodo(allDay, listID, models.CloneOptions{OffsetDays: -28}, berlin)
+	assert.Equal(t, "2025-02-28", c.DueOn.String())
+	assert.Nil(t, c.DueDate)
+}
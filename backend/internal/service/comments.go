@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// MaxCommentLength is the longest comment body accepted, in characters.
+const MaxCommentLength = 10000
+
+// AddComment adds a comment by the calling user to a todo, as a reply when
+// parentID is set. Users mentioned in the body get a mention event.
+func (s *todoService) AddComment(
+	ctx context.Context, todoID uuid.UUID, parentID *uuid.UUID, body string,
+) (*models.Comment, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	if err := validateCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	c := &models.Comment{
+		ID:       uuid.New(),
+		TodoID:   todoID,
+		ParentID: parentID,
+		AuthorID: &userID,
+		Body:     body,
+	}
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetTodo(ctx, todoID); err != nil {
+			return err
+		}
+		if parentID != nil {
+			// replies stay on the todo of their parent
+			if _, err := s.repo.GetComment(ctx, todoID, *parentID); err != nil {
+				return fmt.Errorf("getting parent comment '%s': %w", *parentID, err)
+			}
+		}
+		if err := s.repo.CreateComment(ctx, c); err != nil {
+			return err
+		}
+		return s.updateMentions(ctx, c, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListComments returns the comment threads of a todo. Top level comments
+// are oldest first, each with its replies nested.
+func (s *todoService) ListComments(ctx context.Context, todoID uuid.UUID) ([]*models.Comment, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.ListComments(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	return commentThreads(comments), nil
+}
+
+// EditComment replaces the body of a comment written by the calling user.
+// The previous body is kept in the comment's history.
+func (s *todoService) EditComment(ctx context.Context, todoID, id uuid.UUID, body string) (*models.Comment, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	if err := validateCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	var c *models.Comment
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if c, err = s.authoredComment(ctx, todoID, id, userID); err != nil {
+			return err
+		}
+		if c.Body == body {
+			return nil
+		}
+		if err := s.repo.UpdateCommentBody(ctx, c, body); err != nil {
+			return err
+		}
+		return s.updateMentions(ctx, c, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteComment deletes a comment written by the calling user. Replies to
+// it stay in the thread.
+func (s *todoService) DeleteComment(ctx context.Context, todoID, id uuid.UUID) error {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return ErrNoUser
+	}
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.authoredComment(ctx, todoID, id, userID); err != nil {
+			return err
+		}
+		return s.repo.DeleteComment(ctx, todoID, id)
+	})
+}
+
+// ListCommentRevisions returns the earlier bodies of a comment, oldest
+// first.
+func (s *todoService) ListCommentRevisions(
+	ctx context.Context, todoID, id uuid.UUID,
+) ([]*models.CommentRevision, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetComment(ctx, todoID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCommentRevisions(ctx, id)
+}
+
+// authoredComment returns a comment that is not deleted, provided userID
+// wrote it.
+func (s *todoService) authoredComment(ctx context.Context, todoID, id, userID uuid.UUID) (*models.Comment, error) {
+	c, err := s.repo.GetComment(ctx, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.DeletedAt != nil {
+		return nil, repository.ErrCommentNotFound
+	}
+	if c.AuthorID == nil || *c.AuthorID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	return c, nil
+}
+
+// updateMentions records the users mentioned in a comment and emits a
+// mention event for each user that was not mentioned by it before. Unknown
+// usernames are ignored, and so are authors mentioning themselves.
+func (s *todoService) updateMentions(ctx context.Context, c *models.Comment, actorID uuid.UUID) error {
+	users, err := s.repo.GetUsersByUsernames(ctx, parseMentions(c.Body))
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(users))
+	c.Mentions = nil
+	for _, u := range users {
+		ids = append(ids, u.ID)
+		c.Mentions = append(c.Mentions, u.Username)
+	}
+	added, err := s.repo.SetCommentMentions(ctx, c.ID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range added {
+		if userID == actorID {
+			continue
+		}
+		e := &models.Event{
+			Type:      models.EventMention,
+			UserID:    userID,
+			ActorID:   &actorID,
+			TodoID:    c.TodoID,
+			CommentID: &c.ID,
+		}
+		if err := s.repo.CreateEvent(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return ErrInvalidComment
+	}
+	return nil
+}
+
+// commentThreads nests replies under their parents. comments must be
+// ordered oldest first; the order is kept within each level.
+func commentThreads(comments []*models.Comment) []*models.Comment {
+	byID := make(map[uuid.UUID]*models.Comment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+
+	roots := make([]*models.Comment, 0)
+	for _, c := range comments {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Replies = append(parent.Replies, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"@ada can you check this?", []string{"ada"}},
+		{"Thanks @ada and @grace.hopper.", []string{"ada", "grace.hopper"}},
+		{"(@ada) @Ada @ada", []string{"ada"}},
+		{"mail ada@example.com instead", nil},
+		{"use `@decorator` here", nil},
+		{"```\n@ada in code\n```\nbut @grace outside", []string{"grace"}},
+		{"**@bob_1**: done", []string{"bob_1"}},
+		{"just an @ sign", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseMentions(tt.body))
+		})
+	}
+}
+
+func TestCommentThreads(t *testing.T) {
+	first := &models.Comment{ID: uuid.New()}
+	reply := &models.Comment{ID: uuid.New(), ParentID: &first.ID}
+	second := &models.Comment{ID: uuid.New()}
+	nested := &models.Comment{ID: uuid.New(), ParentID: &reply.ID}
+	orphan := &models.Comment{ID: uuid.New(), ParentID: new(uuid.UUID)}
+
+	roots := commentThreads([]*models.Comment{first, reply, second, nested, orphan})
+
+	assert.Equal(t, []*models.Comment{first, second, orphan}, roots)
+	assert.Equal(t, []*models.Comment{reply}, first.Replies)
+	assert.Equal(t, []*models.Comment{nested}, reply.Replies)
+	assert.Empty(t, second.Replies)
+}
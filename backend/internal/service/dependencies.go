@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// AddTodoDependency makes a todo wait for a blocker, which may be in
+// another list. A dependency that would close a cycle is rejected with
+// ErrDependencyCycle. Dependencies are added one at a time, so that two
+// added at once cannot close a cycle together.
+func (s *todoService) AddTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) error {
+	if todoID == blockerID {
+		return fmt.Errorf("%w: a todo cannot wait for itself", ErrDependencyCycle)
+	}
+
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.repo.LockTodoDependencies(ctx); err != nil {
+			return err
+		}
+		for _, id := range []uuid.UUID{todoID, blockerID} {
+			if _, err := s.repo.GetTodo(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		// the blocker must not already wait for the todo
+		path, err := blockerPath(blockerID, todoID, func(ids []uuid.UUID) ([]models.Dependency, error) {
+			return s.repo.ListBlockers(ctx, ids)
+		})
+		if err != nil {
+			return err
+		}
+		if path != nil {
+			return fmt.Errorf("%w: %s already waits for %s", ErrDependencyCycle, blockerID, formatPath(path))
+		}
+
+		_, err = s.repo.AddTodoDependency(ctx, todoID, blockerID)
+		return err
+	})
+}
+
+// RemoveTodoDependency stops a todo from waiting for a blocker. Removing a
+// dependency that does not exist is not an error.
+func (s *todoService) RemoveTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		_, err := s.repo.RemoveTodoDependency(ctx, todoID, blockerID)
+		return err
+	})
+}
+
+func (s *todoService) ListTodoDependencies(ctx context.Context, todoID uuid.UUID) (*models.TodoDependencies, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTodoDependencies(ctx, todoID)
+}
+
+func (s *todoService) GetDependencyGraph(ctx context.Context, listID uuid.UUID) (*models.DependencyGraph, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDependencyGraph(ctx, listID)
+}
+
+// NextTodos returns the open todos of a list in an order they can be worked
+// on: every todo comes after the todos of the list it waits for.
+func (s *todoService) NextTodos(ctx context.Context, listID uuid.UUID) ([]*models.NextTodo, error) {
+	graph, err := s.GetDependencyGraph(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return workOrder(graph, listID), nil
+}
+
+// checkBlockers rejects completing a todo while todos it waits for are open.
+func (s *todoService) checkBlockers(ctx context.Context, todoID uuid.UUID) error {
+	deps, err := s.repo.ListTodoDependencies(ctx, todoID)
+	if err != nil {
+		return err
+	}
+
+	var open []string
+	for _, blocker := range deps.BlockedBy {
+		if !blocker.Status {
+			open = append(open, strconv.Quote(blocker.Title))
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: %s", ErrOpenBlockers, strings.Join(open, ", "))
+	}
+	return nil
+}
+
+// blockerPath searches breadth first for a chain of blockers leading from
+// one todo to another and returns it, from included, or nil if there is
+// none. blockers returns the dependencies of the given todos.
+func blockerPath(
+	from, to uuid.UUID, blockers func(ids []uuid.UUID) ([]models.Dependency, error),
+) ([]uuid.UUID, error) {
+	prev := map[uuid.UUID]uuid.UUID{from: uuid.Nil}
+	frontier := []uuid.UUID{from}
+	for len(frontier) > 0 {
+		deps, err := blockers(frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, d := range deps {
+			if _, seen := prev[d.BlockedBy]; seen {
+				continue
+			}
+			prev[d.BlockedBy] = d.TodoID
+			if d.BlockedBy == to {
+				var path []uuid.UUID
+				for id := to; id != uuid.Nil; id = prev[id] {
+					path = append([]uuid.UUID{id}, path...)
+				}
+				return path, nil
+			}
+			frontier = append(frontier, d.BlockedBy)
+		}
+	}
+	return nil, nil
+}
+
+func formatPath(path []uuid.UUID) string {
+	ids := make([]string, len(path))
+	for i, id := range path {
+		ids[i] = id.String()
+	}
+	return strings.Join(ids, " -> ")
+}
+
+// workOrder sorts the open todos of a list topologically by their
+// dependencies within the list. Among todos that are free to go next, those
+// with a higher priority, then an earlier deadline, come first. Open
+// blockers in other lists do not affect the order but are reported.
+func workOrder(graph *models.DependencyGraph, listID uuid.UUID) []*models.NextTodo {
+	todos := make(map[uuid.UUID]*models.Todo, len(graph.Todos))
+	for _, todo := range graph.Todos {
+		todos[todo.ID] = todo
+	}
+	inOrder := func(id uuid.UUID) bool {
+		todo, ok := todos[id]
+		return ok && todo.ListID == listID && !todo.Status
+	}
+
+	next := make(map[uuid.UUID]*models.NextTodo)
+	var pending []*models.NextTodo
+	for _, todo := range graph.Todos {
+		if inOrder(todo.ID) {
+			n := &models.NextTodo{Todo: todo, BlockedBy: []uuid.UUID{}}
+			next[todo.ID] = n
+			pending = append(pending, n)
+		}
+	}
+
+	waitsFor := make(map[uuid.UUID]int)
+	unblocks := make(map[uuid.UUID][]uuid.UUID)
+	for _, d := range graph.Dependencies {
+		n, ok := next[d.TodoID]
+		blocker, known := todos[d.BlockedBy]
+		if !ok || !known || blocker.Status {
+			continue
+		}
+		n.BlockedBy = append(n.BlockedBy, d.BlockedBy)
+		if inOrder(d.BlockedBy) {
+			waitsFor[d.TodoID]++
+			unblocks[d.BlockedBy] = append(unblocks[d.BlockedBy], d.TodoID)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return workFirst(pending[i].Todo, pending[j].Todo)
+	})
+
+	order := make([]*models.NextTodo, 0, len(pending))
+	for len(pending) > 0 {
+		// the first todo that waits for nothing left; on a cycle, which
+		// cannot be added but is not worth failing for, the first one
+		pick := 0
+		for i, n := range pending {
+			if waitsFor[n.ID] == 0 {
+				pick = i
+				break
+			}
+		}
+		n := pending[pick]
+		pending = append(pending[:pick], pending[pick+1:]...)
+		order = append(order, n)
+		for _, id := range unblocks[n.ID] {
+			waitsFor[id]--
+		}
+	}
+	return order
+}
+
+// workFirst orders todos by priority, highest first, then by deadline,
+// earliest first, then by creation.
+func workFirst(a, b *models.Todo) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	da, okA := a.Deadline(time.UTC)
+	db, okB := b.Deadline(time.UTC)
+	switch {
+	case okA && okB && !da.Equal(db):
+		return da.Before(db)
+	case okA != okB:
+		return okA
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
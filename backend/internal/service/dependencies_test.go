@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func TestBlockerPath(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	// a waits for b, b waits for c and d
+	edges := []models.Dependency{{TodoID: a, BlockedBy: b}, {TodoID: b, BlockedBy: c}, {TodoID: b, BlockedBy: d}}
+	calls := 0
+	blockers := func(ids []uuid.UUID) ([]models.Dependency, error) {
+		calls++
+		var deps []models.Dependency
+		for _, e := range edges {
+			for _, id := range ids {
+				if e.TodoID == id {
+					deps = append(deps, e)
+				}
+			}
+		}
+		return deps, nil
+	}
+
+	path, err := blockerPath(a, d, blockers)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{a, b, d}, path)
+
+	path, err = blockerPath(c, a, blockers)
+	require.NoError(t, err)
+	assert.Nil(t, path)
+
+	// a cycle already in the data does not loop forever
+	edges = append(edges, models.Dependency{TodoID: c, BlockedBy: a})
+	calls = 0
+	path, err = blockerPath(a, uuid.New(), blockers)
+	require.NoError(t, err)
+	assert.Nil(t, path)
+	assert.Equal(t, 3, calls)
+}
+
+func TestWorkOrder(t *testing.T) {
+	listID := uuid.New()
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	todo := func(title string, p models.Priority) *models.Todo {
+		created = created.Add(time.Minute)
+		return &models.Todo{ID: uuid.New(), ListID: listID, Title: title, Priority: p, CreatedAt: created}
+	}
+
+	design := todo("design", models.PriorityLow)
+	build := todo("build", models.PriorityHigh)
+	test := todo("test", models.PriorityHigh)
+	docs := todo("docs", models.PriorityNone)
+	urgent := todo("urgent", models.PriorityMedium)
+	due := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	urgent.DueDate = &due
+	later := todo("later", models.PriorityMedium)
+	done := todo("done", models.PriorityHigh)
+	done.Status = true
+	external := &models.Todo{ID: uuid.New(), ListID: uuid.New(), Title: "other list"}
+
+	graph := &models.DependencyGraph{
+		Todos: []*models.Todo{design, build, test, docs, urgent, later, done, external},
+		Dependencies: []models.Dependency{
+			{TodoID: build.ID, BlockedBy: design.ID},
+			{TodoID: test.ID, BlockedBy: build.ID},
+			{TodoID: test.ID, BlockedBy: done.ID},
+			{TodoID: docs.ID, BlockedBy: external.ID},
+		},
+	}
+
+	order := workOrder(graph, listID)
+	var titles []string
+	for _, n := range order {
+		titles = append(titles, n.Title)
+	}
+	assert.Equal(t, []string{"urgent", "later", "design", "build", "test", "docs"}, titles)
+	assert.Empty(t, order[0].BlockedBy)
+	assert.Equal(t, []uuid.UUID{build.ID}, order[4].BlockedBy)
+	assert.Equal(t, []uuid.UUID{external.ID}, order[5].BlockedBy)
+}
@@ -0,0 +1,18 @@
+package service
+
+import "fmt"
+
+var ErrAttachmentTooLarge = fmt.Errorf("attachment is larger than %d MiB", MaxAttachmentSize>>20)
+var ErrUnsupportedMediaType = fmt.Errorf("attachment type is not allowed")
+var ErrChecksumMismatch = fmt.Errorf("attachment checksum does not match its content")
+var ErrNoUser = fmt.Errorf("request has no user")
+var ErrNotCommentAuthor = fmt.Errorf("only the author can change a comment")
+var ErrInvalidComment = fmt.Errorf("comment body must be 1 to %d characters", MaxCommentLength)
+var ErrNoListAccess = fmt.Errorf("user has no access to the list")
+var ErrDependencyCycle = fmt.Errorf("dependency would create a cycle")
+var ErrOpenBlockers = fmt.Errorf("todo is blocked by open todos")
+var ErrWIPLimitReached = fmt.Errorf("column is at its WIP limit")
+var ErrColumnNotInList = fmt.Errorf("column belongs to another list")
+var ErrFolderNotInWorkspace = fmt.Errorf("folder belongs to another workspace")
+var ErrInvalidTodo = fmt.Errorf("invalid todo")
+var ErrInvalidStatsRange = fmt.Errorf("stats range must span 1 to %d days", MaxStatsDays)
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// CreateFeedToken creates a secret token for the calendar feed of either a
+// user or a list, failing with ErrUserNotFound or ErrListNotFound when it
+// does not exist. The plain token is only returned here; the repository
+// keeps its hash.
+func (s *todoService) CreateFeedToken(ctx context.Context, userID, listID *uuid.UUID) (*models.FeedToken, error) {
+	if (userID == nil) == (listID == nil) {
+		return nil, fmt.Errorf("feed token needs either a user or a list")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate feed token: %w", err)
+	}
+	plain := base64.RawURLEncoding.EncodeToString(secret)
+
+	var token *models.FeedToken
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if userID != nil {
+			_, err = s.repo.GetUser(ctx, *userID)
+		} else {
+			_, err = s.repo.GetList(ctx, *listID)
+		}
+		if err != nil {
+			return err
+		}
+		token, err = s.repo.CreateFeedToken(ctx, userID, listID, hashFeedToken(plain))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token.Token = plain
+	return token, nil
+}
+
+func (s *todoService) ListFeedTokens(ctx context.Context, userID, listID *uuid.UUID) ([]*models.FeedToken, error) {
+	// read operations don't need transactions
+	return s.repo.ListFeedTokens(ctx, userID, listID)
+}
+
+func (s *todoService) RevokeFeedToken(ctx context.Context, id uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.RevokeFeedToken(ctx, id)
+	})
+}
+
+// GetFeed resolves an active feed token to the todos it publishes.
+func (s *todoService) GetFeed(ctx context.Context, plain string) (*models.Feed, error) {
+	token, err := s.repo.GetFeedTokenByHash(ctx, hashFeedToken(plain))
+	if err != nil {
+		return nil, err
+	}
+
+	if token.ListID != nil {
+		list, err := s.repo.GetList(ctx, *token.ListID)
+		if err != nil {
+			return nil, fmt.Errorf("getting list '%s': %w", token.ListID, err)
+		}
+		todos, err := s.repo.ListTodos(ctx, list.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		return &models.Feed{Name: list.Name, Todos: todos}, nil
+	}
+
+	user, err := s.repo.GetUser(ctx, *token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user '%s': %w", token.UserID, err)
+	}
+	todos, err := s.repo.ListUserTodos(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Feed{Name: user.Username + "'s todos", Todos: todos}, nil
+}
+
+func hashFeedToken(plain string) []byte {
+	sum := sha256.Sum256([]byte(plain))
+	return sum[:]
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// feedRepo knows one user and one list and creates tokens for anything.
+type feedRepo struct {
+	repository.Repository
+	userID, listID uuid.UUID
+	created        int
+}
+
+func (r *feedRepo) GetUser(_ context.Context, id uuid.UUID) (*models.User, error) {
+	if id != r.userID {
+		return nil, repository.ErrUserNotFound
+	}
+	return &models.User{ID: id}, nil
+}
+
+func (r *feedRepo) GetList(_ context.Context, id uuid.UUID) (*models.TodoList, error) {
+	if id != r.listID {
+		return nil, repository.ErrListNotFound
+	}
+	return &models.TodoList{ID: id}, nil
+}
+
+func (r *feedRepo) CreateFeedToken(_ context.Context, userID, listID *uuid.UUID, _ []byte) (*models.FeedToken, error) {
+	r.created++
+	return &models.FeedToken{ID: uuid.New(), UserID: userID, ListID: listID}, nil
+}
+
+func TestCreateFeedTokenNeedsExistingTarget(t *testing.T) {
+	repo := &feedRepo{userID: uuid.New(), listID: uuid.New()}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+	missing := uuid.New()
+
+	_, err := svc.CreateFeedToken(context.Background(), &missing, nil)
+	assert.ErrorIs(t, err, repository.ErrUserNotFound)
+	_, err = svc.CreateFeedToken(context.Background(), nil, &missing)
+	assert.ErrorIs(t, err, repository.ErrListNotFound)
+	assert.Zero(t, repo.created)
+
+	token, err := svc.CreateFeedToken(context.Background(), nil, &repo.listID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token.Token)
+	assert.Equal(t, 1, repo.created)
+}
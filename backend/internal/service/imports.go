@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/transfer"
+)
+
+// importProgressInterval is the number of todos between progress updates
+// of a running import job.
+const importProgressInterval = 50
+
+// importJobLease is how long a running import job stays claimed without a
+// heartbeat from its worker. After that, another worker takes it over.
+const importJobLease = 5 * time.Minute
+
+// maxImportAttempts is the number of times a job is claimed before it is
+// given up on, so that a file that brings down the worker is not retried
+// forever.
+const maxImportAttempts = 3
+
+// CreateImportJob queues an import of another tool's export file. Uploading
+// the same file again returns the job of the first upload unless that job
+// failed, so nothing is imported twice; created reports whether a new job
+// was queued.
+func (s *todoService) CreateImportJob(
+	ctx context.Context, source transfer.Source, listName string, payload []byte,
+) (job *models.ImportJob, created bool, err error) {
+	var ownerID *uuid.UUID
+	if userID, ok := identity.UserID(ctx); ok {
+		ownerID = &userID
+	}
+	hash := sha256.Sum256(payload)
+
+	job, err = s.repo.FindImportJob(ctx, ownerID, string(source), hash[:])
+	if err == nil {
+		return job, false, nil
+	}
+	if !errors.Is(err, repository.ErrImportJobNotFound) {
+		return nil, false, err
+	}
+
+	job = &models.ImportJob{
+		OwnerID:  ownerID,
+		Source:   string(source),
+		ListName: listName,
+		FileHash: hash[:],
+		Payload:  payload,
+	}
+	if err := s.repo.CreateImportJob(ctx, job); err != nil {
+		// lost a race against an upload of the same file
+		if existing, findErr := s.repo.FindImportJob(ctx, ownerID, string(source), hash[:]); findErr == nil {
+			return existing, false, nil
+		}
+		return nil, false, err
+	}
+	return job, true, nil
+}
+
+// GetImportJob returns a job to its owner only; other callers get
+// ErrImportJobNotFound.
+func (s *todoService) GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	// read operations don't need transactions
+	job, err := s.repo.GetImportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.OwnerID != nil {
+		if userID, ok := identity.UserID(ctx); !ok || userID != *job.OwnerID {
+			return nil, repository.ErrImportJobNotFound
+		}
+	}
+	return job, nil
+}
+
+// RunNextImportJob claims the oldest pending import job, or one whose
+// worker stopped renewing its claim, and runs it. It reports false when
+// there was nothing to do. Entries of the file that cannot be imported are
+// recorded on the job; everything else is created in a single transaction
+// for the owner of the job.
+func (s *todoService) RunNextImportJob(ctx context.Context) (bool, error) {
+	job, err := s.repo.ClaimImportJob(ctx, importJobLease)
+	if errors.Is(err, repository.ErrImportJobNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if job.Attempts > maxImportAttempts {
+		err = fmt.Errorf("the import was interrupted %d times", job.Attempts-1)
+	} else {
+		stop := s.renewImportJobClaim(ctx, job.ID)
+		err = s.runImportJob(ctx, job)
+		stop()
+	}
+	if err != nil {
+		job.Status = models.ImportFailed
+		job.Error = err.Error()
+		job.Processed = 0
+		job.CreatedLists = 0
+		job.CreatedTodos = 0
+	} else {
+		job.Status = models.ImportSucceeded
+	}
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err := s.repo.UpdateImportJob(ctx, job); err != nil {
+		return true, fmt.Errorf("finishing import job '%s': %w", job.ID, err)
+	}
+	return true, nil
+}
+
+// renewImportJobClaim keeps the heartbeat of a job fresh until stop is
+// called.
+func (s *todoService) renewImportJobClaim(ctx context.Context, id uuid.UUID) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(importJobLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.repo.HeartbeatImportJob(ctx, id); err != nil {
+					slog.WarnContext(ctx, "renew import job claim", "job_id", id, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (s *todoService) runImportJob(ctx context.Context, job *models.ImportJob) error {
+	lists, rowErrors, err := transfer.ParseExport(bytes.NewReader(job.Payload), transfer.Source(job.Source), job.ListName)
+	if err != nil {
+		return err
+	}
+	job.Errors = rowErrors
+	for _, l := range lists {
+		job.Total += len(l.Todos)
+	}
+	if err := s.repo.UpdateImportJob(ctx, job); err != nil {
+		return err
+	}
+
+	// progress is written outside of the import transaction so that it is
+	// visible while the job runs
+	progressCtx := ctx
+	if job.OwnerID != nil {
+		ctx = identity.WithUserID(ctx, *job.OwnerID)
+	}
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, l := range lists {
+			created, err := s.repo.CreateList(ctx, l.Name, job.OwnerID)
+			if err != nil {
+				return fmt.Errorf("importing list '%s': %w", l.Name, err)
+			}
+			job.CreatedLists++
+
+			for _, todo := range l.Todos {
+				todo.ListID = created.ID
+				if err := s.repo.InsertTodo(ctx, todo); err != nil {
+					return fmt.Errorf("importing todo '%s': %w", todo.Title, err)
+				}
+				job.CreatedTodos++
+				job.Processed++
+				if job.Processed%importProgressInterval == 0 {
+					if err := s.repo.UpdateImportJob(progressCtx, job); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
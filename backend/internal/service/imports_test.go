@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// importRepo hands out job once and keeps its updates.
+type importRepo struct {
+	repository.Repository
+	job     *models.ImportJob
+	lease   time.Duration
+	updates []models.ImportJob
+}
+
+func (r *importRepo) ClaimImportJob(_ context.Context, lease time.Duration) (*models.ImportJob, error) {
+	r.lease = lease
+	if r.job == nil {
+		return nil, repository.ErrImportJobNotFound
+	}
+	job := r.job
+	r.job = nil
+	return job, nil
+}
+
+func (r *importRepo) UpdateImportJob(_ context.Context, job *models.ImportJob) error {
+	r.updates = append(r.updates, *job)
+	return nil
+}
+
+func TestRunNextImportJobGivesUpAfterMaxAttempts(t *testing.T) {
+	repo := &importRepo{job: &models.ImportJob{
+		ID:       uuid.New(),
+		Source:   "todoist",
+		Status:   models.ImportRunning,
+		Payload:  []byte("not looked at"),
+		Attempts: maxImportAttempts + 1,
+	}}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+
+	ran, err := svc.RunNextImportJob(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, importJobLease, repo.lease)
+
+	require.Len(t, repo.updates, 1)
+	assert.Equal(t, models.ImportFailed, repo.updates[0].Status)
+	assert.Equal(t, "the import was interrupted 3 times", repo.updates[0].Error)
+	assert.NotNil(t, repo.updates[0].FinishedAt)
+
+	ran, err = svc.RunNextImportJob(context.Background())
+	require.NoError(t, err)
+	assert.False(t, ran)
+}
@@ -0,0 +1,733 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/awnzl/to-do-app/internal/backup"
+	"github.com/awnzl/to-do-app/internal/logging"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/quickadd"
+	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/transfer"
+)
+
+var tracer = otel.Tracer("github.com/awnzl/to-do-app/internal/service")
+
+// expectedErrors are the errors of calls that callers are told about, such
+// as missing records or rejected changes. Calls failing with them are only
+// logged at debug level.
+var expectedErrors = []error{
+	ErrAttachmentTooLarge, ErrUnsupportedMediaType, ErrChecksumMismatch, ErrNoUser, ErrNotCommentAuthor,
+	ErrInvalidComment, ErrNoListAccess, ErrDependencyCycle, ErrOpenBlockers, ErrWIPLimitReached,
+	ErrColumnNotInList, ErrFolderNotInWorkspace, ErrInvalidStatsRange, ErrInvalidTodo,
+	repository.ErrTodoNotFound, repository.ErrListNotFound, repository.ErrUserNotFound,
+	repository.ErrFeedTokenNotFound, repository.ErrImportJobNotFound, repository.ErrIDConflict,
+	repository.ErrAttachmentNotFound, repository.ErrCommentNotFound, repository.ErrListMemberNotFound,
+	repository.ErrViewNotFound, repository.ErrViewNameTaken, repository.ErrColumnNotFound,
+	repository.ErrColumnNameTaken, repository.ErrWorkspaceNotFound, repository.ErrWorkspaceMemberNotFound,
+	repository.ErrFolderNotFound, repository.ErrFolderNameTaken, repository.ErrTemplateNotFound,
+	repository.ErrTemplateNameTaken, repository.ErrCalendarObjectNameTaken, backup.ErrInvalidArchive,
+}
+
+// instrumentedService runs every call of a TodoService in a span named
+// after the method and logs the calls that fail. Calls between service
+// methods are not instrumented on their own.
+type instrumentedService struct {
+	svc TodoService
+}
+
+// Instrument returns svc with its methods traced by the global tracer
+// provider and their failures logged with the logger of the context.
+func Instrument(svc TodoService) TodoService {
+	return &instrumentedService{svc: svc}
+}
+
+// startCall starts the span of a call to method. The returned function
+// records how the call ended.
+func startCall(ctx context.Context, method string) (context.Context, func(err error)) {
+	ctx, span := tracer.Start(ctx, "TodoService."+method)
+	return ctx, func(err error) {
+		defer span.End()
+		if err == nil {
+			return
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		level := slog.LevelError
+		for _, expected := range expectedErrors {
+			if errors.Is(err, expected) {
+				level = slog.LevelDebug
+				break
+			}
+		}
+		logging.FromContext(ctx).Log(ctx, level, "service call failed", "method", method, "error", err)
+	}
+}
+
+func (s *instrumentedService) CreateList(
+	ctx context.Context, name string, loc models.ListLocation,
+) (_ *models.TodoList, err error) {
+	ctx, end := startCall(ctx, "CreateList")
+	defer func() { end(err) }()
+	return s.svc.CreateList(ctx, name, loc)
+}
+
+func (s *instrumentedService) GetList(ctx context.Context, id uuid.UUID) (_ *models.TodoList, err error) {
+	ctx, end := startCall(ctx, "GetList")
+	defer func() { end(err) }()
+	return s.svc.GetList(ctx, id)
+}
+
+func (s *instrumentedService) UpdateList(ctx context.Context, list *models.TodoList) (err error) {
+	ctx, end := startCall(ctx, "UpdateList")
+	defer func() { end(err) }()
+	return s.svc.UpdateList(ctx, list)
+}
+
+func (s *instrumentedService) DeleteList(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteList")
+	defer func() { end(err) }()
+	return s.svc.DeleteList(ctx, id)
+}
+
+func (s *instrumentedService) ListLists(ctx context.Context, f models.ListFilter) (_ []*models.TodoList, err error) {
+	ctx, end := startCall(ctx, "ListLists")
+	defer func() { end(err) }()
+	return s.svc.ListLists(ctx, f)
+}
+
+func (s *instrumentedService) SetListArchived(
+	ctx context.Context, id uuid.UUID, archived bool,
+) (_ *models.TodoList, err error) {
+	ctx, end := startCall(ctx, "SetListArchived")
+	defer func() { end(err) }()
+	return s.svc.SetListArchived(ctx, id, archived)
+}
+
+func (s *instrumentedService) SetListAutoArchive(
+	ctx context.Context, id uuid.UUID, days *int,
+) (_ *models.TodoList, err error) {
+	ctx, end := startCall(ctx, "SetListAutoArchive")
+	defer func() { end(err) }()
+	return s.svc.SetListAutoArchive(ctx, id, days)
+}
+
+func (s *instrumentedService) MoveList(
+	ctx context.Context, listID uuid.UUID, loc models.ListLocation,
+) (_ *models.TodoList, err error) {
+	ctx, end := startCall(ctx, "MoveList")
+	defer func() { end(err) }()
+	return s.svc.MoveList(ctx, listID, loc)
+}
+
+func (s *instrumentedService) CloneList(
+	ctx context.Context, listID uuid.UUID, opts models.CloneOptions,
+) (_ *models.ListWithTodos, err error) {
+	ctx, end := startCall(ctx, "CloneList")
+	defer func() { end(err) }()
+	return s.svc.CloneList(ctx, listID, opts)
+}
+
+func (s *instrumentedService) CreateListTemplate(ctx context.Context, t *models.ListTemplate) (err error) {
+	ctx, end := startCall(ctx, "CreateListTemplate")
+	defer func() { end(err) }()
+	return s.svc.CreateListTemplate(ctx, t)
+}
+
+func (s *instrumentedService) GetListTemplate(ctx context.Context, id uuid.UUID) (_ *models.ListTemplate, err error) {
+	ctx, end := startCall(ctx, "GetListTemplate")
+	defer func() { end(err) }()
+	return s.svc.GetListTemplate(ctx, id)
+}
+
+func (s *instrumentedService) ListListTemplates(ctx context.Context) (_ []*models.ListTemplate, err error) {
+	ctx, end := startCall(ctx, "ListListTemplates")
+	defer func() { end(err) }()
+	return s.svc.ListListTemplates(ctx)
+}
+
+func (s *instrumentedService) UpdateListTemplate(ctx context.Context, t *models.ListTemplate) (err error) {
+	ctx, end := startCall(ctx, "UpdateListTemplate")
+	defer func() { end(err) }()
+	return s.svc.UpdateListTemplate(ctx, t)
+}
+
+func (s *instrumentedService) DeleteListTemplate(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteListTemplate")
+	defer func() { end(err) }()
+	return s.svc.DeleteListTemplate(ctx, id)
+}
+
+func (s *instrumentedService) InstantiateListTemplate(
+	ctx context.Context, id uuid.UUID, vars map[string]string, start *models.Date, loc models.ListLocation,
+) (_ *models.ListWithTodos, err error) {
+	ctx, end := startCall(ctx, "InstantiateListTemplate")
+	defer func() { end(err) }()
+	return s.svc.InstantiateListTemplate(ctx, id, vars, start, loc)
+}
+
+func (s *instrumentedService) CreateWorkspace(ctx context.Context, name string) (_ *models.Workspace, err error) {
+	ctx, end := startCall(ctx, "CreateWorkspace")
+	defer func() { end(err) }()
+	return s.svc.CreateWorkspace(ctx, name)
+}
+
+func (s *instrumentedService) GetWorkspace(ctx context.Context, id uuid.UUID) (_ *models.Workspace, err error) {
+	ctx, end := startCall(ctx, "GetWorkspace")
+	defer func() { end(err) }()
+	return s.svc.GetWorkspace(ctx, id)
+}
+
+func (s *instrumentedService) ListWorkspaces(ctx context.Context) (_ []*models.Workspace, err error) {
+	ctx, end := startCall(ctx, "ListWorkspaces")
+	defer func() { end(err) }()
+	return s.svc.ListWorkspaces(ctx)
+}
+
+func (s *instrumentedService) UpdateWorkspace(
+	ctx context.Context, id uuid.UUID, name string,
+) (_ *models.Workspace, err error) {
+	ctx, end := startCall(ctx, "UpdateWorkspace")
+	defer func() { end(err) }()
+	return s.svc.UpdateWorkspace(ctx, id, name)
+}
+
+func (s *instrumentedService) DeleteWorkspace(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteWorkspace")
+	defer func() { end(err) }()
+	return s.svc.DeleteWorkspace(ctx, id)
+}
+
+func (s *instrumentedService) RestoreWorkspace(ctx context.Context, id uuid.UUID) (_ *models.Workspace, err error) {
+	ctx, end := startCall(ctx, "RestoreWorkspace")
+	defer func() { end(err) }()
+	return s.svc.RestoreWorkspace(ctx, id)
+}
+
+func (s *instrumentedService) AddWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "AddWorkspaceMember")
+	defer func() { end(err) }()
+	return s.svc.AddWorkspaceMember(ctx, workspaceID, userID)
+}
+
+func (s *instrumentedService) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "RemoveWorkspaceMember")
+	defer func() { end(err) }()
+	return s.svc.RemoveWorkspaceMember(ctx, workspaceID, userID)
+}
+
+func (s *instrumentedService) ListWorkspaceMembers(
+	ctx context.Context, workspaceID uuid.UUID,
+) (_ []*models.WorkspaceMember, err error) {
+	ctx, end := startCall(ctx, "ListWorkspaceMembers")
+	defer func() { end(err) }()
+	return s.svc.ListWorkspaceMembers(ctx, workspaceID)
+}
+
+func (s *instrumentedService) CreateFolder(
+	ctx context.Context, workspaceID uuid.UUID, name string,
+) (_ *models.Folder, err error) {
+	ctx, end := startCall(ctx, "CreateFolder")
+	defer func() { end(err) }()
+	return s.svc.CreateFolder(ctx, workspaceID, name)
+}
+
+func (s *instrumentedService) ListFolders(ctx context.Context, workspaceID uuid.UUID) (_ []*models.Folder, err error) {
+	ctx, end := startCall(ctx, "ListFolders")
+	defer func() { end(err) }()
+	return s.svc.ListFolders(ctx, workspaceID)
+}
+
+func (s *instrumentedService) UpdateFolder(
+	ctx context.Context, workspaceID, folderID uuid.UUID, name string,
+) (_ *models.Folder, err error) {
+	ctx, end := startCall(ctx, "UpdateFolder")
+	defer func() { end(err) }()
+	return s.svc.UpdateFolder(ctx, workspaceID, folderID, name)
+}
+
+func (s *instrumentedService) DeleteFolder(ctx context.Context, workspaceID, folderID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteFolder")
+	defer func() { end(err) }()
+	return s.svc.DeleteFolder(ctx, workspaceID, folderID)
+}
+
+func (s *instrumentedService) AddListMember(ctx context.Context, listID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "AddListMember")
+	defer func() { end(err) }()
+	return s.svc.AddListMember(ctx, listID, userID)
+}
+
+func (s *instrumentedService) RemoveListMember(ctx context.Context, listID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "RemoveListMember")
+	defer func() { end(err) }()
+	return s.svc.RemoveListMember(ctx, listID, userID)
+}
+
+func (s *instrumentedService) ListListMembers(
+	ctx context.Context, listID uuid.UUID,
+) (_ []*models.ListMember, err error) {
+	ctx, end := startCall(ctx, "ListListMembers")
+	defer func() { end(err) }()
+	return s.svc.ListListMembers(ctx, listID)
+}
+
+func (s *instrumentedService) CreateTodo(
+	ctx context.Context, listID uuid.UUID, title, description string, schedule models.Schedule,
+) (_ *models.Todo, err error) {
+	ctx, end := startCall(ctx, "CreateTodo")
+	defer func() { end(err) }()
+	return s.svc.CreateTodo(ctx, listID, title, description, schedule)
+}
+
+func (s *instrumentedService) SetTodoSchedule(
+	ctx context.Context, todo *models.Todo, schedule models.Schedule,
+) (err error) {
+	ctx, end := startCall(ctx, "SetTodoSchedule")
+	defer func() { end(err) }()
+	return s.svc.SetTodoSchedule(ctx, todo, schedule)
+}
+
+func (s *instrumentedService) QuickAddTodo(
+	ctx context.Context, listID uuid.UUID, text string,
+) (_ *models.Todo, _ *quickadd.Result, err error) {
+	ctx, end := startCall(ctx, "QuickAddTodo")
+	defer func() { end(err) }()
+	return s.svc.QuickAddTodo(ctx, listID, text)
+}
+
+func (s *instrumentedService) GetTodo(ctx context.Context, id uuid.UUID) (_ *models.Todo, err error) {
+	ctx, end := startCall(ctx, "GetTodo")
+	defer func() { end(err) }()
+	return s.svc.GetTodo(ctx, id)
+}
+
+func (s *instrumentedService) UpdateTodo(ctx context.Context, todo *models.Todo) (err error) {
+	ctx, end := startCall(ctx, "UpdateTodo")
+	defer func() { end(err) }()
+	return s.svc.UpdateTodo(ctx, todo)
+}
+
+func (s *instrumentedService) MoveTodoToList(ctx context.Context, todoID, newListID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "MoveTodoToList")
+	defer func() { end(err) }()
+	return s.svc.MoveTodoToList(ctx, todoID, newListID)
+}
+
+func (s *instrumentedService) CompleteTodo(ctx context.Context, todoID uuid.UUID, force bool) (err error) {
+	ctx, end := startCall(ctx, "CompleteTodo")
+	defer func() { end(err) }()
+	return s.svc.CompleteTodo(ctx, todoID, force)
+}
+
+func (s *instrumentedService) DeleteTodo(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteTodo")
+	defer func() { end(err) }()
+	return s.svc.DeleteTodo(ctx, id)
+}
+
+func (s *instrumentedService) ListTodos(
+	ctx context.Context, listID uuid.UUID, archived bool,
+) (_ []*models.Todo, err error) {
+	ctx, end := startCall(ctx, "ListTodos")
+	defer func() { end(err) }()
+	return s.svc.ListTodos(ctx, listID, archived)
+}
+
+func (s *instrumentedService) ArchiveCompletedTodos(ctx context.Context) (_ int64, err error) {
+	ctx, end := startCall(ctx, "ArchiveCompletedTodos")
+	defer func() { end(err) }()
+	return s.svc.ArchiveCompletedTodos(ctx)
+}
+
+func (s *instrumentedService) ListOverdueTodos(ctx context.Context) (_ []*models.Todo, err error) {
+	ctx, end := startCall(ctx, "ListOverdueTodos")
+	defer func() { end(err) }()
+	return s.svc.ListOverdueTodos(ctx)
+}
+
+func (s *instrumentedService) AddTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "AddTodoDependency")
+	defer func() { end(err) }()
+	return s.svc.AddTodoDependency(ctx, todoID, blockerID)
+}
+
+func (s *instrumentedService) RemoveTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "RemoveTodoDependency")
+	defer func() { end(err) }()
+	return s.svc.RemoveTodoDependency(ctx, todoID, blockerID)
+}
+
+func (s *instrumentedService) ListTodoDependencies(
+	ctx context.Context, todoID uuid.UUID,
+) (_ *models.TodoDependencies, err error) {
+	ctx, end := startCall(ctx, "ListTodoDependencies")
+	defer func() { end(err) }()
+	return s.svc.ListTodoDependencies(ctx, todoID)
+}
+
+func (s *instrumentedService) GetDependencyGraph(
+	ctx context.Context, listID uuid.UUID,
+) (_ *models.DependencyGraph, err error) {
+	ctx, end := startCall(ctx, "GetDependencyGraph")
+	defer func() { end(err) }()
+	return s.svc.GetDependencyGraph(ctx, listID)
+}
+
+func (s *instrumentedService) NextTodos(ctx context.Context, listID uuid.UUID) (_ []*models.NextTodo, err error) {
+	ctx, end := startCall(ctx, "NextTodos")
+	defer func() { end(err) }()
+	return s.svc.NextTodos(ctx, listID)
+}
+
+func (s *instrumentedService) CreateBoardColumn(
+	ctx context.Context, listID uuid.UUID, name string, wipLimit *int,
+) (_ *models.BoardColumn, err error) {
+	ctx, end := startCall(ctx, "CreateBoardColumn")
+	defer func() { end(err) }()
+	return s.svc.CreateBoardColumn(ctx, listID, name, wipLimit)
+}
+
+func (s *instrumentedService) ListBoardColumns(
+	ctx context.Context, listID uuid.UUID,
+) (_ []*models.BoardColumn, err error) {
+	ctx, end := startCall(ctx, "ListBoardColumns")
+	defer func() { end(err) }()
+	return s.svc.ListBoardColumns(ctx, listID)
+}
+
+func (s *instrumentedService) UpdateBoardColumn(
+	ctx context.Context, listID, columnID uuid.UUID, name string, wipLimit, position *int,
+) (_ *models.BoardColumn, err error) {
+	ctx, end := startCall(ctx, "UpdateBoardColumn")
+	defer func() { end(err) }()
+	return s.svc.UpdateBoardColumn(ctx, listID, columnID, name, wipLimit, position)
+}
+
+func (s *instrumentedService) DeleteBoardColumn(ctx context.Context, listID, columnID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteBoardColumn")
+	defer func() { end(err) }()
+	return s.svc.DeleteBoardColumn(ctx, listID, columnID)
+}
+
+func (s *instrumentedService) GetBoard(ctx context.Context, listID uuid.UUID) (_ *models.Board, err error) {
+	ctx, end := startCall(ctx, "GetBoard")
+	defer func() { end(err) }()
+	return s.svc.GetBoard(ctx, listID)
+}
+
+func (s *instrumentedService) MoveTodoToColumn(
+	ctx context.Context, todoID uuid.UUID, columnID *uuid.UUID, position *int,
+) (err error) {
+	ctx, end := startCall(ctx, "MoveTodoToColumn")
+	defer func() { end(err) }()
+	return s.svc.MoveTodoToColumn(ctx, todoID, columnID, position)
+}
+
+func (s *instrumentedService) AssignTodo(ctx context.Context, todoID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "AssignTodo")
+	defer func() { end(err) }()
+	return s.svc.AssignTodo(ctx, todoID, userID)
+}
+
+func (s *instrumentedService) UnassignTodo(ctx context.Context, todoID, userID uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "UnassignTodo")
+	defer func() { end(err) }()
+	return s.svc.UnassignTodo(ctx, todoID, userID)
+}
+
+func (s *instrumentedService) ListAssignedTodos(ctx context.Context, userID uuid.UUID) (_ []*models.Todo, err error) {
+	ctx, end := startCall(ctx, "ListAssignedTodos")
+	defer func() { end(err) }()
+	return s.svc.ListAssignedTodos(ctx, userID)
+}
+
+func (s *instrumentedService) AddAttachment(
+	ctx context.Context, todoID uuid.UUID, filename, contentType string, size int64, checksum string, r io.Reader,
+) (_ *models.Attachment, err error) {
+	ctx, end := startCall(ctx, "AddAttachment")
+	defer func() { end(err) }()
+	return s.svc.AddAttachment(ctx, todoID, filename, contentType, size, checksum, r)
+}
+
+func (s *instrumentedService) ListAttachments(
+	ctx context.Context, todoID uuid.UUID,
+) (_ []*models.Attachment, err error) {
+	ctx, end := startCall(ctx, "ListAttachments")
+	defer func() { end(err) }()
+	return s.svc.ListAttachments(ctx, todoID)
+}
+
+func (s *instrumentedService) OpenAttachment(
+	ctx context.Context, todoID, id uuid.UUID,
+) (_ *models.Attachment, _ io.ReadCloser, err error) {
+	ctx, end := startCall(ctx, "OpenAttachment")
+	defer func() { end(err) }()
+	return s.svc.OpenAttachment(ctx, todoID, id)
+}
+
+func (s *instrumentedService) DeleteAttachment(ctx context.Context, todoID, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteAttachment")
+	defer func() { end(err) }()
+	return s.svc.DeleteAttachment(ctx, todoID, id)
+}
+
+func (s *instrumentedService) CleanupBlobs(ctx context.Context) (_ int, err error) {
+	ctx, end := startCall(ctx, "CleanupBlobs")
+	defer func() { end(err) }()
+	return s.svc.CleanupBlobs(ctx)
+}
+
+func (s *instrumentedService) CreateView(ctx context.Context, name, query string) (_ *models.View, err error) {
+	ctx, end := startCall(ctx, "CreateView")
+	defer func() { end(err) }()
+	return s.svc.CreateView(ctx, name, query)
+}
+
+func (s *instrumentedService) ListViews(ctx context.Context) (_ []*models.View, err error) {
+	ctx, end := startCall(ctx, "ListViews")
+	defer func() { end(err) }()
+	return s.svc.ListViews(ctx)
+}
+
+func (s *instrumentedService) GetView(ctx context.Context, id uuid.UUID) (_ *models.View, err error) {
+	ctx, end := startCall(ctx, "GetView")
+	defer func() { end(err) }()
+	return s.svc.GetView(ctx, id)
+}
+
+func (s *instrumentedService) UpdateView(
+	ctx context.Context, id uuid.UUID, name, query string,
+) (_ *models.View, err error) {
+	ctx, end := startCall(ctx, "UpdateView")
+	defer func() { end(err) }()
+	return s.svc.UpdateView(ctx, id, name, query)
+}
+
+func (s *instrumentedService) DeleteView(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteView")
+	defer func() { end(err) }()
+	return s.svc.DeleteView(ctx, id)
+}
+
+func (s *instrumentedService) ListViewTodos(
+	ctx context.Context, id uuid.UUID, limit, offset int,
+) (_ *models.TodoPage, err error) {
+	ctx, end := startCall(ctx, "ListViewTodos")
+	defer func() { end(err) }()
+	return s.svc.ListViewTodos(ctx, id, limit, offset)
+}
+
+func (s *instrumentedService) Agenda(ctx context.Context) (_ *models.Agenda, err error) {
+	ctx, end := startCall(ctx, "Agenda")
+	defer func() { end(err) }()
+	return s.svc.Agenda(ctx)
+}
+
+func (s *instrumentedService) Stats(
+	ctx context.Context, listID *uuid.UUID, from, to *models.Date,
+) (_ *models.Stats, err error) {
+	ctx, end := startCall(ctx, "Stats")
+	defer func() { end(err) }()
+	return s.svc.Stats(ctx, listID, from, to)
+}
+
+func (s *instrumentedService) Burndown(
+	ctx context.Context, listID uuid.UUID, from, to *models.Date,
+) (_ *models.Burndown, err error) {
+	ctx, end := startCall(ctx, "Burndown")
+	defer func() { end(err) }()
+	return s.svc.Burndown(ctx, listID, from, to)
+}
+
+func (s *instrumentedService) AddComment(
+	ctx context.Context, todoID uuid.UUID, parentID *uuid.UUID, body string,
+) (_ *models.Comment, err error) {
+	ctx, end := startCall(ctx, "AddComment")
+	defer func() { end(err) }()
+	return s.svc.AddComment(ctx, todoID, parentID, body)
+}
+
+func (s *instrumentedService) ListComments(ctx context.Context, todoID uuid.UUID) (_ []*models.Comment, err error) {
+	ctx, end := startCall(ctx, "ListComments")
+	defer func() { end(err) }()
+	return s.svc.ListComments(ctx, todoID)
+}
+
+func (s *instrumentedService) EditComment(
+	ctx context.Context, todoID, id uuid.UUID, body string,
+) (_ *models.Comment, err error) {
+	ctx, end := startCall(ctx, "EditComment")
+	defer func() { end(err) }()
+	return s.svc.EditComment(ctx, todoID, id, body)
+}
+
+func (s *instrumentedService) DeleteComment(ctx context.Context, todoID, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "DeleteComment")
+	defer func() { end(err) }()
+	return s.svc.DeleteComment(ctx, todoID, id)
+}
+
+func (s *instrumentedService) ListCommentRevisions(
+	ctx context.Context, todoID, id uuid.UUID,
+) (_ []*models.CommentRevision, err error) {
+	ctx, end := startCall(ctx, "ListCommentRevisions")
+	defer func() { end(err) }()
+	return s.svc.ListCommentRevisions(ctx, todoID, id)
+}
+
+func (s *instrumentedService) ExportList(ctx context.Context, listID uuid.UUID) (_ *models.ListWithTodos, err error) {
+	ctx, end := startCall(ctx, "ExportList")
+	defer func() { end(err) }()
+	return s.svc.ExportList(ctx, listID)
+}
+
+func (s *instrumentedService) ImportLists(ctx context.Context, lists []*models.ListWithTodos) (err error) {
+	ctx, end := startCall(ctx, "ImportLists")
+	defer func() { end(err) }()
+	return s.svc.ImportLists(ctx, lists)
+}
+
+func (s *instrumentedService) CreateImportJob(
+	ctx context.Context, source transfer.Source, listName string, payload []byte,
+) (_ *models.ImportJob, _ bool, err error) {
+	ctx, end := startCall(ctx, "CreateImportJob")
+	defer func() { end(err) }()
+	return s.svc.CreateImportJob(ctx, source, listName, payload)
+}
+
+func (s *instrumentedService) GetImportJob(ctx context.Context, id uuid.UUID) (_ *models.ImportJob, err error) {
+	ctx, end := startCall(ctx, "GetImportJob")
+	defer func() { end(err) }()
+	return s.svc.GetImportJob(ctx, id)
+}
+
+func (s *instrumentedService) RunNextImportJob(ctx context.Context) (_ bool, err error) {
+	ctx, end := startCall(ctx, "RunNextImportJob")
+	defer func() { end(err) }()
+	return s.svc.RunNextImportJob(ctx)
+}
+
+func (s *instrumentedService) BackupUser(ctx context.Context, userID uuid.UUID) (_ *models.AccountData, err error) {
+	ctx, end := startCall(ctx, "BackupUser")
+	defer func() { end(err) }()
+	return s.svc.BackupUser(ctx, userID)
+}
+
+func (s *instrumentedService) RestoreUser(
+	ctx context.Context, userID uuid.UUID, data *models.AccountData, remapIDs bool,
+) (_ *models.RestoreResult, err error) {
+	ctx, end := startCall(ctx, "RestoreUser")
+	defer func() { end(err) }()
+	return s.svc.RestoreUser(ctx, userID, data, remapIDs)
+}
+
+func (s *instrumentedService) CreateUser(
+	ctx context.Context, username, email, timeZone string,
+) (_ *models.User, err error) {
+	ctx, end := startCall(ctx, "CreateUser")
+	defer func() { end(err) }()
+	return s.svc.CreateUser(ctx, username, email, timeZone)
+}
+
+func (s *instrumentedService) GetUser(ctx context.Context, id uuid.UUID) (_ *models.User, err error) {
+	ctx, end := startCall(ctx, "GetUser")
+	defer func() { end(err) }()
+	return s.svc.GetUser(ctx, id)
+}
+
+func (s *instrumentedService) GetNotificationPreferences(
+	ctx context.Context, userID uuid.UUID,
+) (_ *models.NotificationPreferences, err error) {
+	ctx, end := startCall(ctx, "GetNotificationPreferences")
+	defer func() { end(err) }()
+	return s.svc.GetNotificationPreferences(ctx, userID)
+}
+
+func (s *instrumentedService) UpdateNotificationPreferences(
+	ctx context.Context, prefs *models.NotificationPreferences,
+) (err error) {
+	ctx, end := startCall(ctx, "UpdateNotificationPreferences")
+	defer func() { end(err) }()
+	return s.svc.UpdateNotificationPreferences(ctx, prefs)
+}
+
+func (s *instrumentedService) CreateFeedToken(
+	ctx context.Context, userID, listID *uuid.UUID,
+) (_ *models.FeedToken, err error) {
+	ctx, end := startCall(ctx, "CreateFeedToken")
+	defer func() { end(err) }()
+	return s.svc.CreateFeedToken(ctx, userID, listID)
+}
+
+func (s *instrumentedService) ListFeedTokens(
+	ctx context.Context, userID, listID *uuid.UUID,
+) (_ []*models.FeedToken, err error) {
+	ctx, end := startCall(ctx, "ListFeedTokens")
+	defer func() { end(err) }()
+	return s.svc.ListFeedTokens(ctx, userID, listID)
+}
+
+func (s *instrumentedService) RevokeFeedToken(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, end := startCall(ctx, "RevokeFeedToken")
+	defer func() { end(err) }()
+	return s.svc.RevokeFeedToken(ctx, id)
+}
+
+func (s *instrumentedService) GetFeed(ctx context.Context, token string) (_ *models.Feed, err error) {
+	ctx, end := startCall(ctx, "GetFeed")
+	defer func() { end(err) }()
+	return s.svc.GetFeed(ctx, token)
+}
+
+func (s *instrumentedService) ListOwnedLists(ctx context.Context, ownerID uuid.UUID) (_ []*models.TodoList, err error) {
+	ctx, end := startCall(ctx, "ListOwnedLists")
+	defer func() { end(err) }()
+	return s.svc.ListOwnedLists(ctx, ownerID)
+}
+
+func (s *instrumentedService) ListCalendarObjects(
+	ctx context.Context, listID uuid.UUID,
+) (_ []*models.CalendarObject, err error) {
+	ctx, end := startCall(ctx, "ListCalendarObjects")
+	defer func() { end(err) }()
+	return s.svc.ListCalendarObjects(ctx, listID)
+}
+
+func (s *instrumentedService) GetCalendarObject(
+	ctx context.Context, listID uuid.UUID, name string,
+) (_ *models.CalendarObject, err error) {
+	ctx, end := startCall(ctx, "GetCalendarObject")
+	defer func() { end(err) }()
+	return s.svc.GetCalendarObject(ctx, listID, name)
+}
+
+func (s *instrumentedService) CreateCalendarObject(
+	ctx context.Context, listID uuid.UUID, name, uid string, todo *models.Todo,
+) (err error) {
+	ctx, end := startCall(ctx, "CreateCalendarObject")
+	defer func() { end(err) }()
+	return s.svc.CreateCalendarObject(ctx, listID, name, uid, todo)
+}
+
+func (s *instrumentedService) GetCalendarSyncToken(ctx context.Context, listID uuid.UUID) (_ int64, err error) {
+	ctx, end := startCall(ctx, "GetCalendarSyncToken")
+	defer func() { end(err) }()
+	return s.svc.GetCalendarSyncToken(ctx, listID)
+}
+
+func (s *instrumentedService) ListCalendarChanges(
+	ctx context.Context, listID uuid.UUID, since int64,
+) (_ []*models.CalendarChange, err error) {
+	ctx, end := startCall(ctx, "ListCalendarChanges")
+	defer func() { end(err) }()
+	return s.svc.ListCalendarChanges(ctx, listID, since)
+}
@@ -2,28 +2,156 @@ package service
 
 import (
 	"context"
-	"time"
+	"io"
 
 	"github.com/google/uuid"
 
 	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/quickadd"
+	"github.com/awnzl/to-do-app/internal/transfer"
 )
 
 type TodoService interface {
 	// List operations
-	CreateList(ctx context.Context, name string) (*models.TodoList, error)
+	CreateList(ctx context.Context, name string, loc models.ListLocation) (*models.TodoList, error)
 	GetList(ctx context.Context, id uuid.UUID) (*models.TodoList, error)
 	UpdateList(ctx context.Context, list *models.TodoList) error
 	DeleteList(ctx context.Context, id uuid.UUID) error
-	ListLists(ctx context.Context) ([]*models.TodoList, error)
+	ListLists(ctx context.Context, f models.ListFilter) ([]*models.TodoList, error)
+	SetListArchived(ctx context.Context, id uuid.UUID, archived bool) (*models.TodoList, error)
+	SetListAutoArchive(ctx context.Context, id uuid.UUID, days *int) (*models.TodoList, error)
+	MoveList(ctx context.Context, listID uuid.UUID, loc models.ListLocation) (*models.TodoList, error)
+
+	CloneList(ctx context.Context, listID uuid.UUID, opts models.CloneOptions) (*models.ListWithTodos, error)
+
+	// List template operations
+	CreateListTemplate(ctx context.Context, t *models.ListTemplate) error
+	GetListTemplate(ctx context.Context, id uuid.UUID) (*models.ListTemplate, error)
+	ListListTemplates(ctx context.Context) ([]*models.ListTemplate, error)
+	UpdateListTemplate(ctx context.Context, t *models.ListTemplate) error
+	DeleteListTemplate(ctx context.Context, id uuid.UUID) error
+	InstantiateListTemplate(
+		ctx context.Context, id uuid.UUID, vars map[string]string, start *models.Date, loc models.ListLocation,
+	) (*models.ListWithTodos, error)
+
+	// Workspace operations
+	CreateWorkspace(ctx context.Context, name string) (*models.Workspace, error)
+	GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	ListWorkspaces(ctx context.Context) ([]*models.Workspace, error)
+	UpdateWorkspace(ctx context.Context, id uuid.UUID, name string) (*models.Workspace, error)
+	DeleteWorkspace(ctx context.Context, id uuid.UUID) error
+	RestoreWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error)
+	AddWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMember, error)
+	CreateFolder(ctx context.Context, workspaceID uuid.UUID, name string) (*models.Folder, error)
+	ListFolders(ctx context.Context, workspaceID uuid.UUID) ([]*models.Folder, error)
+	UpdateFolder(ctx context.Context, workspaceID, folderID uuid.UUID, name string) (*models.Folder, error)
+	DeleteFolder(ctx context.Context, workspaceID, folderID uuid.UUID) error
+
+	// List member operations
+	AddListMember(ctx context.Context, listID, userID uuid.UUID) error
+	RemoveListMember(ctx context.Context, listID, userID uuid.UUID) error
+	ListListMembers(ctx context.Context, listID uuid.UUID) ([]*models.ListMember, error)
 
 	// Todo operations
-	CreateTodo(ctx context.Context, listID uuid.UUID, title, description string, dueDate *time.Time) (*models.Todo, error)
+	CreateTodo(ctx context.Context, listID uuid.UUID, title, description string, schedule models.Schedule) (*models.Todo, error)
+	SetTodoSchedule(ctx context.Context, todo *models.Todo, schedule models.Schedule) error
+	QuickAddTodo(ctx context.Context, listID uuid.UUID, text string) (*models.Todo, *quickadd.Result, error)
 	GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error)
 	UpdateTodo(ctx context.Context, todo *models.Todo) error
 	MoveTodoToList(ctx context.Context, todoID, newListID uuid.UUID) error
-	CompleteTodo(ctx context.Context, todoID uuid.UUID) error
+	CompleteTodo(ctx context.Context, todoID uuid.UUID, force bool) error
 	DeleteTodo(ctx context.Context, id uuid.UUID) error
-	ListTodos(ctx context.Context, listID uuid.UUID) ([]*models.Todo, error)
+	ListTodos(ctx context.Context, listID uuid.UUID, archived bool) ([]*models.Todo, error)
+	ArchiveCompletedTodos(ctx context.Context) (int64, error)
 	ListOverdueTodos(ctx context.Context) ([]*models.Todo, error)
+
+	// Dependency operations
+	AddTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) error
+	RemoveTodoDependency(ctx context.Context, todoID, blockerID uuid.UUID) error
+	ListTodoDependencies(ctx context.Context, todoID uuid.UUID) (*models.TodoDependencies, error)
+	GetDependencyGraph(ctx context.Context, listID uuid.UUID) (*models.DependencyGraph, error)
+	NextTodos(ctx context.Context, listID uuid.UUID) ([]*models.NextTodo, error)
+
+	// Board operations
+	CreateBoardColumn(ctx context.Context, listID uuid.UUID, name string, wipLimit *int) (*models.BoardColumn, error)
+	ListBoardColumns(ctx context.Context, listID uuid.UUID) ([]*models.BoardColumn, error)
+	UpdateBoardColumn(
+		ctx context.Context, listID, columnID uuid.UUID, name string, wipLimit, position *int,
+	) (*models.BoardColumn, error)
+	DeleteBoardColumn(ctx context.Context, listID, columnID uuid.UUID) error
+	GetBoard(ctx context.Context, listID uuid.UUID) (*models.Board, error)
+	MoveTodoToColumn(ctx context.Context, todoID uuid.UUID, columnID *uuid.UUID, position *int) error
+
+	// Assignee operations
+	AssignTodo(ctx context.Context, todoID, userID uuid.UUID) error
+	UnassignTodo(ctx context.Context, todoID, userID uuid.UUID) error
+	ListAssignedTodos(ctx context.Context, userID uuid.UUID) ([]*models.Todo, error)
+
+	// Attachment operations
+	AddAttachment(
+		ctx context.Context, todoID uuid.UUID, filename, contentType string, size int64, checksum string, r io.Reader,
+	) (*models.Attachment, error)
+	ListAttachments(ctx context.Context, todoID uuid.UUID) ([]*models.Attachment, error)
+	OpenAttachment(ctx context.Context, todoID, id uuid.UUID) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, todoID, id uuid.UUID) error
+	CleanupBlobs(ctx context.Context) (int, error)
+
+	// View operations
+	CreateView(ctx context.Context, name, query string) (*models.View, error)
+	ListViews(ctx context.Context) ([]*models.View, error)
+	GetView(ctx context.Context, id uuid.UUID) (*models.View, error)
+	UpdateView(ctx context.Context, id uuid.UUID, name, query string) (*models.View, error)
+	DeleteView(ctx context.Context, id uuid.UUID) error
+	ListViewTodos(ctx context.Context, id uuid.UUID, limit, offset int) (*models.TodoPage, error)
+
+	// Agenda operations
+	Agenda(ctx context.Context) (*models.Agenda, error)
+
+	// Stats operations
+	Stats(ctx context.Context, listID *uuid.UUID, from, to *models.Date) (*models.Stats, error)
+	Burndown(ctx context.Context, listID uuid.UUID, from, to *models.Date) (*models.Burndown, error)
+
+	// Comment operations
+	AddComment(ctx context.Context, todoID uuid.UUID, parentID *uuid.UUID, body string) (*models.Comment, error)
+	ListComments(ctx context.Context, todoID uuid.UUID) ([]*models.Comment, error)
+	EditComment(ctx context.Context, todoID, id uuid.UUID, body string) (*models.Comment, error)
+	DeleteComment(ctx context.Context, todoID, id uuid.UUID) error
+	ListCommentRevisions(ctx context.Context, todoID, id uuid.UUID) ([]*models.CommentRevision, error)
+
+	// Import and export operations
+	ExportList(ctx context.Context, listID uuid.UUID) (*models.ListWithTodos, error)
+	ImportLists(ctx context.Context, lists []*models.ListWithTodos) error
+	CreateImportJob(
+		ctx context.Context, source transfer.Source, listName string, payload []byte,
+	) (*models.ImportJob, bool, error)
+	GetImportJob(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	RunNextImportJob(ctx context.Context) (bool, error)
+
+	// Backup operations
+	BackupUser(ctx context.Context, userID uuid.UUID) (*models.AccountData, error)
+	RestoreUser(
+		ctx context.Context, userID uuid.UUID, data *models.AccountData, remapIDs bool,
+	) (*models.RestoreResult, error)
+
+	// User operations
+	CreateUser(ctx context.Context, username, email, timeZone string) (*models.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+
+	// Calendar feed operations
+	CreateFeedToken(ctx context.Context, userID, listID *uuid.UUID) (*models.FeedToken, error)
+	ListFeedTokens(ctx context.Context, userID, listID *uuid.UUID) ([]*models.FeedToken, error)
+	RevokeFeedToken(ctx context.Context, id uuid.UUID) error
+	GetFeed(ctx context.Context, token string) (*models.Feed, error)
+
+	// CalDAV operations
+	ListOwnedLists(ctx context.Context, ownerID uuid.UUID) ([]*models.TodoList, error)
+	ListCalendarObjects(ctx context.Context, listID uuid.UUID) ([]*models.CalendarObject, error)
+	GetCalendarObject(ctx context.Context, listID uuid.UUID, name string) (*models.CalendarObject, error)
+	CreateCalendarObject(ctx context.Context, listID uuid.UUID, name, uid string, todo *models.Todo) error
+	GetCalendarSyncToken(ctx context.Context, listID uuid.UUID) (int64, error)
+	ListCalendarChanges(ctx context.Context, listID uuid.UUID, since int64) ([]*models.CalendarChange, error)
 }
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fencedCode = regexp.MustCompile("(?s)```.*?(```|$)")
+	inlineCode = regexp.MustCompile("`[^`\n]*`")
+	// a mention starts at a word boundary so email addresses do not count
+	mention = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+)
+
+// parseMentions returns the usernames mentioned as @username in a Markdown
+// body, in order of first appearance. Mentions inside code are ignored.
+func parseMentions(body string) []string {
+	body = fencedCode.ReplaceAllString(body, " ")
+	body = inlineCode.ReplaceAllString(body, " ")
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range mention.FindAllStringSubmatch(body, -1) {
+		// punctuation ending a sentence is not part of the name
+		name := strings.TrimRight(m[1], ".-")
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/quickadd"
+)
+
+// QuickAddTodo creates a todo from a line of text such as "Pay rent every
+// month on the 1st #home !high" and returns it with the interpretation of
+// the text. Relative dates are resolved in the caller's time zone, UTC for
+// requests without a user. The todo is checked like one created by
+// CreateTodo and fails with ErrInvalidTodo where that would.
+func (s *todoService) QuickAddTodo(
+	ctx context.Context, listID uuid.UUID, text string,
+) (*models.Todo, *quickadd.Result, error) {
+	loc := time.UTC
+	if userID, ok := identity.UserID(ctx); ok {
+		user, err := s.repo.GetUser(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		loc = user.Location()
+	}
+
+	parsed, err := quickadd.Parse(text, time.Now().In(loc))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	todo := &models.Todo{
+		ListID:     listID,
+		Title:      parsed.Title,
+		DueDate:    parsed.DueDate,
+		DueOn:      parsed.DueOn,
+		Tags:       parsed.Tags,
+		Priority:   parsed.Priority,
+		Recurrence: parsed.Recurrence,
+	}
+	if err := validateTodo(todo); err != nil {
+		return nil, nil, err
+	}
+	err = s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetList(ctx, listID); err != nil {
+			return err
+		}
+		return s.repo.InsertTodo(ctx, todo)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return todo, parsed, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// insertRepo holds one list without an owner and keeps the todos inserted
+// into it.
+type insertRepo struct {
+	repository.Repository
+	list     *models.TodoList
+	inserted []*models.Todo
+}
+
+func (r *insertRepo) GetList(context.Context, uuid.UUID) (*models.TodoList, error) {
+	return r.list, nil
+}
+
+func (r *insertRepo) InsertTodo(_ context.Context, todo *models.Todo) error {
+	r.inserted = append(r.inserted, todo)
+	return nil
+}
+
+func TestQuickAddIsValidatedLikeCreate(t *testing.T) {
+	repo := &insertRepo{list: &models.TodoList{ID: uuid.New()}}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+	long := strings.Repeat("a", models.MaxTitleLength+1)
+
+	_, err := svc.CreateTodo(context.Background(), repo.list.ID, long, "", models.Schedule{})
+	assert.ErrorIs(t, err, ErrInvalidTodo)
+	_, _, err = svc.QuickAddTodo(context.Background(), repo.list.ID, long+" tomorrow")
+	assert.ErrorIs(t, err, ErrInvalidTodo)
+	_, err = svc.CreateTodo(context.Background(), repo.list.ID, " ", "", models.Schedule{})
+	assert.ErrorIs(t, err, ErrInvalidTodo)
+	_, err = svc.CreateTodo(context.Background(), repo.list.ID, "Pay rent", "", models.Schedule{TimeZone: "Local"})
+	assert.ErrorIs(t, err, ErrInvalidTodo)
+	assert.Empty(t, repo.inserted)
+
+	todo, _, err := svc.QuickAddTodo(context.Background(), repo.list.ID, "Pay rent every month !high")
+	require.NoError(t, err)
+	assert.Equal(t, models.PriorityHigh, todo.Priority)
+	assert.Len(t, repo.inserted, 1)
+}
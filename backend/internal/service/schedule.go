@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// SetTodoSchedule sets when a todo is due and planned without saving it. A
+// due time given without a time zone is read in the time zone of the list
+// owner, UTC for lists without one, the same zone all-day todos are
+// evaluated in.
+func (s *todoService) SetTodoSchedule(ctx context.Context, todo *models.Todo, schedule models.Schedule) error {
+	loc := time.UTC
+	if schedule.DueTime != "" && schedule.TimeZone == "" {
+		list, err := s.repo.GetList(ctx, todo.ListID)
+		if err != nil {
+			return err
+		}
+		if loc, err = s.ownerLocation(ctx, list); err != nil {
+			return err
+		}
+	}
+
+	schedule.Apply(todo, loc)
+	return nil
+}
+
+// ownerLocation returns the time zone of a list's owner, UTC for lists
+// without one.
+func (s *todoService) ownerLocation(ctx context.Context, list *models.TodoList) (*time.Location, error) {
+	if list.OwnerID == nil {
+		return time.UTC, nil
+	}
+	owner, err := s.repo.GetUser(ctx, *list.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return owner.Location(), nil
+}
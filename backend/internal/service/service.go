@@ -3,33 +3,52 @@ package service
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/awnzl/to-do-app/internal/identity"
 	"github.com/awnzl/to-do-app/internal/models"
 	"github.com/awnzl/to-do-app/internal/repository"
+	"github.com/awnzl/to-do-app/internal/storage"
 )
 
 type todoService struct {
-	repo repository.Repository
-	txm  repository.TransactionManager
+	repo  repository.Repository
+	txm   repository.TransactionManager
+	blobs storage.BlobStore
 }
 
-func NewTodoService(repo repository.Repository, txm repository.TransactionManager) *todoService {
+func NewTodoService(
+	repo repository.Repository, txm repository.TransactionManager, blobs storage.BlobStore,
+) *todoService {
 	return &todoService{
-		repo: repo,
-		txm:  txm,
+		repo:  repo,
+		txm:   txm,
+		blobs: blobs,
 	}
 }
 
-func (s *todoService) CreateList(ctx context.Context, name string) (*models.TodoList, error) {
+// CreateList creates a list owned by the calling user at a location.
+func (s *todoService) CreateList(ctx context.Context, name string, loc models.ListLocation) (*models.TodoList, error) {
 	var list *models.TodoList
 	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
 		var err error
-		list, err = s.repo.CreateList(ctx, name)
-		return err
+		if loc, err = s.resolveLocation(ctx, loc); err != nil {
+			return err
+		}
+		var ownerID *uuid.UUID
+		if userID, ok := identity.UserID(ctx); ok {
+			ownerID = &userID
+		}
+		if list, err = s.repo.CreateList(ctx, name, ownerID); err != nil {
+			return err
+		}
+		if loc.WorkspaceID == nil {
+			return nil
+		}
+		list.WorkspaceID, list.FolderID = loc.WorkspaceID, loc.FolderID
+		return s.repo.SetListLocation(ctx, list.ID, loc)
 	})
 	return list, err
 }
@@ -51,21 +70,54 @@ func (s *todoService) DeleteList(ctx context.Context, id uuid.UUID) error {
 	})
 }
 
-func (s *todoService) ListLists(ctx context.Context) ([]*models.TodoList, error) {
+// ListLists returns the lists matching f. Lists of deleted workspaces are
+// left out.
+func (s *todoService) ListLists(ctx context.Context, f models.ListFilter) ([]*models.TodoList, error) {
 	// read operations don't need transactions
-	return s.repo.ListLists(ctx)
+	if f.WorkspaceID != nil {
+		if _, err := s.repo.GetWorkspace(ctx, *f.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+	if f.FolderID != nil {
+		if _, err := s.repo.GetFolder(ctx, *f.FolderID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListLists(ctx, f)
 }
 
+// CreateTodo creates a todo in a list. A todo the API would not accept,
+// such as one without a title, fails with ErrInvalidTodo.
 func (s *todoService) CreateTodo(
-	ctx context.Context, listID uuid.UUID, title, description string, dueDate *time.Time,
+	ctx context.Context, listID uuid.UUID, title, description string, schedule models.Schedule,
 ) (*models.Todo, error) {
-	var newTodo *models.Todo
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTodo, err)
+	}
+	todo := &models.Todo{ListID: listID, Title: title, Description: description}
 	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
-		var err error
-		newTodo, err = s.repo.CreateTodo(ctx, listID, title, description, dueDate)
-		return err
+		if err := s.SetTodoSchedule(ctx, todo, schedule); err != nil {
+			return err
+		}
+		if err := validateTodo(todo); err != nil {
+			return err
+		}
+		return s.repo.InsertTodo(ctx, todo)
 	})
-	return newTodo, err
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// validateTodo checks a todo before it is saved and wraps the reason it is
+// rejected in ErrInvalidTodo.
+func validateTodo(todo *models.Todo) error {
+	if err := todo.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTodo, err)
+	}
+	return nil
 }
 
 func (s *todoService) GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo, error) {
@@ -73,30 +125,53 @@ func (s *todoService) GetTodo(ctx context.Context, id uuid.UUID) (*models.Todo,
 	return s.repo.GetTodo(ctx, id)
 }
 
+// UpdateTodo saves a todo. Completing it is rejected with ErrOpenBlockers
+// while todos it depends on are open.
 func (s *todoService) UpdateTodo(ctx context.Context, todo *models.Todo) error {
 	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if todo.Status {
+			current, err := s.repo.GetTodo(ctx, todo.ID)
+			if err != nil {
+				return err
+			}
+			if !current.Status {
+				if err := s.checkBlockers(ctx, todo.ID); err != nil {
+					return err
+				}
+			}
+		}
 		return s.repo.UpdateTodo(ctx, todo)
 	})
 }
 
+// MoveTodoToList moves a todo to another list. The change log records it
+// as deleted from the list it leaves.
 func (s *todoService) MoveTodoToList(ctx context.Context, todoID, newListID uuid.UUID) error {
-	todo, err := s.GetTodo(ctx, todoID)
-	if err != nil {
+	if _, err := s.GetTodo(ctx, todoID); err != nil {
 		return fmt.Errorf("getting todo '%s': %w", todoID.String(), err)
 	}
-	todo.ListID = newListID
 	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
-		return s.repo.UpdateTodo(ctx, todo)
+		if _, err := s.repo.GetList(ctx, newListID); err != nil {
+			return err
+		}
+		return s.repo.MoveTodo(ctx, todoID, newListID)
 	})
 }
 
-func (s *todoService) CompleteTodo(ctx context.Context, todoID uuid.UUID) error {
+// CompleteTodo marks a todo as done. Unless forced, it is rejected with
+// ErrOpenBlockers while todos it depends on are open.
+func (s *todoService) CompleteTodo(ctx context.Context, todoID uuid.UUID, force bool) error {
 	todo, err := s.GetTodo(ctx, todoID)
 	if err != nil {
 		return fmt.Errorf("getting todo '%s': %w", todoID.String(), err)
 	}
 	todo.Status = true
 	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if !force {
+			if err := s.checkBlockers(ctx, todoID); err != nil {
+				return err
+			}
+		}
 		return s.repo.UpdateTodo(ctx, todo)
 	})
 }
@@ -107,9 +182,11 @@ func (s *todoService) DeleteTodo(ctx context.Context, id uuid.UUID) error {
 	})
 }
 
-func (s *todoService) ListTodos(ctx context.Context, listID uuid.UUID) ([]*models.Todo, error) {
+// ListTodos returns the todos of a list, with the archived ones only when
+// archived is set.
+func (s *todoService) ListTodos(ctx context.Context, listID uuid.UUID, archived bool) ([]*models.Todo, error) {
 	// read operations don't need transactions
-	return s.repo.ListTodos(ctx, listID)
+	return s.repo.ListTodos(ctx, listID, archived)
 }
 
 func (s *todoService) ListOverdueTodos(ctx context.Context) ([]*models.Todo, error) {
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/awnzl/to-do-app/internal/repository"
 )
 
 func TestNew(t *testing.T) {
@@ -11,3 +14,11 @@ func TestNew(t *testing.T) {
 
 	//TODO AW: add testing for NewTodoService
 }
+
+// fakeTxManager runs transactions without a database, for services backed
+// by a fake repository.
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithTransaction(ctx context.Context, fn repository.TxFn) error {
+	return fn(ctx, nil)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// MaxStatsDays is the longest range stats are summed up over.
+const MaxStatsDays = 366
+
+// defaultStatsDays is the range, ending today, of stats without one.
+const defaultStatsDays = 30
+
+// Stats sums up the activity in the lists the calling user can access, or
+// only in listID, per list and per assignee from from to to in the user's
+// time zone. Without from the range spans the 30 days up to to, which
+// defaults to today.
+func (s *todoService) Stats(ctx context.Context, listID *uuid.UUID, from, to *models.Date) (*models.Stats, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	// read operations don't need transactions
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if listID != nil {
+		if _, err := s.repo.GetList(ctx, *listID); err != nil {
+			return nil, err
+		}
+	}
+
+	loc := user.Location()
+	today := models.DateOf(time.Now().In(loc))
+	q := models.StatsQuery{UserID: userID, ListID: listID, TimeZone: loc.String()}
+	if q.From, q.To, err = statsRange(from, to, today); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListStats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return buildStats(rows, q, today), nil
+}
+
+// Burndown counts the open and done todos in a list at the end of every
+// day from from to to in the calling user's time zone, with the same
+// defaults as Stats. Lists the user cannot access fail with
+// ErrListNotFound, as if they did not exist.
+func (s *todoService) Burndown(ctx context.Context, listID uuid.UUID, from, to *models.Date) (*models.Burndown, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	// read operations don't need transactions
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetList(ctx, listID); err != nil {
+		return nil, err
+	}
+	access, err := s.repo.HasListAccess(ctx, listID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access {
+		return nil, repository.ErrListNotFound
+	}
+
+	loc := user.Location()
+	burndown := &models.Burndown{ListID: listID, TimeZone: loc.String()}
+	if burndown.From, burndown.To, err = statsRange(from, to, models.DateOf(time.Now().In(loc))); err != nil {
+		return nil, err
+	}
+	burndown.Days, err = s.repo.ListBurndown(ctx, listID, burndown.From, burndown.To, burndown.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return burndown, nil
+}
+
+// statsRange fills in the missing ends of a range of days: to defaults to
+// today and from to 30 days up to to.
+func statsRange(from, to *models.Date, today models.Date) (models.Date, models.Date, error) {
+	last := today
+	if to != nil {
+		last = *to
+	}
+	first := last.AddDays(1 - defaultStatsDays)
+	if from != nil {
+		first = *from
+	}
+	if last.Before(first) || !last.Before(first.AddDays(MaxStatsDays)) {
+		return models.Date{}, models.Date{}, ErrInvalidStatsRange
+	}
+	return first, last, nil
+}
+
+// buildStats groups the rows of each list and user, in their order, and
+// sums them up.
+func buildStats(rows []*models.StatsRow, q models.StatsQuery, today models.Date) *models.Stats {
+	stats := &models.Stats{
+		From:     q.From,
+		To:       q.To,
+		TimeZone: q.TimeZone,
+		Lists:    []*models.GroupStats{},
+		Users:    []*models.GroupStats{},
+	}
+	var group *models.GroupStats
+	for _, row := range rows {
+		if group == nil || !sameID(group.ListID, row.ListID) || !sameID(group.UserID, row.UserID) {
+			group = &models.GroupStats{ListID: row.ListID, UserID: row.UserID, Name: row.Name}
+			if row.UserID != nil {
+				stats.Users = append(stats.Users, group)
+			} else {
+				stats.Lists = append(stats.Lists, group)
+			}
+		}
+		day := row.StatsDay
+		group.Days = append(group.Days, &day)
+	}
+	for _, group := range append(stats.Lists, stats.Users...) {
+		sumUp(group, today)
+	}
+	return stats
+}
+
+// sumUp totals the days of a group and finds its completion streaks.
+func sumUp(group *models.GroupStats, today models.Date) {
+	var seconds float64
+	streak := 0
+	for _, day := range group.Days {
+		group.Created += day.Created
+		group.Completed += day.Completed
+		seconds += day.CompletionSeconds
+		if day.Completed == 0 {
+			streak = 0
+			continue
+		}
+		streak++
+		group.LongestStreak = max(group.LongestStreak, streak)
+	}
+	if group.Completed > 0 {
+		hours := seconds / float64(group.Completed) / time.Hour.Seconds()
+		group.AverageCompletionHours = &hours
+	}
+
+	days := group.Days
+	if n := len(days); n > 0 && days[n-1].Day == today && days[n-1].Completed == 0 {
+		// today still has time for a completion
+		days = days[:n-1]
+	}
+	for i := len(days) - 1; i >= 0 && days[i].Completed > 0; i-- {
+		group.CurrentStreak++
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+func TestBuildStats(t *testing.T) {
+	day := func(d int) models.Date {
+		return models.Date{Year: 2025, Month: time.March, Day: d}
+	}
+	listID, userID := uuid.New(), uuid.New()
+	q := models.StatsQuery{From: day(10), To: day(14), TimeZone: "Europe/Berlin"}
+	// completions per day of the list and of the user
+	listDone := []int{1, 0, 2, 1, 1}
+	userDone := []int{1, 1, 1, 0, 0}
+
+	var rows []*models.StatsRow
+	for i, done := range listDone {
+		rows = append(rows, &models.StatsRow{ListID: &listID, Name: "work", StatsDay: models.StatsDay{
+			Day: day(10 + i), Created: 1, Completed: done, Overdue: i, CompletionSeconds: float64(done) * 3600,
+		}})
+	}
+	for i, done := range userDone {
+		rows = append(rows, &models.StatsRow{UserID: &userID, Name: "ada", StatsDay: models.StatsDay{
+			Day: day(10 + i), Completed: done, CompletionSeconds: float64(done) * 7200,
+		}})
+	}
+
+	stats := buildStats(rows, q, day(14))
+
+	assert.Equal(t, day(10), stats.From)
+	assert.Equal(t, day(14), stats.To)
+	assert.Equal(t, "Europe/Berlin", stats.TimeZone)
+	require.Len(t, stats.Lists, 1)
+	require.Len(t, stats.Users, 1)
+
+	list := stats.Lists[0]
+	assert.Equal(t, &listID, list.ListID)
+	assert.Nil(t, list.UserID)
+	assert.Equal(t, "work", list.Name)
+	assert.Equal(t, 5, list.Created)
+	assert.Equal(t, 5, list.Completed)
+	require.NotNil(t, list.AverageCompletionHours)
+	assert.InDelta(t, 1, *list.AverageCompletionHours, 1e-9)
+	assert.Equal(t, 3, list.CurrentStreak)
+	assert.Equal(t, 3, list.LongestStreak)
+	require.Len(t, list.Days, 5)
+	assert.Equal(t, 4, list.Days[4].Overdue)
+
+	user := stats.Users[0]
+	assert.Equal(t, &userID, user.UserID)
+	assert.Equal(t, "ada", user.Name)
+	assert.Equal(t, 3, user.Completed)
+	require.NotNil(t, user.AverageCompletionHours)
+	assert.InDelta(t, 2, *user.AverageCompletionHours, 1e-9)
+	assert.Equal(t, 0, user.CurrentStreak)
+	assert.Equal(t, 3, user.LongestStreak)
+}
+
+func TestBuildStatsStreakSpansToday(t *testing.T) {
+	day := func(d int) models.Date {
+		return models.Date{Year: 2025, Month: time.March, Day: d}
+	}
+	listID := uuid.New()
+	rows := []*models.StatsRow{
+		{ListID: &listID, StatsDay: models.StatsDay{Day: day(12), Completed: 1}},
+		{ListID: &listID, StatsDay: models.StatsDay{Day: day(13), Completed: 2}},
+		{ListID: &listID, StatsDay: models.StatsDay{Day: day(14)}},
+	}
+
+	stats := buildStats(rows, models.StatsQuery{From: day(12), To: day(14)}, day(14))
+
+	require.Len(t, stats.Lists, 1)
+	assert.Equal(t, 2, stats.Lists[0].CurrentStreak)
+
+	stats = buildStats(rows, models.StatsQuery{From: day(12), To: day(14)}, day(15))
+
+	assert.Equal(t, 0, stats.Lists[0].CurrentStreak)
+	assert.Equal(t, 2, stats.Lists[0].LongestStreak)
+}
+
+func TestBuildStatsEmpty(t *testing.T) {
+	stats := buildStats(nil, models.StatsQuery{}, models.Date{})
+
+	assert.Empty(t, stats.Lists)
+	assert.NotNil(t, stats.Lists)
+	assert.Empty(t, stats.Users)
+	assert.NotNil(t, stats.Users)
+}
+
+func TestStatsRange(t *testing.T) {
+	day := func(m time.Month, d int) *models.Date {
+		return &models.Date{Year: 2025, Month: m, Day: d}
+	}
+	today := *day(time.March, 14)
+
+	from, to, err := statsRange(nil, nil, today)
+	require.NoError(t, err)
+	assert.Equal(t, *day(time.February, 13), from)
+	assert.Equal(t, today, to)
+
+	from, to, err = statsRange(nil, day(time.January, 31), today)
+	require.NoError(t, err)
+	assert.Equal(t, *day(time.January, 2), from)
+	assert.Equal(t, *day(time.January, 31), to)
+
+	from, to, err = statsRange(day(time.March, 20), day(time.March, 20), today)
+	require.NoError(t, err)
+	assert.Equal(t, from, to)
+
+	_, _, err = statsRange(day(time.March, 15), nil, today)
+	assert.ErrorIs(t, err, ErrInvalidStatsRange)
+
+	_, _, err = statsRange(&models.Date{Year: 2024, Month: time.March, Day: 13}, nil, today)
+	assert.ErrorIs(t, err, ErrInvalidStatsRange)
+	_, _, err = statsRange(&models.Date{Year: 2024, Month: time.March, Day: 14}, nil, today)
+	assert.NoError(t, err)
+}
+
+// burndownRepo holds one list that only its members can access.
+type burndownRepo struct {
+	repository.Repository
+	list    *models.TodoList
+	members map[uuid.UUID]bool
+}
+
+func (r *burndownRepo) GetUser(_ context.Context, id uuid.UUID) (*models.User, error) {
+	return &models.User{ID: id, TimeZone: "UTC"}, nil
+}
+
+func (r *burndownRepo) GetList(context.Context, uuid.UUID) (*models.TodoList, error) {
+	return r.list, nil
+}
+
+func (r *burndownRepo) HasListAccess(_ context.Context, _, userID uuid.UUID) (bool, error) {
+	return r.members[userID], nil
+}
+
+func (r *burndownRepo) ListBurndown(context.Context, uuid.UUID, models.Date, models.Date, string) ([]*models.BurndownDay, error) {
+	return []*models.BurndownDay{}, nil
+}
+
+func TestBurndownNeedsListAccess(t *testing.T) {
+	member, stranger := uuid.New(), uuid.New()
+	repo := &burndownRepo{list: &models.TodoList{ID: uuid.New(), OwnerID: &member}, members: map[uuid.UUID]bool{member: true}}
+	svc := &todoService{repo: repo, txm: fakeTxManager{}}
+
+	_, err := svc.Burndown(identity.WithUserID(context.Background(), stranger), repo.list.ID, nil, nil)
+	assert.ErrorIs(t, err, repository.ErrListNotFound)
+
+	burndown, err := svc.Burndown(identity.WithUserID(context.Background(), member), repo.list.ID, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, repo.list.ID, burndown.ListID)
+}
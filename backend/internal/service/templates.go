@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// CreateListTemplate saves a template owned by the calling user.
+func (s *todoService) CreateListTemplate(ctx context.Context, t *models.ListTemplate) error {
+	t.OwnerID = actor(ctx)
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.CreateListTemplate(ctx, t)
+	})
+}
+
+func (s *todoService) GetListTemplate(ctx context.Context, id uuid.UUID) (*models.ListTemplate, error) {
+	// read operations don't need transactions
+	return s.visibleTemplate(ctx, id)
+}
+
+// ListListTemplates returns the templates of the calling user and those
+// without an owner.
+func (s *todoService) ListListTemplates(ctx context.Context) ([]*models.ListTemplate, error) {
+	// read operations don't need transactions
+	return s.repo.ListListTemplates(ctx, actor(ctx))
+}
+
+// UpdateListTemplate replaces the names and todos of a template.
+func (s *todoService) UpdateListTemplate(ctx context.Context, t *models.ListTemplate) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		current, err := s.visibleTemplate(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		t.OwnerID, t.CreatedAt = current.OwnerID, current.CreatedAt
+		return s.repo.UpdateListTemplate(ctx, t)
+	})
+}
+
+func (s *todoService) DeleteListTemplate(ctx context.Context, id uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.visibleTemplate(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.DeleteListTemplate(ctx, id)
+	})
+}
+
+// InstantiateListTemplate creates a list with the todos of a template,
+// with its placeholders filled in from vars, at loc. Due dates are counted
+// from start, today in the calling user's time zone when it is nil. A
+// *models.MissingVariablesError names the placeholders missing from vars.
+func (s *todoService) InstantiateListTemplate(
+	ctx context.Context, id uuid.UUID, vars map[string]string, start *models.Date, loc models.ListLocation,
+) (*models.ListWithTodos, error) {
+	var l *models.ListWithTodos
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		t, err := s.visibleTemplate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if start == nil {
+			zone := time.UTC
+			if userID, ok := identity.UserID(ctx); ok {
+				user, err := s.repo.GetUser(ctx, userID)
+				if err != nil {
+					return err
+				}
+				zone = user.Location()
+			}
+			today := models.DateOf(time.Now().In(zone))
+			start = &today
+		}
+
+		if l, err = t.Instantiate(vars, *start); err != nil {
+			return err
+		}
+		list, err := s.CreateList(ctx, l.Name, loc)
+		if err != nil {
+			return err
+		}
+		l.TodoList = *list
+		for _, todo := range l.Todos {
+			todo.ListID = list.ID
+			if err := s.repo.InsertTodo(ctx, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// visibleTemplate returns a template of the calling user or one without an
+// owner, ErrTemplateNotFound for the templates of other users.
+func (s *todoService) visibleTemplate(ctx context.Context, id uuid.UUID) (*models.ListTemplate, error) {
+	t, err := s.repo.GetListTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userID, ok := identity.UserID(ctx); ok && t.OwnerID != nil && *t.OwnerID != userID {
+		return nil, repository.ErrTemplateNotFound
+	}
+	return t, nil
+}
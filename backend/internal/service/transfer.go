@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (s *todoService) ExportList(ctx context.Context, listID uuid.UUID) (*models.ListWithTodos, error) {
+	list, err := s.repo.GetList(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, err := s.repo.ListTodos(ctx, listID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListWithTodos{TodoList: *list, Todos: todos}, nil
+}
+
+// ImportLists creates the lists together with their todos in a single
+// transaction. The passed lists and todos are updated with the stored
+// values.
+func (s *todoService) ImportLists(ctx context.Context, lists []*models.ListWithTodos) error {
+	var ownerID *uuid.UUID
+	if userID, ok := identity.UserID(ctx); ok {
+		ownerID = &userID
+	}
+
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, l := range lists {
+			created, err := s.repo.CreateList(ctx, l.Name, ownerID)
+			if err != nil {
+				return fmt.Errorf("importing list '%s': %w", l.Name, err)
+			}
+			l.TodoList = *created
+
+			for _, todo := range l.Todos {
+				todo.ID = uuid.Nil
+				todo.ListID = created.ID
+				if err := s.repo.InsertTodo(ctx, todo); err != nil {
+					return fmt.Errorf("importing todo '%s': %w", todo.Title, err)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func (s *todoService) CreateUser(ctx context.Context, username, email, timeZone string) (*models.User, error) {
+	var user *models.User
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		user, err = s.repo.CreateUser(ctx, username, email, timeZone)
+		return err
+	})
+	return user, err
+}
+
+func (s *todoService) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	// read operations don't need transactions
+	return s.repo.GetUser(ctx, id)
+}
+
+func (s *todoService) GetNotificationPreferences(
+	ctx context.Context, userID uuid.UUID,
+) (*models.NotificationPreferences, error) {
+	// read operations don't need transactions
+	return s.repo.GetNotificationPreferences(ctx, userID)
+}
+
+func (s *todoService) UpdateNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.UpdateNotificationPreferences(ctx, prefs)
+	})
+}
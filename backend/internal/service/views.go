@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/filter"
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// MaxPageSize is the largest page of todos returned at once.
+const MaxPageSize = 200
+
+// CreateView saves a filter query under a name for the calling user. The
+// query is rejected with a *filter.Error if it does not parse.
+func (s *todoService) CreateView(ctx context.Context, name, query string) (*models.View, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	if _, err := filter.Parse(query); err != nil {
+		return nil, err
+	}
+
+	v := &models.View{OwnerID: userID, Name: name, Query: query}
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.CreateView(ctx, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ListViews returns the views of the calling user.
+func (s *todoService) ListViews(ctx context.Context) ([]*models.View, error) {
+	userID, ok := identity.UserID(ctx)
+	if !ok {
+		return nil, ErrNoUser
+	}
+	// read operations don't need transactions
+	return s.repo.ListViews(ctx, userID)
+}
+
+func (s *todoService) GetView(ctx context.Context, id uuid.UUID) (*models.View, error) {
+	// read operations don't need transactions
+	return s.ownedView(ctx, id)
+}
+
+func (s *todoService) UpdateView(ctx context.Context, id uuid.UUID, name, query string) (*models.View, error) {
+	if _, err := filter.Parse(query); err != nil {
+		return nil, err
+	}
+
+	var v *models.View
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if v, err = s.ownedView(ctx, id); err != nil {
+			return err
+		}
+		v.Name, v.Query = name, query
+		return s.repo.UpdateView(ctx, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *todoService) DeleteView(ctx context.Context, id uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.ownedView(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.DeleteView(ctx, id)
+	})
+}
+
+// ListViewTodos evaluates a view and returns a page of at most limit of
+// the matching todos, starting at offset. Relative times such as today are
+// resolved in the time zone of the view's owner.
+func (s *todoService) ListViewTodos(ctx context.Context, id uuid.UUID, limit, offset int) (*models.TodoPage, error) {
+	// read operations don't need transactions
+	v, err := s.ownedView(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := filter.Parse(v.Query)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.repo.GetUser(ctx, v.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = min(max(limit, 1), MaxPageSize)
+	now := time.Now().In(owner.Location())
+	// one more than asked for tells whether there is a next page
+	todos, err := s.repo.ListFilteredTodos(ctx, v.OwnerID, f, now, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TodoPage{Todos: todos, Offset: offset}
+	if len(todos) > limit {
+		page.Todos = todos[:limit]
+		next := offset + limit
+		page.NextOffset = &next
+	}
+	return page, nil
+}
+
+// ownedView returns a view of the calling user. Views of other users are
+// reported as not found.
+func (s *todoService) ownedView(ctx context.Context, id uuid.UUID) (*models.View, error) {
+	v, err := s.repo.GetView(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userID, ok := identity.UserID(ctx); !ok || userID != v.OwnerID {
+		return nil, repository.ErrViewNotFound
+	}
+	return v, nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/awnzl/to-do-app/internal/identity"
+	"github.com/awnzl/to-do-app/internal/models"
+	"github.com/awnzl/to-do-app/internal/repository"
+)
+
+// CreateWorkspace creates a workspace owned by the calling user, who
+// becomes its first member.
+func (s *todoService) CreateWorkspace(ctx context.Context, name string) (*models.Workspace, error) {
+	w := &models.Workspace{Name: name, OwnerID: actor(ctx)}
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.repo.CreateWorkspace(ctx, w); err != nil {
+			return err
+		}
+		if w.OwnerID == nil {
+			return nil
+		}
+		_, err := s.repo.AddWorkspaceMember(ctx, w.ID, *w.OwnerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *todoService) GetWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	// read operations don't need transactions
+	return s.repo.GetWorkspace(ctx, id)
+}
+
+// ListWorkspaces returns the workspaces of the calling user and those
+// without an owner, or all workspaces when the request has no user.
+func (s *todoService) ListWorkspaces(ctx context.Context) ([]*models.Workspace, error) {
+	// read operations don't need transactions
+	var userID *uuid.UUID
+	if id, ok := identity.UserID(ctx); ok {
+		userID = &id
+	}
+	return s.repo.ListWorkspaces(ctx, userID)
+}
+
+func (s *todoService) UpdateWorkspace(ctx context.Context, id uuid.UUID, name string) (*models.Workspace, error) {
+	var w *models.Workspace
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if w, err = s.repo.GetWorkspace(ctx, id); err != nil {
+			return err
+		}
+		w.Name = name
+		return s.repo.UpdateWorkspace(ctx, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// DeleteWorkspace marks a workspace as deleted. Its lists are hidden from
+// everyone until the workspace is restored.
+func (s *todoService) DeleteWorkspace(ctx context.Context, id uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return s.repo.DeleteWorkspace(ctx, id)
+	})
+}
+
+// RestoreWorkspace brings back a deleted workspace with its folders, lists
+// and members.
+func (s *todoService) RestoreWorkspace(ctx context.Context, id uuid.UUID) (*models.Workspace, error) {
+	var w *models.Workspace
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		w, err = s.repo.RestoreWorkspace(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddWorkspaceMember adds a user to a workspace, giving them access to all
+// of its lists.
+func (s *todoService) AddWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetWorkspace(ctx, workspaceID); err != nil {
+			return err
+		}
+		if _, err := s.repo.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		_, err := s.repo.AddWorkspaceMember(ctx, workspaceID, userID)
+		return err
+	})
+}
+
+// RemoveWorkspaceMember removes a user from a workspace. The user is
+// unassigned from the todos of the lists they can no longer access.
+func (s *todoService) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := s.repo.RemoveWorkspaceMember(ctx, workspaceID, userID); err != nil {
+			return err
+		}
+		lists, err := s.repo.ListLists(ctx, models.ListFilter{WorkspaceID: &workspaceID, Archived: true})
+		if err != nil {
+			return err
+		}
+		for _, list := range lists {
+			if err := s.unassignWithoutAccess(ctx, list.ID, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *todoService) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]*models.WorkspaceMember, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWorkspaceMembers(ctx, workspaceID)
+}
+
+func (s *todoService) CreateFolder(ctx context.Context, workspaceID uuid.UUID, name string) (*models.Folder, error) {
+	f := &models.Folder{WorkspaceID: workspaceID, Name: name}
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.repo.GetWorkspace(ctx, workspaceID); err != nil {
+			return err
+		}
+		return s.repo.CreateFolder(ctx, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *todoService) ListFolders(ctx context.Context, workspaceID uuid.UUID) ([]*models.Folder, error) {
+	// read operations don't need transactions
+	if _, err := s.repo.GetWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListFolders(ctx, workspaceID)
+}
+
+func (s *todoService) UpdateFolder(
+	ctx context.Context, workspaceID, folderID uuid.UUID, name string,
+) (*models.Folder, error) {
+	var f *models.Folder
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if f, err = s.workspaceFolder(ctx, workspaceID, folderID); err != nil {
+			return err
+		}
+		f.Name = name
+		return s.repo.UpdateFolder(ctx, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DeleteFolder removes a folder from a workspace. Its lists stay in the
+// workspace, outside of any folder.
+func (s *todoService) DeleteFolder(ctx context.Context, workspaceID, folderID uuid.UUID) error {
+	return s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := s.workspaceFolder(ctx, workspaceID, folderID); err != nil {
+			return err
+		}
+		return s.repo.DeleteFolder(ctx, folderID)
+	})
+}
+
+// MoveList moves a list into a folder or workspace, or out of its
+// workspace when loc is empty. Users who lose access to the list with the
+// move are unassigned from its todos.
+func (s *todoService) MoveList(ctx context.Context, listID uuid.UUID, loc models.ListLocation) (*models.TodoList, error) {
+	var list *models.TodoList
+	err := s.txm.WithTransaction(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		if list, err = s.repo.GetList(ctx, listID); err != nil {
+			return err
+		}
+		if loc, err = s.resolveLocation(ctx, loc); err != nil {
+			return err
+		}
+
+		var leaving []*models.WorkspaceMember
+		if list.WorkspaceID != nil && !sameID(list.WorkspaceID, loc.WorkspaceID) {
+			if leaving, err = s.repo.ListWorkspaceMembers(ctx, *list.WorkspaceID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repo.SetListLocation(ctx, listID, loc); err != nil {
+			return err
+		}
+		list.WorkspaceID, list.FolderID = loc.WorkspaceID, loc.FolderID
+
+		for _, m := range leaving {
+			if err := s.unassignWithoutAccess(ctx, listID, m.UserID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// resolveLocation checks that the workspace and folder of a location exist
+// and belong together, and fills in the workspace of a folder.
+func (s *todoService) resolveLocation(ctx context.Context, loc models.ListLocation) (models.ListLocation, error) {
+	if loc.FolderID != nil {
+		f, err := s.repo.GetFolder(ctx, *loc.FolderID)
+		if err != nil {
+			return loc, err
+		}
+		if loc.WorkspaceID != nil && *loc.WorkspaceID != f.WorkspaceID {
+			return loc, ErrFolderNotInWorkspace
+		}
+		loc.WorkspaceID = &f.WorkspaceID
+	}
+	if loc.WorkspaceID != nil {
+		if _, err := s.repo.GetWorkspace(ctx, *loc.WorkspaceID); err != nil {
+			return loc, err
+		}
+	}
+	return loc, nil
+}
+
+// workspaceFolder returns a folder of a workspace, ErrFolderNotFound when
+// it belongs to another one.
+func (s *todoService) workspaceFolder(ctx context.Context, workspaceID, folderID uuid.UUID) (*models.Folder, error) {
+	f, err := s.repo.GetFolder(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if f.WorkspaceID != workspaceID {
+		return nil, repository.ErrFolderNotFound
+	}
+	return f, nil
+}
@@ -0,0 +1,23 @@
+// Package storage keeps the content of attachments outside the database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs under slash separated keys.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob
+	// stored there before.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key. It returns ErrNotFound if
+	// there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob
+	// is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,97 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+var csvHeader = []string{"list", "title", "description", "due_date", "status", "tags"}
+
+func exportCSV(w io.Writer, lists []*models.ListWithTodos) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, l := range lists {
+		for _, t := range l.Todos {
+			if err := cw.Write([]string{
+				l.Name, t.Title, t.Description, formatDue(t), strconv.FormatBool(t.Status),
+				strings.Join(t.Tags, " "),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parseCSV reads a CSV file with a header row. Only the title column is
+// required; columns are matched by name. Tags are separated by spaces.
+func (p *parser) parseCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return errors.New("csv header has no title column")
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				p.errors = append(p.errors, lineError(parseErr.Line, "%v", parseErr.Err))
+				continue
+			}
+			return fmt.Errorf("read csv: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		due, dueOn, err := parseDue(field("due_date"))
+		if err != nil {
+			p.errors = append(p.errors, lineError(line, "%v", err))
+			continue
+		}
+		var status bool
+		if s := field("status"); s != "" {
+			if status, err = strconv.ParseBool(s); err != nil {
+				p.errors = append(p.errors, lineError(line, "invalid status %q", s))
+				continue
+			}
+		}
+
+		p.add(lineError(line, ""), field("list"), &models.Todo{
+			Title:       field("title"),
+			Description: field("description"),
+			DueDate:     due,
+			DueOn:       dueOn,
+			Status:      status,
+			Tags:        parseTags(field("tags")),
+		})
+	}
+}
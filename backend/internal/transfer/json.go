@@ -0,0 +1,85 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+type jsonDocument struct {
+	Lists []jsonList `json:"lists"`
+}
+
+type jsonList struct {
+	Name  string     `json:"name"`
+	Todos []jsonTodo `json:"todos"`
+}
+
+type jsonTodo struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	DueDate     string   `json:"due_date,omitempty"`
+	Status      bool     `json:"status"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+func exportJSON(w io.Writer, lists []*models.ListWithTodos) error {
+	doc := jsonDocument{Lists: make([]jsonList, 0, len(lists))}
+	for _, l := range lists {
+		jl := jsonList{Name: l.Name, Todos: make([]jsonTodo, 0, len(l.Todos))}
+		for _, t := range l.Todos {
+			jl.Todos = append(jl.Todos, jsonTodo{
+				Title:       t.Title,
+				Description: t.Description,
+				DueDate:     formatDue(t),
+				Status:      t.Status,
+				Tags:        t.Tags,
+			})
+		}
+		doc.Lists = append(doc.Lists, jl)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func (p *parser) parseJSON(r io.Reader) error {
+	var doc jsonDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("decode json: %w", err)
+	}
+
+	for i, jl := range doc.Lists {
+		if len(jl.Todos) == 0 {
+			// empty lists are imported as well
+			if msg := validateList(jl.Name); msg != "" {
+				p.errors = append(p.errors, RowError{Location: fmt.Sprintf("lists[%d]", i), Message: msg})
+			} else {
+				p.list(jl.Name)
+			}
+			continue
+		}
+		for j, jt := range jl.Todos {
+			location := RowError{Location: fmt.Sprintf("lists[%d].todos[%d]", i, j)}
+			due, dueOn, err := parseDue(jt.DueDate)
+			if err != nil {
+				location.Message = err.Error()
+				p.errors = append(p.errors, location)
+				continue
+			}
+			p.add(location, jl.Name, &models.Todo{
+				Title:       jt.Title,
+				Description: jt.Description,
+				DueDate:     due,
+				DueOn:       dueOn,
+				Status:      jt.Status,
+				Tags:        jt.Tags,
+			})
+		}
+	}
+
+	return nil
+}
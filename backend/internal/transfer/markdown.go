@@ -0,0 +1,110 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+var (
+	mdHeading = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+	mdItem    = regexp.MustCompile(`^[-*+]\s+\[([ xX])\]\s*(.*)$`)
+	mdDue     = regexp.MustCompile(`\s*\(due ([^)]*)\)$`)
+)
+
+// exportMarkdown writes one heading per list and a checklist item per todo.
+// Due dates follow the title in parentheses, descriptions are indented
+// below the item.
+func exportMarkdown(w io.Writer, lists []*models.ListWithTodos) error {
+	bw := bufio.NewWriter(w)
+	for i, l := range lists {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "# %s\n\n", l.Name)
+		for _, t := range l.Todos {
+			mark := " "
+			if t.Status {
+				mark = "x"
+			}
+			fmt.Fprintf(bw, "- [%s] %s", mark, t.Title)
+			if due := formatDue(t); due != "" {
+				fmt.Fprintf(bw, " (due %s)", due)
+			}
+			bw.WriteString("\n")
+			for _, line := range strings.Split(t.Description, "\n") {
+				if line != "" {
+					fmt.Fprintf(bw, "  %s\n", line)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// parseMarkdown reads checklists. Headings start a new list, indented lines
+// below an item become its description and other text is ignored.
+func (p *parser) parseMarkdown(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	listName := ""
+	var current *models.Todo
+	var description []string
+	var currentLine int
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Description = strings.Join(description, "\n")
+		p.add(lineError(currentLine, ""), listName, current)
+		current, description = nil, nil
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+
+		if current != nil && trimmed != "" && (strings.HasPrefix(text, "  ") || strings.HasPrefix(text, "\t")) {
+			description = append(description, trimmed)
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			listName = m[1]
+			if msg := validateList(listName); msg != "" {
+				p.errors = append(p.errors, lineError(line, "%s", msg))
+			} else {
+				p.list(listName)
+			}
+			continue
+		}
+
+		if m := mdItem.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			todo := &models.Todo{Title: m[2], Status: m[1] != " "}
+			if due := mdDue.FindStringSubmatch(todo.Title); due != nil {
+				dueDate, dueOn, err := parseDue(due[1])
+				if err != nil {
+					p.errors = append(p.errors, lineError(line, "%v", err))
+					continue
+				}
+				todo.DueDate, todo.DueOn = dueDate, dueOn
+				todo.Title = strings.TrimSuffix(todo.Title, due[0])
+			}
+			current, currentLine = todo, line
+			continue
+		}
+
+		if trimmed != "" {
+			flush()
+		}
+	}
+	flush()
+
+	return scanner.Err()
+}
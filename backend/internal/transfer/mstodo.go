@@ -0,0 +1,114 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// msToDoExport holds task lists with their tasks as returned by the
+// Microsoft Graph To Do API, either wrapped in an object or as a bare
+// array.
+type msToDoExport struct {
+	Lists []msToDoList `json:"lists"`
+}
+
+type msToDoList struct {
+	DisplayName string       `json:"displayName"`
+	Tasks       []msToDoTask `json:"tasks"`
+}
+
+type msToDoTask struct {
+	Title          string            `json:"title"`
+	Status         string            `json:"status"`
+	Body           *msToDoBody       `json:"body"`
+	DueDateTime    *msToDoDateTime   `json:"dueDateTime"`
+	Categories     []string          `json:"categories"`
+	ChecklistItems []msToDoCheckItem `json:"checklistItems"`
+}
+
+type msToDoBody struct {
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+}
+
+type msToDoDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type msToDoCheckItem struct {
+	DisplayName string `json:"displayName"`
+	IsChecked   bool   `json:"isChecked"`
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// parseMicrosoftToDo imports task lists. Categories become tags and the
+// steps of a task are appended to its description as a checklist.
+func (p *parser) parseMicrosoftToDo(data []byte) error {
+	var doc msToDoExport
+	if isJSON(data) && strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &doc.Lists); err != nil {
+			return fmt.Errorf("decode microsoft to do export: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decode microsoft to do export: %w", err)
+	}
+
+	for i, l := range doc.Lists {
+		if msg := validateList(l.DisplayName); msg != "" {
+			p.errors = append(p.errors, RowError{Location: fmt.Sprintf("lists[%d]", i), Message: msg})
+			continue
+		}
+		p.list(l.DisplayName)
+
+		for j, task := range l.Tasks {
+			location := RowError{Location: fmt.Sprintf("lists[%d].tasks[%d]", i, j)}
+			todo := &models.Todo{
+				Title:  strings.TrimSpace(task.Title),
+				Status: task.Status == "completed",
+				Tags:   labelTags(task.Categories),
+			}
+			if task.DueDateTime != nil {
+				due, err := parseLocalDue(
+					task.DueDateTime.DateTime, loadLocation(task.DueDateTime.TimeZone), "2006-01-02T15:04:05.9999999",
+				)
+				if err != nil {
+					location.Message = err.Error()
+					p.errors = append(p.errors, location)
+					continue
+				}
+				todo.DueDate = due
+			}
+
+			var desc strings.Builder
+			if task.Body != nil {
+				content := task.Body.Content
+				if strings.EqualFold(task.Body.ContentType, "html") {
+					content = html.UnescapeString(htmlTag.ReplaceAllString(content, " "))
+				}
+				desc.WriteString(strings.TrimSpace(content))
+			}
+			for k, step := range task.ChecklistItems {
+				if k == 0 && desc.Len() > 0 {
+					desc.WriteString("\n\n")
+				}
+				mark := " "
+				if step.IsChecked {
+					mark = "x"
+				}
+				fmt.Fprintf(&desc, "- [%s] %s\n", mark, step.DisplayName)
+			}
+			todo.Description = strings.TrimSpace(desc.String())
+
+			p.add(location, l.DisplayName, todo)
+		}
+	}
+
+	return nil
+}
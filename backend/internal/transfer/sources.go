@@ -0,0 +1,115 @@
+package transfer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// Source is another todo application whose export files can be imported.
+type Source string
+
+const (
+	SourceTodoist       Source = "todoist"
+	SourceTrello        Source = "trello"
+	SourceMicrosoftToDo Source = "mstodo"
+)
+
+func LookupSource(s string) (Source, error) {
+	switch strings.ToLower(s) {
+	case "todoist":
+		return SourceTodoist, nil
+	case "trello":
+		return SourceTrello, nil
+	case "mstodo", "microsoft-todo", "microsoft_todo", "ms-todo":
+		return SourceMicrosoftToDo, nil
+	default:
+		return "", fmt.Errorf("unsupported source %q", s)
+	}
+}
+
+// ParseExport reads an export file of src. Projects and boards become
+// lists, tasks and cards become todos and labels become tags. As with Parse,
+// invalid entries are left out and reported as RowErrors. Todos without a
+// project, such as the rows of a Todoist CSV export, go to defaultList.
+func ParseExport(r io.Reader, src Source, defaultList string) ([]*models.ListWithTodos, []RowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &parser{defaultList: defaultList}
+	switch src {
+	case SourceTodoist:
+		if isJSON(data) {
+			err = p.parseTodoistJSON(data)
+		} else {
+			err = p.parseTodoistCSV(bytes.NewReader(data))
+		}
+	case SourceTrello:
+		err = p.parseTrello(data)
+	case SourceMicrosoftToDo:
+		err = p.parseMicrosoftToDo(data)
+	default:
+		err = fmt.Errorf("unsupported source %q", src)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.lists, p.errors, nil
+}
+
+func isJSON(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && (data[0] == '{' || data[0] == '[')
+}
+
+// tag turns a label name into a tag, which cannot contain spaces.
+func tag(label string) string {
+	return strings.Join(strings.Fields(label), "-")
+}
+
+func labelTags(labels []string) []string {
+	var tags []string
+	for _, l := range labels {
+		if t := tag(l); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// parseLocalDue reads dates with an optional time of day. Times without an
+// offset are read in loc.
+func parseLocalDue(s string, loc *time.Location, layouts ...string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid due date %q", s)
+}
+
+// loadLocation falls back to UTC for unknown zones, such as the Windows
+// zone names used by Microsoft.
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := models.LoadTimeZone(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,147 @@
+package transfer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestParseExport(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	meeting := time.Date(2025, 3, 14, 16, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name       string
+		source     Source
+		input      string
+		want       []*models.ListWithTodos
+		wantErrors []RowError
+	}{
+		{
+			name:   "todoist json",
+			source: SourceTodoist,
+			input: `{
+				"projects": [{"id": "1", "name": "Home"}, {"id": 2, "name": "Old", "is_deleted": true}, {"id": "3", "name": "Empty"}],
+				"items": [
+					{"project_id": "1", "content": "Water plants", "labels": ["garden"], "due": {"date": "2025-03-14"}},
+					{"project_id": "1", "content": "Call plumber", "checked": true,
+						"due": {"date": "2025-03-14T16:00:00", "timezone": "Europe/Berlin"}},
+					{"project_id": "9", "content": "Loose task"},
+					{"project_id": "1", "content": "Gone", "is_deleted": true},
+					{"project_id": "1", "content": "Bad", "due": {"date": "someday"}}
+				]
+			}`,
+			want: []*models.ListWithTodos{
+				{TodoList: models.TodoList{Name: "Home"}, Todos: []*models.Todo{
+					{Title: "Water plants", DueDate: date(2025, 3, 14), Tags: []string{"garden"}},
+					{Title: "Call plumber", DueDate: &meeting, Status: true},
+				}},
+				{TodoList: models.TodoList{Name: "Empty"}},
+				{TodoList: models.TodoList{Name: "Imported"}, Todos: []*models.Todo{{Title: "Loose task"}}},
+			},
+			wantErrors: []RowError{{Location: "items[4]", Message: `invalid due date "someday"`}},
+		},
+		{
+			name:   "todoist csv",
+			source: SourceTodoist,
+			input: "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+				"section,Weekly,,,,,,,,\n" +
+				"task,Buy milk @errand @home,Whole milk,4,1,,,2025-03-14,en,UTC\n" +
+				"\n" +
+				"task,Stretch,,1,1,,,every day,en,UTC\n" +
+				"note,Remember the receipt,,,,,,,,\n" +
+				"task,@only,,1,1,,,,en,UTC\n",
+			want: []*models.ListWithTodos{
+				{TodoList: models.TodoList{Name: "Imported"}, Todos: []*models.Todo{
+					{Title: "Buy milk", Description: "Whole milk", DueDate: date(2025, 3, 14), Tags: []string{"errand", "home"}},
+					{Title: "Stretch", Description: "Due: every day"},
+				}},
+			},
+			wantErrors: []RowError{{Location: "line 7", Message: "title is required"}},
+		},
+		{
+			name:   "trello",
+			source: SourceTrello,
+			input: `{
+				"name": "Launch",
+				"lists": [{"id": "l1"}, {"id": "l2", "closed": true}],
+				"cards": [
+					{"id": "c1", "idList": "l1", "name": "Write copy", "desc": "Landing page",
+						"due": "2025-03-14T00:00:00.000Z", "dueComplete": true,
+						"labels": [{"name": "Marketing team"}, {"name": "", "color": "red"}]},
+					{"id": "c2", "idList": "l2", "name": "In closed list"},
+					{"id": "c3", "idList": "l1", "name": "Archived", "closed": true}
+				],
+				"checklists": [
+					{"idCard": "c1", "name": "Review", "checkItems": [
+						{"name": "Proofread", "state": "complete"},
+						{"name": "Legal", "state": "incomplete", "due": "tomorrow"}
+					]}
+				]
+			}`,
+			want: []*models.ListWithTodos{
+				{TodoList: models.TodoList{Name: "Launch"}, Todos: []*models.Todo{
+					{
+						Title: "Write copy", Description: "Landing page", DueDate: date(2025, 3, 14), Status: true,
+						Tags: []string{"Marketing-team", "red"},
+					},
+					{
+						Title: "Proofread", Description: `Review on card "Write copy"`, Status: true,
+						Tags: []string{"Marketing-team", "red"},
+					},
+				}},
+			},
+			wantErrors: []RowError{{Location: "checklists[0].checkItems[1]", Message: `invalid due date "tomorrow"`}},
+		},
+		{
+			name:   "microsoft to do",
+			source: SourceMicrosoftToDo,
+			input: `[{
+				"displayName": "Groceries",
+				"tasks": [
+					{"title": "Apples", "status": "completed", "categories": ["Red category"],
+						"body": {"content": "<p>Granny Smith &amp; Gala</p>", "contentType": "html"},
+						"dueDateTime": {"dateTime": "2025-03-14T00:00:00.0000000", "timeZone": "UTC"},
+						"checklistItems": [{"displayName": "Wash", "isChecked": true}, {"displayName": "Slice"}]},
+					{"title": "  ", "status": "notStarted"}
+				]
+			}]`,
+			want: []*models.ListWithTodos{
+				{TodoList: models.TodoList{Name: "Groceries"}, Todos: []*models.Todo{
+					{
+						Title: "Apples", Description: "Granny Smith & Gala\n\n- [x] Wash\n- [ ] Slice",
+						DueDate: date(2025, 3, 14), Status: true, Tags: []string{"Red-category"},
+					},
+				}},
+			},
+			wantErrors: []RowError{{Location: "lists[0].tasks[1]", Message: "title is required"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists, rowErrors, err := ParseExport(strings.NewReader(tt.input), tt.source, "Imported")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantErrors, rowErrors)
+			assert.Equal(t, tt.want, lists)
+		})
+	}
+}
+
+func TestParseExportRejectsUnreadableFiles(t *testing.T) {
+	for _, src := range []Source{SourceTodoist, SourceTrello, SourceMicrosoftToDo} {
+		_, _, err := ParseExport(strings.NewReader("{not json"), src, "Imported")
+		assert.Error(t, err, src)
+	}
+}
@@ -0,0 +1,184 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// todoistExport is the shape of a Todoist sync, which is what backup tools
+// built on the Todoist API write.
+type todoistExport struct {
+	Projects []todoistProject `json:"projects"`
+	Items    []todoistItem    `json:"items"`
+}
+
+type todoistProject struct {
+	ID        flexibleID `json:"id"`
+	Name      string     `json:"name"`
+	IsDeleted bool       `json:"is_deleted"`
+}
+
+type todoistItem struct {
+	ProjectID   flexibleID  `json:"project_id"`
+	Content     string      `json:"content"`
+	Description string      `json:"description"`
+	Checked     bool        `json:"checked"`
+	IsDeleted   bool        `json:"is_deleted"`
+	Labels      []string    `json:"labels"`
+	Due         *todoistDue `json:"due"`
+}
+
+type todoistDue struct {
+	Date     string `json:"date"`
+	Timezone string `json:"timezone"`
+}
+
+// flexibleID accepts the numeric IDs of older Todoist API versions as well
+// as the string IDs of current ones.
+type flexibleID string
+
+func (id *flexibleID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = flexibleID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid id %s", data)
+	}
+	*id = flexibleID(n.String())
+	return nil
+}
+
+// todoistDateLayouts covers due dates with a floating time of day as well
+// as the date column of CSV exports written with an English date language.
+var todoistDateLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+}
+
+func (p *parser) parseTodoistJSON(data []byte) error {
+	var doc todoistExport
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("decode todoist export: %w", err)
+	}
+
+	projects := make(map[flexibleID]string, len(doc.Projects))
+	for i, project := range doc.Projects {
+		if project.IsDeleted {
+			continue
+		}
+		if msg := validateList(project.Name); msg != "" {
+			p.errors = append(p.errors, RowError{Location: fmt.Sprintf("projects[%d]", i), Message: msg})
+			continue
+		}
+		projects[project.ID] = project.Name
+		p.list(project.Name)
+	}
+
+	for i, item := range doc.Items {
+		if item.IsDeleted {
+			continue
+		}
+		location := RowError{Location: fmt.Sprintf("items[%d]", i)}
+		todo := &models.Todo{
+			Title:       strings.TrimSpace(item.Content),
+			Description: item.Description,
+			Status:      item.Checked,
+			Tags:        labelTags(item.Labels),
+		}
+		if item.Due != nil {
+			due, err := parseLocalDue(item.Due.Date, loadLocation(item.Due.Timezone), todoistDateLayouts...)
+			if err != nil {
+				location.Message = err.Error()
+				p.errors = append(p.errors, location)
+				continue
+			}
+			todo.DueDate = due
+		}
+		p.add(location, projects[item.ProjectID], todo)
+	}
+
+	return nil
+}
+
+// parseTodoistCSV reads the CSV export of a single project. Only rows of
+// type task are imported; labels are part of the content as @label. The
+// date column holds what the user typed, so dates that are not plain dates,
+// such as "every monday", are kept in the description instead.
+func (p *parser) parseTodoistCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("read todoist csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))] = i
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return errors.New("todoist csv header has no CONTENT column")
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				p.errors = append(p.errors, lineError(parseErr.Line, "%v", parseErr.Err))
+				continue
+			}
+			return fmt.Errorf("read todoist csv: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if typ := field("TYPE"); typ != "" && typ != "task" {
+			continue
+		}
+
+		var words, labels []string
+		for _, w := range strings.Fields(field("CONTENT")) {
+			if len(w) > 1 && strings.HasPrefix(w, "@") {
+				labels = append(labels, w[1:])
+				continue
+			}
+			words = append(words, w)
+		}
+		todo := &models.Todo{
+			Title:       strings.Join(words, " "),
+			Description: field("DESCRIPTION"),
+			Tags:        labelTags(labels),
+		}
+		if date := field("DATE"); date != "" {
+			due, err := parseLocalDue(date, loadLocation(field("TIMEZONE")), todoistDateLayouts...)
+			if err != nil {
+				todo.Description = strings.TrimSpace(todo.Description + "\n\nDue: " + date)
+			}
+			todo.DueDate = due
+		}
+		p.add(lineError(line, ""), "", todo)
+	}
+}
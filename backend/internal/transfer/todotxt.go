@@ -0,0 +1,93 @@
+package transfer
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+var todoTxtPriority = regexp.MustCompile(`^\([A-Z]\)$`)
+
+// exportTodoTxt writes one line per todo. The list becomes a +project with
+// spaces replaced by underscores. todo.txt has no descriptions and due
+// dates are written as plain dates, so both are lossy.
+func exportTodoTxt(w io.Writer, lists []*models.ListWithTodos) error {
+	bw := bufio.NewWriter(w)
+	for _, l := range lists {
+		project := "+" + strings.ReplaceAll(l.Name, " ", "_")
+		for _, t := range l.Todos {
+			var parts []string
+			if t.Status {
+				parts = append(parts, "x")
+			}
+			if !t.CreatedAt.IsZero() {
+				parts = append(parts, t.CreatedAt.UTC().Format(time.DateOnly))
+			}
+			parts = append(parts, t.Title, project)
+			switch {
+			case t.DueDate != nil:
+				parts = append(parts, "due:"+t.DueDate.UTC().Format(time.DateOnly))
+			case t.DueOn != nil:
+				parts = append(parts, "due:"+t.DueOn.String())
+			}
+			bw.WriteString(strings.Join(parts, " ") + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// parseTodoTxt reads todo.txt lines. The first +project names the list;
+// completion and creation dates and priorities are skipped.
+func (p *parser) parseTodoTxt(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		todo := &models.Todo{}
+		if fields[0] == "x" {
+			todo.Status = true
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && todoTxtPriority.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+		for i := 0; i < 2 && len(fields) > 0 && isDate(fields[0]); i++ {
+			fields = fields[1:]
+		}
+
+		var listName string
+		var title []string
+		var dueErr error
+		for _, field := range fields {
+			switch {
+			case strings.HasPrefix(field, "+") && len(field) > 1 && listName == "":
+				listName = strings.ReplaceAll(field[1:], "_", " ")
+			case strings.HasPrefix(field, "due:"):
+				todo.DueDate, todo.DueOn, dueErr = parseDue(strings.TrimPrefix(field, "due:"))
+			default:
+				title = append(title, field)
+			}
+		}
+		if dueErr != nil {
+			p.errors = append(p.errors, lineError(line, "%v", dueErr))
+			continue
+		}
+
+		todo.Title = strings.Join(title, " ")
+		p.add(lineError(line, ""), listName, todo)
+	}
+
+	return scanner.Err()
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
@@ -0,0 +1,207 @@
+// Package transfer reads and writes lists with their todos in the formats
+// supported by import and export: JSON, CSV, Markdown checklists and
+// todo.txt. It also reads the export files of Todoist, Trello and Microsoft
+// To Do.
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+	FormatTodoTxt  Format = "todotxt"
+)
+
+// maxNameLength matches the VARCHAR(255) columns of list names and titles.
+const maxNameLength = 255
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "todotxt", "todo.txt", "txt":
+		return FormatTodoTxt, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+func (f Format) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return "md"
+	case FormatTodoTxt:
+		return "txt"
+	default:
+		return string(f)
+	}
+}
+
+type RowError = models.RowError
+
+func lineError(line int, format string, args ...any) RowError {
+	return RowError{Location: fmt.Sprintf("line %d", line), Message: fmt.Sprintf(format, args...)}
+}
+
+// Export writes lists in the given format.
+func Export(w io.Writer, f Format, lists []*models.ListWithTodos) error {
+	switch f {
+	case FormatJSON:
+		return exportJSON(w, lists)
+	case FormatCSV:
+		return exportCSV(w, lists)
+	case FormatMarkdown:
+		return exportMarkdown(w, lists)
+	case FormatTodoTxt:
+		return exportTodoTxt(w, lists)
+	default:
+		return fmt.Errorf("unsupported format %q", f)
+	}
+}
+
+// Parse reads lists in the given format. Entries that fail validation are
+// left out and reported as RowErrors; err is only returned when the input
+// cannot be read at all. Todos that do not name a list go to defaultList.
+func Parse(r io.Reader, f Format, defaultList string) ([]*models.ListWithTodos, []RowError, error) {
+	p := &parser{defaultList: defaultList}
+	var err error
+	switch f {
+	case FormatJSON:
+		err = p.parseJSON(r)
+	case FormatCSV:
+		err = p.parseCSV(r)
+	case FormatMarkdown:
+		err = p.parseMarkdown(r)
+	case FormatTodoTxt:
+		err = p.parseTodoTxt(r)
+	default:
+		err = fmt.Errorf("unsupported format %q", f)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.lists, p.errors, nil
+}
+
+// parser collects todos into lists by name, preserving input order.
+type parser struct {
+	defaultList string
+	lists       []*models.ListWithTodos
+	errors      []RowError
+}
+
+func (p *parser) list(name string) *models.ListWithTodos {
+	if name == "" {
+		name = p.defaultList
+	}
+	for _, l := range p.lists {
+		if l.Name == name {
+			return l
+		}
+	}
+	l := &models.ListWithTodos{TodoList: models.TodoList{Name: name}}
+	p.lists = append(p.lists, l)
+	return l
+}
+
+// add validates todo and adds it to the named list.
+func (p *parser) add(location RowError, listName string, todo *models.Todo) {
+	if listName == "" {
+		listName = p.defaultList
+	}
+	msg := validateList(listName)
+	if msg == "" {
+		msg = validateTodo(todo)
+	}
+	if msg != "" {
+		location.Message = msg
+		p.errors = append(p.errors, location)
+		return
+	}
+	l := p.list(listName)
+	l.Todos = append(l.Todos, todo)
+}
+
+func validateList(name string) string {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return "list name is required"
+	case utf8.RuneCountInString(name) > maxNameLength:
+		return fmt.Sprintf("list name is longer than %d characters", maxNameLength)
+	}
+	return ""
+}
+
+func validateTodo(todo *models.Todo) string {
+	switch {
+	case strings.TrimSpace(todo.Title) == "":
+		return "title is required"
+	case utf8.RuneCountInString(todo.Title) > maxNameLength:
+		return fmt.Sprintf("title is longer than %d characters", maxNameLength)
+	}
+	return ""
+}
+
+// parseDue accepts RFC 3339 timestamps and plain dates, which make all-day
+// todos.
+func parseDue(s string) (*time.Time, *models.Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil, nil
+	}
+	d, err := models.ParseDate(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid due date %q", s)
+	}
+	return nil, &d, nil
+}
+
+func formatDue(t *models.Todo) string {
+	switch {
+	case t.DueDate != nil:
+		return t.DueDate.UTC().Format(time.RFC3339)
+	case t.DueOn != nil:
+		return t.DueOn.String()
+	}
+	return ""
+}
+
+// parseTags splits a space separated tag list.
+func parseTags(s string) []string {
+	tags := strings.Fields(s)
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
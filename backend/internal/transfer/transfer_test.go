@@ -0,0 +1,138 @@
+package transfer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+func sampleLists() []*models.ListWithTodos {
+	due := time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)
+	dueOn := models.Date{Year: 2025, Month: time.March, Day: 14}
+	return []*models.ListWithTodos{
+		{
+			TodoList: models.TodoList{Name: "Home chores"},
+			Todos: []*models.Todo{
+				{Title: "Water plants", Description: "Balcony first", DueOn: &dueOn, Tags: []string{"garden", "weekly"}},
+				{Title: "Take out trash", Status: true},
+			},
+		},
+		{
+			TodoList: models.TodoList{Name: "Work"},
+			Todos:    []*models.Todo{{Title: "Write report, draft", DueDate: &due}},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range []Format{FormatJSON, FormatCSV, FormatMarkdown, FormatTodoTxt} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Export(&buf, f, sampleLists()))
+
+			lists, rowErrors, err := Parse(&buf, f, "Imported")
+			require.NoError(t, err)
+			assert.Empty(t, rowErrors)
+
+			want := sampleLists()
+			switch f {
+			case FormatMarkdown:
+				want[0].Todos[0].Tags = nil
+			case FormatTodoTxt:
+				// todo.txt has neither descriptions, tags nor due times
+				want[0].Todos[0].Description = ""
+				want[0].Todos[0].Tags = nil
+				want[1].Todos[0].DueDate = nil
+				want[1].Todos[0].DueOn = &models.Date{Year: 2025, Month: time.March, Day: 17}
+			}
+			assert.Equal(t, want, lists)
+		})
+	}
+}
+
+func TestParseReportsRowErrors(t *testing.T) {
+	tests := []struct {
+		format Format
+		input  string
+		want   []RowError
+		todos  int
+	}{
+		{
+			format: FormatCSV,
+			input:  "title,due_date,status\nok,,\n,,\nbad date,tomorrow,\nbad status,,maybe\n",
+			want: []RowError{
+				{Location: "line 3", Message: "title is required"},
+				{Location: "line 4", Message: `invalid due date "tomorrow"`},
+				{Location: "line 5", Message: `invalid status "maybe"`},
+			},
+			todos: 1,
+		},
+		{
+			format: FormatJSON,
+			input:  `{"lists":[{"name":"A","todos":[{"title":"ok"},{"title":""},{"title":"x","due_date":"soon"}]},{"name":""}]}`,
+			want: []RowError{
+				{Location: "lists[0].todos[1]", Message: "title is required"},
+				{Location: "lists[0].todos[2]", Message: `invalid due date "soon"`},
+				{Location: "lists[1]", Message: "list name is required"},
+			},
+			todos: 1,
+		},
+		{
+			format: FormatMarkdown,
+			input:  "Some intro text.\n\n# List\n- [ ] ok\n- [x]\n- [ ] late (due 2025-13-01)\n",
+			want: []RowError{
+				{Location: "line 5", Message: "title is required"},
+				{Location: "line 6", Message: `invalid due date "2025-13-01"`},
+			},
+			todos: 1,
+		},
+		{
+			format: FormatTodoTxt,
+			input:  "(A) 2025-03-01 call mom +Family due:2025-03-02\nx 2025-03-02 2025-03-01 +Family\n",
+			want:   []RowError{{Location: "line 2", Message: "title is required"}},
+			todos:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			lists, rowErrors, err := Parse(strings.NewReader(tt.input), tt.format, "Imported")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rowErrors)
+
+			todos := 0
+			for _, l := range lists {
+				todos += len(l.Todos)
+			}
+			assert.Equal(t, tt.todos, todos)
+		})
+	}
+}
+
+func TestParseTodoTxtFields(t *testing.T) {
+	lists, rowErrors, err := Parse(strings.NewReader(
+		"(B) 2025-03-01 Call mom @phone +Family_stuff due:2025-03-02\nno project here\n",
+	), FormatTodoTxt, "Inbox")
+	require.NoError(t, err)
+	require.Empty(t, rowErrors)
+	require.Len(t, lists, 2)
+
+	assert.Equal(t, "Family stuff", lists[0].Name)
+	assert.Equal(t, "Call mom @phone", lists[0].Todos[0].Title)
+	assert.Equal(t, "2025-03-02", lists[0].Todos[0].DueOn.String())
+	assert.Equal(t, "Inbox", lists[1].Name)
+}
+
+func TestParseRejectsUnreadableInput(t *testing.T) {
+	_, _, err := Parse(strings.NewReader("{"), FormatJSON, "Imported")
+	assert.Error(t, err)
+
+	_, _, err = Parse(strings.NewReader("name,due\n"), FormatCSV, "Imported")
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/awnzl/to-do-app/internal/models"
+)
+
+// trelloBoard is the JSON export of a Trello board.
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloList struct {
+	ID     string `json:"id"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	ID          string        `json:"id"`
+	IDList      string        `json:"idList"`
+	Name        string        `json:"name"`
+	Desc        string        `json:"desc"`
+	Due         string        `json:"due"`
+	DueComplete bool          `json:"dueComplete"`
+	Closed      bool          `json:"closed"`
+	Labels      []trelloLabel `json:"labels"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloChecklist struct {
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Due   string `json:"due"`
+}
+
+// parseTrello imports a board as one list. Cards become todos followed by
+// their checklist items, which inherit the card's labels. Archived cards
+// and the cards of archived lists are skipped.
+func (p *parser) parseTrello(data []byte) error {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return fmt.Errorf("decode trello board: %w", err)
+	}
+	name := board.Name
+	if name == "" {
+		name = p.defaultList
+	}
+	if msg := validateList(name); msg != "" {
+		return fmt.Errorf("board: %s", msg)
+	}
+	p.list(name)
+
+	closedLists := make(map[string]bool)
+	for _, l := range board.Lists {
+		if l.Closed {
+			closedLists[l.ID] = true
+		}
+	}
+	checklists := make(map[string][]int)
+	for i, cl := range board.Checklists {
+		checklists[cl.IDCard] = append(checklists[cl.IDCard], i)
+	}
+
+	for i, card := range board.Cards {
+		if card.Closed || closedLists[card.IDList] {
+			continue
+		}
+		var labels []string
+		for _, l := range card.Labels {
+			if l.Name != "" {
+				labels = append(labels, l.Name)
+			} else {
+				labels = append(labels, l.Color)
+			}
+		}
+		tags := labelTags(labels)
+
+		location := RowError{Location: fmt.Sprintf("cards[%d]", i)}
+		due, dueOn, err := parseDue(card.Due)
+		if err != nil {
+			location.Message = err.Error()
+			p.errors = append(p.errors, location)
+			continue
+		}
+		p.add(location, name, &models.Todo{
+			Title:       strings.TrimSpace(card.Name),
+			Description: card.Desc,
+			DueDate:     due,
+			DueOn:       dueOn,
+			Status:      card.DueComplete,
+			Tags:        tags,
+		})
+
+		for _, ci := range checklists[card.ID] {
+			cl := board.Checklists[ci]
+			for j, item := range cl.CheckItems {
+				location := RowError{Location: fmt.Sprintf("checklists[%d].checkItems[%d]", ci, j)}
+				due, dueOn, err := parseDue(item.Due)
+				if err != nil {
+					location.Message = err.Error()
+					p.errors = append(p.errors, location)
+					continue
+				}
+				p.add(location, name, &models.Todo{
+					Title:       strings.TrimSpace(item.Name),
+					Description: fmt.Sprintf("%s on card %q", cl.Name, card.Name),
+					DueDate:     due,
+					DueOn:       dueOn,
+					Status:      item.State == "complete",
+					Tags:        tags,
+				})
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_todos_updated_at ON todos;
+DROP FUNCTION IF EXISTS update_updated_at_column;
+DROP TABLE IF EXISTS todos;
+DROP TABLE IF EXISTS todo_lists;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_preferences;
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,14 @@
+CREATE TABLE feed_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- only the SHA-256 of the secret token is stored
+    token_hash BYTEA NOT NULL UNIQUE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    list_id UUID REFERENCES todo_lists(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((user_id IS NULL) <> (list_id IS NULL))
+);
+
+-- Create indexes
+CREATE INDEX idx_feed_tokens_user_id ON feed_tokens(user_id);
+CREATE INDEX idx_feed_tokens_list_id ON feed_tokens(list_id);
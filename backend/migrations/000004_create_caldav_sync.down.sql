@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS caldav_resources;
+DROP TRIGGER IF EXISTS log_todos_change ON todos;
+DROP FUNCTION IF EXISTS log_todo_change;
+DROP TABLE IF EXISTS todo_changes;
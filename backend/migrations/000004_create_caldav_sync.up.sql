@@ -0,0 +1,42 @@
+-- Append-only log of todo changes per list. It backs CalDAV ctags and
+-- sync-collection tokens, so it also records deletions and moves: a todo
+-- that leaves a list is logged as deleted there.
+CREATE TABLE todo_changes (
+    seq BIGSERIAL PRIMARY KEY,
+    list_id UUID NOT NULL,
+    todo_id UUID NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION log_todo_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted) VALUES (OLD.list_id, OLD.id, TRUE);
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.list_id IS DISTINCT FROM NEW.list_id THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted) VALUES (OLD.list_id, OLD.id, TRUE);
+    END IF;
+    INSERT INTO todo_changes (list_id, todo_id) VALUES (NEW.list_id, NEW.id);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER log_todos_change
+    AFTER INSERT OR UPDATE OR DELETE ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION log_todo_change();
+
+-- Resource names and UIDs chosen by CalDAV clients. Rows are kept after the
+-- todo is deleted so sync reports can name the removed resource.
+CREATE TABLE caldav_resources (
+    todo_id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    uid VARCHAR(255) NOT NULL
+);
+
+-- Create indexes
+CREATE INDEX idx_todo_changes_list_id_seq ON todo_changes(list_id, seq);
+CREATE INDEX idx_caldav_resources_name ON caldav_resources(name);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS import_jobs;
+ALTER TABLE todos DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,35 @@
+ALTER TABLE todos
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(32) NOT NULL,
+    list_name VARCHAR(255) NOT NULL,
+    -- SHA-256 of the uploaded file, used to make reruns idempotent
+    file_hash BYTEA NOT NULL,
+    -- the uploaded file, cleared once the job has finished
+    payload BYTEA,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    created_lists INTEGER NOT NULL DEFAULT 0,
+    created_todos INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TRIGGER update_import_jobs_updated_at
+    BEFORE UPDATE ON import_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create indexes
+CREATE INDEX idx_todos_tags ON todos USING GIN (tags);
+CREATE INDEX idx_import_jobs_status ON import_jobs(status, created_at);
+-- a file is only imported once per user and source unless the job failed
+CREATE UNIQUE INDEX idx_import_jobs_file ON import_jobs(owner_id, source, file_hash)
+    WHERE status <> 'failed';
@@ -0,0 +1,37 @@
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    -- hex encoded SHA-256 of the content
+    sha256 CHAR(64) NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Blobs of deleted attachments, removed from the blob store by a background
+-- worker. Filled by a trigger so that cascading deletes of todos and lists
+-- are covered as well.
+CREATE TABLE blob_deletions (
+    id BIGSERIAL PRIMARY KEY,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION queue_blob_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO blob_deletions (storage_key) VALUES (OLD.storage_key);
+    RETURN OLD;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER queue_attachment_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW
+    EXECUTE FUNCTION queue_blob_deletion();
+
+-- Create indexes
+CREATE INDEX idx_attachments_todo_id ON attachments(todo_id);
+CREATE INDEX idx_attachments_storage_key ON attachments(storage_key);
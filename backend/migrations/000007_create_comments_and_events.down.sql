@@ -0,0 +1,5 @@
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS mention_emails;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS comment_mentions;
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS comments;
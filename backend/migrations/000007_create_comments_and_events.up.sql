@@ -0,0 +1,48 @@
+CREATE TABLE comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- Markdown; emptied when the comment is deleted so replies keep their
+    -- place in the thread
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    edited_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Previous bodies of edited comments
+CREATE TABLE comment_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    written_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    replaced_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE comment_mentions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- Outbox of things users should hear about, written in the transaction of
+-- the change and consumed by the notifier
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    todo_id UUID NOT NULL,
+    comment_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE notification_preferences
+    ADD COLUMN mention_emails BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Create indexes
+CREATE INDEX idx_comments_todo_id ON comments(todo_id, created_at);
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions(comment_id);
+CREATE INDEX idx_events_pending ON events(id) WHERE processed_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todo_assignees;
+DROP TABLE IF EXISTS list_members;
@@ -0,0 +1,19 @@
+-- Users a list is shared with, besides its owner
+CREATE TABLE list_members (
+    list_id UUID NOT NULL REFERENCES todo_lists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (list_id, user_id)
+);
+
+CREATE TABLE todo_assignees (
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (todo_id, user_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_list_members_user_id ON list_members(user_id);
+CREATE INDEX idx_todo_assignees_user_id ON todo_assignees(user_id);
@@ -0,0 +1,16 @@
+-- Saved views: named filter queries evaluated against the todos a user can
+-- access
+CREATE TABLE views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE TRIGGER update_views_updated_at
+    BEFORE UPDATE ON views
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS recurrence,
+    DROP COLUMN IF EXISTS priority;
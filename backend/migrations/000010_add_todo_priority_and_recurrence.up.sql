@@ -0,0 +1,4 @@
+ALTER TABLE todos
+    ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 3),
+    -- iCalendar RRULE value, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+    ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE todos
+    DROP CONSTRAINT IF EXISTS todos_due_check,
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS due_on;
@@ -0,0 +1,6 @@
+ALTER TABLE todos
+    -- all-day todos have a due day instead of a due instant
+    ADD COLUMN due_on DATE,
+    -- IANA time zone the due day is evaluated in, the list owner's when empty
+    ADD COLUMN time_zone TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT todos_due_check CHECK (due_date IS NULL OR due_on IS NULL);
@@ -0,0 +1,3 @@
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS scheduled_for,
+    DROP COLUMN IF EXISTS start_date;
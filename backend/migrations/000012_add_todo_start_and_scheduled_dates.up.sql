@@ -0,0 +1,4 @@
+ALTER TABLE todos
+    -- todos are hidden from the agenda before their start date
+    ADD COLUMN start_date DATE,
+    ADD COLUMN scheduled_for DATE;
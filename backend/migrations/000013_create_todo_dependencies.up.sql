@@ -0,0 +1,11 @@
+-- todo_id cannot be completed before blocked_by is
+CREATE TABLE todo_dependencies (
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    blocked_by UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (todo_id, blocked_by),
+    CHECK (todo_id <> blocked_by)
+);
+
+-- Create indexes
+CREATE INDEX idx_todo_dependencies_blocked_by ON todo_dependencies(blocked_by);
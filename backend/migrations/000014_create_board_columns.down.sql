@@ -0,0 +1,4 @@
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS rank,
+    DROP COLUMN IF EXISTS column_id;
+DROP TABLE IF EXISTS board_columns;
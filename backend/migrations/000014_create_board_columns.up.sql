@@ -0,0 +1,21 @@
+-- Kanban columns of a list, in position order
+CREATE TABLE board_columns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    list_id UUID NOT NULL REFERENCES todo_lists(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    -- most todos the column may hold, unlimited when NULL
+    wip_limit INTEGER CHECK (wip_limit > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (list_id, name)
+);
+
+-- todos without a column are not on the board yet; rank orders them within
+-- their column
+ALTER TABLE todos
+    ADD COLUMN column_id UUID REFERENCES board_columns(id) ON DELETE SET NULL,
+    ADD COLUMN rank INTEGER NOT NULL DEFAULT 0;
+
+-- Create indexes
+CREATE INDEX idx_board_columns_list_id ON board_columns(list_id);
+CREATE INDEX idx_todos_column_id ON todos(column_id);
@@ -0,0 +1,7 @@
+ALTER TABLE todo_lists
+    DROP CONSTRAINT IF EXISTS todo_lists_folder_needs_workspace,
+    DROP COLUMN IF EXISTS folder_id,
+    DROP COLUMN IF EXISTS workspace_id;
+DROP TABLE IF EXISTS folders;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,37 @@
+-- Workspaces group the lists of a team; deleting one only marks it deleted
+-- so that it can be restored
+CREATE TABLE workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Members of a workspace can access all of its lists
+CREATE TABLE workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- Folders group the lists within a workspace
+CREATE TABLE folders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (workspace_id, name)
+);
+
+ALTER TABLE todo_lists
+    ADD COLUMN workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE,
+    ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL,
+    ADD CONSTRAINT todo_lists_folder_needs_workspace CHECK (folder_id IS NULL OR workspace_id IS NOT NULL);
+
+-- Create indexes
+CREATE INDEX idx_workspaces_owner_id ON workspaces(owner_id);
+CREATE INDEX idx_workspace_members_user_id ON workspace_members(user_id);
+CREATE INDEX idx_todo_lists_workspace_id ON todo_lists(workspace_id);
+CREATE INDEX idx_todo_lists_folder_id ON todo_lists(folder_id);
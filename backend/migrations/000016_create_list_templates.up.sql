@@ -0,0 +1,17 @@
+-- Saved list templates; names and todos may contain {{placeholders}} that
+-- are filled in when a list is created from the template
+CREATE TABLE list_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    list_name VARCHAR(255) NOT NULL,
+    todos JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE TRIGGER update_list_templates_updated_at
+    BEFORE UPDATE ON list_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
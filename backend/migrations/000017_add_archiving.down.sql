@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS track_todos_completion ON todos;
+DROP FUNCTION IF EXISTS track_todo_completion();
+ALTER TABLE todos
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE todo_lists
+    DROP COLUMN IF EXISTS auto_archive_days,
+    DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,40 @@
+-- Archived lists are hidden from listings, and lists with an auto-archive
+-- policy archive todos completed more than that many days ago
+ALTER TABLE todo_lists
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN auto_archive_days INTEGER CHECK (auto_archive_days > 0);
+
+ALTER TABLE todos
+    ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Todos completed before completion times were kept count as completed at
+-- their last change; triggers are disabled so that this keeps updated_at
+-- and the change log as they are
+ALTER TABLE todos DISABLE TRIGGER USER;
+UPDATE todos SET completed_at = updated_at WHERE status;
+ALTER TABLE todos ENABLE TRIGGER USER;
+
+-- Completing a todo records when; reopening it clears that and takes it
+-- out of the archive
+CREATE OR REPLACE FUNCTION track_todo_completion()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NOT NEW.status THEN
+        NEW.completed_at = NULL;
+        NEW.archived_at = NULL;
+    ELSIF NEW.completed_at IS NULL THEN
+        NEW.completed_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER track_todos_completion
+    BEFORE INSERT OR UPDATE ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION track_todo_completion();
+
+-- Create indexes
+CREATE INDEX idx_todos_unarchived_completed ON todos(list_id, completed_at)
+    WHERE status AND archived_at IS NULL;
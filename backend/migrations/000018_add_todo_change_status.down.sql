@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS idx_todo_changes_todo_id_seq;
+
+CREATE OR REPLACE FUNCTION log_todo_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted) VALUES (OLD.list_id, OLD.id, TRUE);
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.list_id IS DISTINCT FROM NEW.list_id THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted) VALUES (OLD.list_id, OLD.id, TRUE);
+    END IF;
+    INSERT INTO todo_changes (list_id, todo_id) VALUES (NEW.list_id, NEW.id);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+ALTER TABLE todo_changes
+    DROP COLUMN IF EXISTS status;
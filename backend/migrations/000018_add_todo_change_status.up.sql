@@ -0,0 +1,28 @@
+-- The change log records whether the todo was done after each change, so
+-- that the open and done todos of a list can be counted at any time
+ALTER TABLE todo_changes
+    ADD COLUMN status BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Entries written before take the status from when the todo was completed
+UPDATE todo_changes c
+SET status = t.completed_at <= c.changed_at
+FROM todos t
+WHERE t.id = c.todo_id AND t.completed_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION log_todo_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted, status) VALUES (OLD.list_id, OLD.id, TRUE, OLD.status);
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD.list_id IS DISTINCT FROM NEW.list_id THEN
+        INSERT INTO todo_changes (list_id, todo_id, deleted, status) VALUES (OLD.list_id, OLD.id, TRUE, OLD.status);
+    END IF;
+    INSERT INTO todo_changes (list_id, todo_id, status) VALUES (NEW.list_id, NEW.id, NEW.status);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Create indexes
+CREATE INDEX idx_todo_changes_todo_id_seq ON todo_changes(todo_id, seq);
@@ -0,0 +1,3 @@
+ALTER TABLE caldav_resources DROP CONSTRAINT caldav_resources_list_id_name_key;
+ALTER TABLE caldav_resources DROP COLUMN list_id;
+CREATE INDEX idx_caldav_resources_name ON caldav_resources(name);
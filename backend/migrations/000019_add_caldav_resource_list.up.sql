@@ -0,0 +1,30 @@
+-- Resource names are chosen per calendar collection, so they are kept with
+-- the list the client created them in and are unique there.
+ALTER TABLE caldav_resources ADD COLUMN list_id UUID;
+
+UPDATE caldav_resources r
+SET list_id = COALESCE(
+    (SELECT t.list_id FROM todos t WHERE t.id = r.todo_id),
+    (SELECT c.list_id FROM todo_changes c WHERE c.todo_id = r.todo_id ORDER BY c.seq DESC LIMIT 1)
+);
+
+DELETE FROM caldav_resources WHERE list_id IS NULL;
+
+-- a name taken twice in a list stays with the todo that still exists
+DELETE FROM caldav_resources
+WHERE todo_id IN (
+    SELECT todo_id
+    FROM (
+        SELECT r.todo_id, ROW_NUMBER() OVER (
+            PARTITION BY r.list_id, r.name
+            ORDER BY EXISTS (SELECT 1 FROM todos t WHERE t.id = r.todo_id) DESC, r.todo_id
+        ) AS n
+        FROM caldav_resources r
+    ) ranked
+    WHERE n > 1
+);
+
+ALTER TABLE caldav_resources ALTER COLUMN list_id SET NOT NULL;
+
+DROP INDEX idx_caldav_resources_name;
+ALTER TABLE caldav_resources ADD CONSTRAINT caldav_resources_list_id_name_key UNIQUE (list_id, name);
@@ -0,0 +1,4 @@
+ALTER TABLE import_jobs
+    DROP COLUMN attempts,
+    DROP COLUMN heartbeat_at,
+    DROP COLUMN claimed_at;
@@ -0,0 +1,10 @@
+-- a running job is claimed by the worker that keeps its heartbeat fresh;
+-- jobs whose worker died are claimed again once the heartbeat is stale
+ALTER TABLE import_jobs
+    ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN heartbeat_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+UPDATE import_jobs
+SET claimed_at = updated_at, heartbeat_at = updated_at, attempts = 1
+WHERE status = 'running';